@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"claude-pilot/core/api"
 	"claude-pilot/internal/ui"
 
 	"github.com/spf13/cobra"
@@ -46,16 +47,23 @@ Examples:
 			os.Exit(1)
 		}
 
+		// Headless runs have no terminal, only their log
+		if sess.Backend == api.HeadlessBackend {
+			fmt.Println(ui.WarningMsg(fmt.Sprintf("Session '%s' is a headless run and cannot be attached to", sess.Name)))
+			fmt.Println(ui.InfoMsg("You can follow it with: claude-pilot logs -f " + sess.Name))
+			os.Exit(1)
+		}
+
 		// Check if session is running
 		if !ctx.Client.IsSessionRunning(sess.Name) {
 			fmt.Println(ui.WarningMsg(fmt.Sprintf("Session '%s' is not running. It may have been terminated.", sess.Name)))
-			fmt.Println(ui.InfoMsg("You can recreate it with: claude-pilot create " + sess.Name))
+			fmt.Println(ui.InfoMsg("You can resume it with: claude-pilot resume " + sess.Name))
 			os.Exit(1)
 		}
 
 		// Show session info
 		fmt.Println(ui.InfoMsg(fmt.Sprintf("Attaching to session '%s' (%s backend)...", sess.Name, ctx.Client.GetBackend())))
-		fmt.Println(ui.InfoMsg("Use your multiplexer's detach key to exit (tmux: Ctrl+B,D)"))
+		fmt.Println(ui.InfoMsg(fmt.Sprintf("Use your multiplexer's detach key to exit (%s)", detachHint(ctx.Client.GetBackend()))))
 		fmt.Println()
 
 		// Attach to the session
@@ -68,6 +76,20 @@ Examples:
 	},
 }
 
+// detachHint returns the detach key sequence for a multiplexer backend
+func detachHint(backend string) string {
+	switch backend {
+	case "zellij":
+		return "zellij: Ctrl+O,D"
+	case "screen":
+		return "screen: Ctrl+A,D"
+	case "native":
+		return "native: Ctrl+\\"
+	default:
+		return "tmux: Ctrl+B,D"
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(attachCmd)
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [session-name-or-id]",
+	Short: "Print the screen of a session",
+	Long: `Print what a session's pane shows without attaching to it, e.g. to read
+the agent's last answer from a script or CI job. By default the visible
+screen of the pane Claude runs in is printed as plain text.
+
+Examples:
+  claude-pilot capture my-session                  # Visible screen as text
+  claude-pilot capture my-session -n 500           # Also 500 lines of scrollback
+  claude-pilot capture my-session --history        # The whole scrollback
+  claude-pilot capture my-session -t 1.1 --ansi    # Another pane, with colors
+  claude-pilot capture my-session -f html -o screen.html`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		target, _ := cmd.Flags().GetString("target")
+		lines, _ := cmd.Flags().GetInt("lines")
+		history, _ := cmd.Flags().GetBool("history")
+		keepANSI, _ := cmd.Flags().GetBool("ansi")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		if format != "text" && format != "html" {
+			HandleError(fmt.Errorf("unknown format '%s', use text or html", format), "capture session")
+		}
+		if history {
+			lines = -1
+		}
+
+		opts := api.CaptureOptions{
+			Target: target,
+			Lines:  lines,
+			// HTML is rendered from the colors
+			ANSI: keepANSI || format == "html",
+		}
+
+		screen, err := ctx.Client.Capture(args[0], opts)
+		if err != nil {
+			HandleError(err, "capture session")
+		}
+
+		content := screen
+		if format == "html" {
+			content = api.CaptureHTML(args[0], screen)
+		}
+
+		if output == "" {
+			fmt.Print(content)
+			return
+		}
+		if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+			HandleError(err, "write capture")
+		}
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Capture of '%s' written to %s", args[0], output)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(captureCmd)
+
+	// Add flags
+	captureCmd.Flags().StringP("target", "t", "", "Window or pane to capture, e.g. 1 or 1.2 (default: the first pane)")
+	captureCmd.Flags().IntP("lines", "n", 0, "Lines of scrollback to include above the visible screen")
+	captureCmd.Flags().Bool("history", false, "Include the whole scrollback")
+	captureCmd.Flags().Bool("ansi", false, "Keep colors as ANSI escape sequences")
+	captureCmd.Flags().StringP("format", "f", "text", "Output format: text or html")
+	captureCmd.Flags().StringP("output", "o", "", "Write the capture to a file")
+}
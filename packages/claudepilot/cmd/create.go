@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"claude-pilot/core/api"
@@ -17,13 +19,39 @@ var createCmd = &cobra.Command{
 	Long: `Create a new Claude coding session with an optional name.
 If no name is provided, a timestamp-based name will be generated.
 
-You can also attach to existing sessions as new panes or windows/tabs.
+You can also attach to existing sessions as new panes or windows/tabs. These
+are listed under the session and can be killed or resumed on their own.
+
+With --worktree the session gets its own git worktree of the project's
+repository, on a new or existing branch named after the session or given
+as --worktree=<branch>, so that agents do not share a working tree.
+
+Claude's options, such as --model or --allowed-tools, are kept with the
+session and used again when it is resumed.
+
+With --pane the session's window gets more panes next to Claude, e.g. a
+shell or a test watcher, laid out with tmux.default_layout. They are created
+again when the session is resumed. Templates can also split and size the
+panes, which then keep that arrangement instead of the layout.
+
+With --template the session starts from a template of the config file's
+templates section; flags given as well win over the template's values.
+See 'claude-pilot templates list'.
 
 Examples:
   claude-pilot create                              # Create session with auto-generated name
   claude-pilot create my-project                   # Create session named "my-project"
   claude-pilot create --desc "React app"           # Create session with description
   claude-pilot create --project ./src              # Create session with project path
+  claude-pilot create api --tag backend            # Create session tagged "backend"
+  claude-pilot create api --log                    # Log the session's output
+  claude-pilot create api --worktree               # Run on branch "api" in a new worktree
+  claude-pilot create fix --worktree=bugfix/login  # Run in a worktree of branch bugfix/login
+  claude-pilot create api --model opus --permission-mode plan  # Start Claude with options
+  claude-pilot create api --allowed-tools "Bash(go test:*)",Edit --add-dir ../shared
+  claude-pilot create api --pane "" --pane "go test ./... -count=1"  # Add a shell and a test pane
+  claude-pilot create --template reviewer          # Create session from the "reviewer" template
+  claude-pilot create api -T reviewer --model sonnet  # Template with a different model
   claude-pilot create --attach-to main --as-pane   # Create as new pane in 'main' session
   claude-pilot create --attach-to main --as-window # Create as new window in 'main' session
   claude-pilot create debug --attach-to main --as-pane --split h  # Create horizontal pane split
@@ -49,6 +77,36 @@ Examples:
 		asPane, _ := cmd.Flags().GetBool("as-pane")
 		asWindow, _ := cmd.Flags().GetBool("as-window")
 		splitDirection, _ := cmd.Flags().GetString("split")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		templateName, _ := cmd.Flags().GetString("template")
+		paneCommands, _ := cmd.Flags().GetStringArray("pane")
+		branch, _ := cmd.Flags().GetString("worktree")
+		if branch == sessionNameBranch {
+			branch = ""
+		}
+
+		// Without --worktree or --no-worktree the template decides
+		var useWorktree *bool
+		if cmd.Flags().Changed("worktree") || cmd.Flags().Changed("no-worktree") {
+			noWorktree, _ := cmd.Flags().GetBool("no-worktree")
+			if cmd.Flags().Changed("worktree") && noWorktree {
+				HandleError(fmt.Errorf("--worktree and --no-worktree cannot be used together"), "parse worktree flags")
+			}
+			worktree := !noWorktree
+			useWorktree = &worktree
+		}
+
+		// Without --log the session_logs setting decides
+		var logOutput *bool
+		if cmd.Flags().Changed("log") {
+			logFlag, _ := cmd.Flags().GetBool("log")
+			logOutput = &logFlag
+		}
+
+		launch, err := launchOptionsFromFlags(cmd)
+		if err != nil {
+			HandleError(err, "parse launch options")
+		}
 
 		// Validate attachment flags
 		if err := validateAttachmentFlags(attachTo, asPane, asWindow); err != nil {
@@ -68,9 +126,9 @@ Examples:
 			}
 		}
 
-		// Parse split direction
+		// Parse split direction, which templates may set unless given
 		var splitDir interfaces.SplitDirection
-		if splitDirection != "" {
+		if cmd.Flags().Changed("split") {
 			switch strings.ToLower(splitDirection) {
 			case "h", "horizontal":
 				splitDir = interfaces.SplitHorizontal
@@ -79,22 +137,47 @@ Examples:
 			default:
 				HandleError(fmt.Errorf("invalid split direction '%s', use 'h' or 'v'", splitDirection), "parse split direction")
 			}
-		} else {
-			splitDir = interfaces.SplitVertical // Default to vertical split
 		}
 
-		// Resolve project path using common function
-		projectPath = GetProjectPath(projectPath)
-
-		// Create the session
-		sess, err := ctx.Client.CreateSession(api.CreateSessionRequest{
+		req := api.CreateSessionRequest{
 			Name:           sessionName,
 			Description:    description,
 			ProjectPath:    projectPath,
 			AttachTo:       attachTo,
 			AttachmentType: attachmentType,
 			SplitDirection: splitDir,
-		})
+			Tags:           tags,
+			LogOutput:      logOutput,
+			Worktree:       useWorktree,
+			Branch:         branch,
+			Launch:         launch,
+		}
+		for _, command := range paneCommands {
+			req.Blueprint = append(req.Blueprint, api.BlueprintPane{Command: command})
+		}
+		if templateName != "" {
+			if err := ctx.Client.ApplyTemplate(templateName, &req); err != nil {
+				HandleError(err, "apply template")
+			}
+		}
+		if req.SplitDirection == "" {
+			req.SplitDirection = interfaces.SplitVertical // Default to vertical split
+		}
+
+		// Resolve project path using common function
+		req.ProjectPath = GetProjectPath(req.ProjectPath)
+
+		// An inactive session with this name can be brought back instead
+		if req.Name != "" {
+			if existing, err := ctx.Client.GetSession(req.Name); err == nil && existing.Status == api.StatusInactive {
+				fmt.Println(ui.WarningMsg(fmt.Sprintf("Session '%s' already exists but is not running", req.Name)))
+				fmt.Println(ui.InfoMsg("You can resume it with: claude-pilot resume " + req.Name))
+				os.Exit(1)
+			}
+		}
+
+		// Create the session
+		sess, err := ctx.Client.CreateSession(req)
 		if err != nil {
 			HandleError(err, "create session")
 		}
@@ -116,6 +199,41 @@ Examples:
 	},
 }
 
+// sessionNameBranch is the value of a bare --worktree, whose branch is named
+// after the session
+const sessionNameBranch = "-"
+
+// launchOptionsFromFlags returns the Claude options given as flags, nil when
+// there are none. Paths are made absolute since Claude runs in the project.
+func launchOptionsFromFlags(cmd *cobra.Command) (*api.LaunchOptions, error) {
+	opts := &api.LaunchOptions{}
+	opts.Model, _ = cmd.Flags().GetString("model")
+	opts.PermissionMode, _ = cmd.Flags().GetString("permission-mode")
+	opts.AllowedTools, _ = cmd.Flags().GetStringSlice("allowed-tools")
+	opts.DisallowedTools, _ = cmd.Flags().GetStringSlice("disallowed-tools")
+	opts.MCPConfig, _ = cmd.Flags().GetString("mcp-config")
+	opts.AppendSystemPrompt, _ = cmd.Flags().GetString("append-system-prompt")
+	opts.AddDirs, _ = cmd.Flags().GetStringSlice("add-dir")
+	opts.Args, _ = cmd.Flags().GetStringArray("arg")
+
+	if len(opts.CommandArgs()) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	if opts.MCPConfig != "" {
+		if opts.MCPConfig, err = filepath.Abs(opts.MCPConfig); err != nil {
+			return nil, fmt.Errorf("failed to resolve MCP config path: %w", err)
+		}
+	}
+	for i, dir := range opts.AddDirs {
+		if opts.AddDirs[i], err = filepath.Abs(dir); err != nil {
+			return nil, fmt.Errorf("failed to resolve directory '%s': %w", dir, err)
+		}
+	}
+	return opts, nil
+}
+
 // validateAttachmentFlags validates the attachment-related flags
 func validateAttachmentFlags(attachTo string, asPane, asWindow bool) error {
 	if attachTo == "" && (asPane || asWindow) {
@@ -135,10 +253,27 @@ func init() {
 	// Add flags
 	createCmd.Flags().StringP("description", "d", "", "Description for the session")
 	createCmd.Flags().StringP("project", "p", "", "Project path for the session (defaults to current directory)")
+	createCmd.Flags().StringP("template", "T", "", "Start from a session template of the config file")
 
 	// Attachment flags
 	createCmd.Flags().StringP("attach-to", "a", "", "Attach to existing session (session name)")
 	createCmd.Flags().Bool("as-pane", false, "Create as new pane in existing session")
 	createCmd.Flags().Bool("as-window", false, "Create as new window/tab in existing session")
 	createCmd.Flags().String("split", "v", "Split direction for panes: 'h' (horizontal) or 'v' (vertical)")
+	createCmd.Flags().StringSliceP("tag", "t", nil, "Tag the session, e.g. to send input to all sessions with a tag (repeatable)")
+	createCmd.Flags().Bool("log", false, "Log the session's output, or --log=false not to (default: session_logs.enabled)")
+	createCmd.Flags().String("worktree", "", "Run in a new git worktree, on the session's branch or --worktree=<branch>")
+	createCmd.Flags().Lookup("worktree").NoOptDefVal = sessionNameBranch
+	createCmd.Flags().Bool("no-worktree", false, "Run in the project directory even if the template uses a worktree")
+	createCmd.Flags().StringArray("pane", nil, "Add a pane running a command next to Claude, \"\" for a shell (repeatable, tmux only)")
+
+	// Claude launch options
+	createCmd.Flags().String("model", "", "Model for Claude, e.g. opus or sonnet")
+	createCmd.Flags().String("permission-mode", "", "Permission mode for Claude: default, acceptEdits, plan or bypassPermissions")
+	createCmd.Flags().StringSlice("allowed-tools", nil, "Tools Claude may use without asking, e.g. \"Bash(git log:*)\",Edit (repeatable)")
+	createCmd.Flags().StringSlice("disallowed-tools", nil, "Tools Claude may not use (repeatable)")
+	createCmd.Flags().String("mcp-config", "", "MCP servers config file for Claude")
+	createCmd.Flags().String("append-system-prompt", "", "Text appended to Claude's system prompt")
+	createCmd.Flags().StringSlice("add-dir", nil, "Additional directory Claude may access (repeatable)")
+	createCmd.Flags().StringArray("arg", nil, "Other argument passed on to claude, e.g. --arg --verbose (repeatable)")
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"claude-pilot/core/api"
 	"claude-pilot/internal/ui"
 	"claude-pilot/shared/interfaces"
 
@@ -31,6 +32,7 @@ Examples:
 			if err != nil {
 				HandleError(err, "list sessions")
 			}
+			ctx.Client.WithAgentState(sessions)
 
 			for _, session := range sessions {
 				listDetails(ctx, session)
@@ -51,7 +53,7 @@ Examples:
 		identifier := args[0]
 
 		// Get the session
-		sess, err := ctx.Client.GetSession(identifier)
+		sess, err := ctx.Client.GetSessionDetails(identifier)
 		if err != nil {
 			HandleError(err, "get session")
 		}
@@ -59,7 +61,14 @@ Examples:
 		// Show enhanced session details
 		listDetails(ctx, sess)
 
-		// Show enhanced next steps
+		// Show enhanced next steps; headless runs have nothing to attach to
+		if sess.Backend == api.HeadlessBackend {
+			fmt.Println(ui.NextSteps(
+				fmt.Sprintf("claude-pilot logs %s", sess.Name),
+				"claude-pilot list",
+			))
+			return
+		}
 		fmt.Println(ui.NextSteps(
 			fmt.Sprintf("claude-pilot attach %s", sess.Name),
 			"claude-pilot list",
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var fanoutCmd = &cobra.Command{
+	Use:   "fanout [group-name]",
+	Short: "Run one task in several parallel sessions",
+	Long: `Start the same task in several Claude sessions at once, to pick the best of
+their attempts. Each session runs in its own git worktree on a new branch
+from the project's HEAD, and all of them are linked under a group. The
+sessions and branches are named <group>-1 to <group>-N; without a group name
+one is generated. Branches left by an earlier group of the same name must be
+deleted first.
+
+Compare the attempts with 'claude-pilot fanout status <group>'.
+
+Examples:
+  claude-pilot fanout -n 3 --prompt-file task.md          # Three attempts at a task
+  claude-pilot fanout login-fix --prompt "Fix the login"  # Group named login-fix
+  claude-pilot fanout -n 2 --prompt-file - < task.md      # Prompt from stdin`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get command-specific parameters
+		var group string
+		if len(args) > 0 {
+			group = args[0]
+		}
+
+		// Get flags
+		count, _ := cmd.Flags().GetInt("count")
+		prompt, _ := cmd.Flags().GetString("prompt")
+		promptFile, _ := cmd.Flags().GetString("prompt-file")
+		description, _ := cmd.Flags().GetString("description")
+		projectPath, _ := cmd.Flags().GetString("project")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+
+		if promptFile != "" {
+			if prompt, err = readPromptFile(promptFile); err != nil {
+				HandleError(err, "read prompt")
+			}
+		}
+		if strings.TrimSpace(prompt) == "" {
+			HandleError(fmt.Errorf("a prompt is required, give it with --prompt or --prompt-file"), "start fanout")
+		}
+
+		sessions, err := ctx.Client.Fanout(api.FanoutRequest{
+			Name:        group,
+			Description: description,
+			ProjectPath: GetProjectPath(projectPath),
+			Prompt:      prompt,
+			Count:       count,
+			Tags:        tags,
+		})
+		if len(sessions) == 0 {
+			HandleError(err, "start fanout")
+		}
+
+		group = sessions[0].Group
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Started %d sessions in group '%s'", len(sessions), group)))
+		fmt.Println()
+		for _, sess := range sessions {
+			fmt.Printf("  %s %s  %s\n", ui.Arrow(), ui.Highlight(sess.Name), ui.Dim(sess.Worktree.Path))
+		}
+		fmt.Println()
+
+		if err != nil {
+			fmt.Println(ui.ErrorMsg(fmt.Sprintf("Some sessions could not be started: %v", err)))
+			fmt.Println()
+		}
+
+		fmt.Println(ui.NextSteps(
+			fmt.Sprintf("claude-pilot fanout status %s", group),
+			fmt.Sprintf("claude-pilot attach %s", sessions[0].Name),
+		))
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var fanoutStatusCmd = &cobra.Command{
+	Use:     "status <group-name>",
+	Aliases: []string{"compare"},
+	Short:   "Compare the sessions of a fanout group",
+	Long: `Show the sessions of a fanout group side by side: their status and agent
+state, and what each changed on its branch since the fanout, committed or not.
+
+Examples:
+  claude-pilot fanout status login-fix
+  claude-pilot fanout compare login-fix`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		sessions, err := ctx.Client.ListGroupSessions(args[0])
+		if err != nil {
+			HandleError(err, "list group sessions")
+		}
+		if len(sessions) == 0 {
+			HandleError(fmt.Errorf("no sessions in group '%s'", args[0]), "show group")
+		}
+		slices.SortFunc(sessions, func(a, b *api.Session) int { return strings.Compare(a.Name, b.Name) })
+		ctx.Client.WithAgentState(sessions)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Session\tStatus\tAgent\tBranch\tCommits\tFiles\tLines\tUntracked\t")
+		for _, sess := range sessions {
+			agent := string(sess.AgentState)
+			if agent == "" {
+				agent = "—"
+			}
+			line := fmt.Sprintf("%s\t%s\t%s\t", sess.Name, sess.Status, agent)
+
+			if sess.Worktree == nil {
+				fmt.Fprintln(w, line+"—\t\t\t\t\t")
+				continue
+			}
+			stats, err := ctx.Client.WorktreeDiff(sess.Worktree)
+			if err != nil {
+				fmt.Fprintf(w, "%s%s\t%s\t\t\t\t\n", line, sess.Worktree.Branch, "unavailable")
+				continue
+			}
+			fmt.Fprintf(w, "%s%s\t%d\t%d\t+%d -%d\t%d\t\n", line, sess.Worktree.Branch,
+				stats.Commits, stats.Files, stats.Insertions, stats.Deletions, stats.Untracked)
+		}
+		w.Flush()
+	},
+}
+
+// readPromptFile reads a prompt from a file, or from stdin for "-"
+func readPromptFile(path string) (string, error) {
+	if path == "-" {
+		return readSendText(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func init() {
+	rootCmd.AddCommand(fanoutCmd)
+	fanoutCmd.AddCommand(fanoutStatusCmd)
+
+	// Add flags
+	fanoutCmd.Flags().IntP("count", "n", 3, "Number of sessions to start")
+	fanoutCmd.Flags().String("prompt", "", "The task for Claude")
+	fanoutCmd.Flags().String("prompt-file", "", "File holding the task for Claude, or - to read it from stdin")
+	fanoutCmd.Flags().StringP("description", "d", "", "Description for the sessions")
+	fanoutCmd.Flags().StringP("project", "p", "", "Project path in the git repository (defaults to current directory)")
+	fanoutCmd.Flags().StringSliceP("tag", "t", nil, "Tag the sessions (repeatable)")
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+
+	"github.com/spf13/cobra"
+)
+
+// headlessRunnerCmd runs claude -p for a headless session started with run.
+// It is started in the background by the run; it is not meant to be run by hand.
+var headlessRunnerCmd = &cobra.Command{
+	Use:                api.HeadlessRunnerCommand + " <runs-dir> <session-id>",
+	Short:              "Run a headless session",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.RunHeadlessRunner(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running headless session: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(headlessRunnerCmd)
+}
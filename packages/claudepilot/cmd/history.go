@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// historyPreviewLength bounds tool inputs and outputs in the terminal view
+const historyPreviewLength = 200
+
+var historyCmd = &cobra.Command{
+	Use:   "history <session-name-or-id>",
+	Short: "Show the Claude conversation of a session",
+	Long: `Show the Claude conversation running in a session, read from Claude Code's
+transcript, without attaching to it. Messages are shown a page at a time,
+starting with the most recent page.
+
+Examples:
+  claude-pilot history my-session                     # Most recent messages
+  claude-pilot history my-session --page 1            # First page of the conversation
+  claude-pilot history my-session --since 2h          # Messages from the last two hours
+  claude-pilot history my-session --format markdown   # Export the conversation as markdown
+  claude-pilot history my-session --format json -o conversation.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		since, _ := cmd.Flags().GetString("since")
+		page, _ := cmd.Flags().GetInt("page")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		messages, err := ctx.Client.GetMessages(args[0])
+		if err != nil {
+			HandleError(err, "read conversation")
+		}
+
+		if since != "" {
+			cutoff, err := parseSince(since)
+			if err != nil {
+				HandleError(err, "parse --since")
+			}
+			messages = messagesSince(messages, cutoff)
+		}
+
+		// Exports always contain every message
+		switch format {
+		case "text":
+		case "markdown", "md":
+			writeHistoryExport(output, renderMessagesMarkdown(args[0], messages))
+			return
+		case "json":
+			data, err := json.MarshalIndent(messages, "", "  ")
+			if err != nil {
+				HandleError(err, "encode messages")
+			}
+			writeHistoryExport(output, string(data)+"\n")
+			return
+		default:
+			HandleError(fmt.Errorf("unknown format '%s', use text, markdown or json", format), "export history")
+		}
+
+		if len(messages) == 0 {
+			fmt.Println(ui.InfoMsg("No messages found"))
+			return
+		}
+
+		pageMessages, page, totalPages := paginateMessages(messages, page, pageSize)
+		for _, message := range pageMessages {
+			printMessage(message)
+		}
+
+		fmt.Println(ui.Dim(fmt.Sprintf("Page %d of %d (%d messages)", page, totalPages, len(messages))))
+		if page > 1 {
+			ui.DisplayNextSteps(fmt.Sprintf("claude-pilot history %s --page %d", args[0], page-1))
+		}
+	},
+}
+
+// parseSince parses a --since value, either a duration ago, with d for days
+// in addition to Go's duration units, or a date
+func parseSince(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return time.Now().AddDate(0, 0, -days), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time '%s', use a duration like 2h or 7d, or a date like 2006-01-02", value)
+}
+
+// messagesSince returns the messages sent at or after cutoff
+func messagesSince(messages []api.Message, cutoff time.Time) []api.Message {
+	var filtered []api.Message
+	for _, message := range messages {
+		if !message.Timestamp.Before(cutoff) {
+			filtered = append(filtered, message)
+		}
+	}
+	return filtered
+}
+
+// paginateMessages returns one page of messages. Page 0 is the last page;
+// out-of-range pages are clamped. A page size of 0 shows everything.
+func paginateMessages(messages []api.Message, page, pageSize int) ([]api.Message, int, int) {
+	if pageSize <= 0 {
+		return messages, 1, 1
+	}
+
+	totalPages := (len(messages) + pageSize - 1) / pageSize
+	if page <= 0 || page > totalPages {
+		page = totalPages
+	}
+
+	start := (page - 1) * pageSize
+	end := min(start+pageSize, len(messages))
+	return messages[start:end], page, totalPages
+}
+
+// printMessage prints a message for the terminal, shortening tool calls
+func printMessage(message api.Message) {
+	role := ui.Highlight("Claude")
+	if message.Role == "user" {
+		role = ui.Bold("You")
+	}
+	fmt.Printf("%s %s\n", role, ui.Dim(message.Timestamp.Local().Format("2006-01-02 15:04:05")))
+
+	if message.Content != "" {
+		fmt.Println(message.Content)
+	}
+	for _, call := range message.ToolCalls {
+		fmt.Printf("  %s %s %s\n", ui.Arrow(), ui.Bold(call.Name), ui.Dim(truncate(call.Input, historyPreviewLength)))
+		if call.Output != "" {
+			mark := ui.CheckMark()
+			if call.IsError {
+				mark = ui.CrossMark()
+			}
+			fmt.Printf("    %s %s\n", mark, ui.Dim(truncate(call.Output, historyPreviewLength)))
+		}
+	}
+	fmt.Println()
+}
+
+// renderMessagesMarkdown renders a conversation as a markdown document
+func renderMessagesMarkdown(sessionName string, messages []api.Message) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Conversation: %s\n\n", sessionName)
+
+	for _, message := range messages {
+		role := "Claude"
+		if message.Role == "user" {
+			role = "User"
+		}
+		fmt.Fprintf(&b, "## %s (%s)\n\n", role, message.Timestamp.Local().Format("2006-01-02 15:04:05"))
+
+		if message.Content != "" {
+			fmt.Fprintf(&b, "%s\n\n", message.Content)
+		}
+		for _, call := range message.ToolCalls {
+			fmt.Fprintf(&b, "**Tool: %s**\n\n```json\n%s\n```\n\n", call.Name, call.Input)
+			if call.Output != "" {
+				label := "Result"
+				if call.IsError {
+					label = "Error"
+				}
+				fmt.Fprintf(&b, "%s:\n\n```\n%s\n```\n\n", label, call.Output)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// writeHistoryExport writes an export to a file, or to stdout without one
+func writeHistoryExport(output, content string) {
+	if output == "" {
+		fmt.Print(content)
+		return
+	}
+
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		HandleError(err, "write export")
+	}
+	fmt.Println(ui.SuccessMsg(fmt.Sprintf("Conversation exported to %s", output)))
+}
+
+// truncate shortens s to a single line of at most n characters
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	// Add flags
+	historyCmd.Flags().String("since", "", "Only show messages since a duration ago (e.g. 2h) or a date")
+	historyCmd.Flags().Int("page", 0, "Page to show, starting at 1 (default: most recent page)")
+	historyCmd.Flags().Int("page-size", 20, "Messages per page (0 shows all)")
+	historyCmd.Flags().StringP("format", "f", "text", "Output format: text, markdown or json")
+	historyCmd.Flags().StringP("output", "o", "", "Write markdown or json exports to a file")
+}
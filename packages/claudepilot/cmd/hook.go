@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Record a Claude Code hook event (run by Claude Code)",
+	Long: `Record a Claude Code hook event in the session Claude runs in. Claude Code
+runs this command itself once it is configured as a hook, see
+'claude-pilot hooks install'. The event is read as JSON from stdin and keeps
+the session's agent state, conversation and last activity up to date.
+
+The session is found by the CLAUDE_PILOT_SESSION_ID environment variable, the
+tmux pane, the conversation, or the working directory, in that order. Hooks
+from Claude instances outside claude-pilot sessions are ignored.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Claude Code adds the stdout of some hooks to the conversation, so
+		// this command only ever writes to stderr. It runs for every tool
+		// call, so it gets a client that does nothing else.
+		client, err := api.NewHookClient(api.ClientConfig{
+			ConfigFile: cfgFile,
+			Verbose:    viper.GetBool("verbose"),
+		})
+		if err != nil {
+			hookError(err, "initialize command")
+		}
+
+		event, err := api.ReadHookEvent(os.Stdin)
+		if err != nil {
+			hookError(err, "read hook event")
+		}
+
+		origin := api.HookOrigin{
+			SessionID: os.Getenv(api.SessionIDEnv),
+			Pane:      os.Getenv("TMUX_PANE"),
+		}
+
+		// Most likely Claude runs outside of claude-pilot, which is fine
+		if _, err := client.RecordHookEvent(*event, origin); err != nil {
+			client.GetLogger().Debug("Hook event not recorded", "event", event.Name, "error", err)
+		}
+	},
+}
+
+// hookError reports a failure of the hook command on stderr, where Claude
+// Code shows it without interrupting the agent
+func hookError(err error, action string) {
+	fmt.Fprintf(os.Stderr, "claude-pilot: failed to %s: %v\n", action, err)
+	os.Exit(1)
+}
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage Claude Code hooks",
+	Long: `Manage the Claude Code hooks that report what Claude is doing in your
+sessions to claude-pilot.`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [project-path]",
+	Short: "Configure Claude Code to report to claude-pilot",
+	Long: `Add 'claude-pilot hook' as a hook to the shared Claude Code settings of a
+project (.claude/settings.json), keeping the settings and hooks already there.
+Claude then reports when it starts, works, asks for permission and stops,
+which is more reliable than reading the session's screen.
+
+Examples:
+  claude-pilot hooks install                   # Install in the current directory
+  claude-pilot hooks install ~/src/api         # Install in another project
+  claude-pilot hooks install --command "/opt/bin/claude-pilot hook"`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get flags
+		command, _ := cmd.Flags().GetString("command")
+
+		var projectPath string
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+		projectPath = api.GetProjectPath(projectPath)
+
+		path, added, err := api.InstallHooks(projectPath, command)
+		if err != nil {
+			HandleError(err, "install hooks")
+		}
+
+		if len(added) == 0 {
+			fmt.Println(ui.InfoMsg(fmt.Sprintf("Hooks already installed in %s", path)))
+			return
+		}
+
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Installed hooks in %s", path)))
+		fmt.Printf("  %s %s\n", ui.Arrow(), ui.Dim(strings.Join(added, ", ")))
+		fmt.Println()
+		fmt.Println(ui.InfoMsg("Claude sessions started from now on report to claude-pilot"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+
+	// Add flags
+	hooksInstallCmd.Flags().String("command", "claude-pilot hook", "Command Claude Code runs for each hook")
+}
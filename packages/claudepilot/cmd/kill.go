@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"claude-pilot/core/api"
@@ -16,10 +17,21 @@ var killCmd = &cobra.Command{
 	Long: `Kill (terminate) a Claude coding session.
 If no session name is provided, kills all sessions.
 
+Killing a session also kills the panes and windows attached to it with
+--as-pane or --as-window, while killing one of those closes just that pane
+or window.
+
+Sessions created with --worktree keep their git worktree unless it is
+removed: kill asks, or removes it with --remove-worktree. Worktrees with
+uncommitted changes are kept; --force-worktree removes them as well.
+
 Examples:
   claude-pilot kill my-session    # Kill specific session
+  claude-pilot kill my-tests      # Close a pane attached to a session
   claude-pilot kill --all         # Kill all sessions
-  claude-pilot kill --force       # Kill without confirmation`,
+  claude-pilot kill --force       # Kill without confirmation
+  claude-pilot kill api --remove-worktree  # Also remove the session's worktree
+  claude-pilot kill api --force-worktree   # Even with uncommitted changes`,
 	Aliases: []string{"terminate", "stop", "delete", "remove", "del"},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize common command context
@@ -31,6 +43,9 @@ Examples:
 		// Get flags
 		killAll, _ := cmd.Flags().GetBool("all")
 		force, _ := cmd.Flags().GetBool("force")
+		removeWorktree, _ := cmd.Flags().GetBool("remove-worktree")
+		forceWorktree, _ := cmd.Flags().GetBool("force-worktree")
+		removeWorktree = removeWorktree || forceWorktree
 
 		// Get all sessions
 		allSessions, err := ctx.Client.ListSessions()
@@ -75,6 +90,13 @@ Examples:
 			}
 
 			sessions = []*api.Session{targetSession}
+
+			// Its panes and windows are killed along with it
+			for _, sess := range allSessions {
+				if sess.ParentID == targetSession.ID {
+					sessions = append(sessions, sess)
+				}
+			}
 		}
 
 		// Check if any sessions to kill
@@ -106,13 +128,25 @@ Examples:
 			}
 		}
 
+		killing := make(map[string]bool, len(sessions))
+		for _, sess := range sessions {
+			killing[sess.ID] = true
+		}
+
 		// Kill sessions
 		var errors []string
 		for _, sess := range sessions {
+			// Panes and windows go with the session they are attached to
+			if killing[sess.ParentID] {
+				continue
+			}
 			if err := ctx.Client.KillSession(sess.ID); err != nil {
 				errors = append(errors, fmt.Sprintf("Failed to kill session %s: %v", sess.Name, err))
 			} else {
 				fmt.Printf("%s Session %s killed successfully\n", ui.SuccessMsg(""), ui.Highlight(sess.Name))
+				if sess.Worktree != nil {
+					removeSessionWorktree(ctx.Client, sess, removeWorktree, force, forceWorktree)
+				}
 			}
 		}
 
@@ -136,6 +170,33 @@ Examples:
 	},
 }
 
+// removeSessionWorktree removes the worktree of a killed session when asked
+// to or when the user agrees. Kills without confirmation keep it unless asked
+// to remove it, and uncommitted changes keep it unless discard is set.
+func removeSessionWorktree(client *api.Client, sess *api.Session, remove, noConfirm, discard bool) {
+	wt := sess.Worktree
+	if !remove {
+		if noConfirm {
+			fmt.Println(ui.InfoMsg(fmt.Sprintf("Kept worktree %s (branch %s)", wt.Path, wt.Branch)))
+			return
+		}
+		if !ConfirmAction(fmt.Sprintf("Remove worktree %s of session %s? [y/N]: ", wt.Path, sess.Name)) {
+			return
+		}
+	}
+
+	err := client.RemoveWorktree(wt, discard)
+	switch {
+	case errors.Is(err, api.ErrWorktreeDirty):
+		fmt.Println(ui.WarningMsg(fmt.Sprintf("Kept worktree %s, it has uncommitted changes", wt.Path)))
+		fmt.Println(ui.InfoMsg("Remove it anyway with --force-worktree, or: git worktree remove --force " + wt.Path))
+	case err != nil:
+		fmt.Println(ui.ErrorMsg(fmt.Sprintf("Failed to remove worktree %s: %v", wt.Path, err)))
+	default:
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Removed worktree %s, branch %s is kept", wt.Path, wt.Branch)))
+	}
+}
+
 // convertToSessionDataForKill converts API sessions to the shared table SessionData format for kill command
 func convertToSessionDataForKill(sessions []*api.Session) []components.SessionData {
 	sessionData := make([]components.SessionData, len(sessions))
@@ -145,12 +206,15 @@ func convertToSessionDataForKill(sessions []*api.Session) []components.SessionDa
 			ID:          sess.ID,
 			Name:        sess.Name,
 			Status:      string(sess.Status),
+			AgentState:  string(sess.AgentState),
 			Backend:     sess.Backend,
 			Created:     sess.CreatedAt,
 			LastActive:  sess.LastActive,
 			ProjectPath: sess.ProjectPath,
 			Panes:       sess.Panes,
+			ParentID:    sess.ParentID,
 		}
+		sessionData[i].SetUsage(sess.Usage)
 	}
 
 	return sessionData
@@ -162,4 +226,6 @@ func init() {
 	// Add flags
 	killCmd.Flags().BoolP("all", "a", false, "Kill all sessions")
 	killCmd.Flags().BoolP("force", "f", false, "Force kill without confirmation")
+	killCmd.Flags().Bool("remove-worktree", false, "Remove the git worktree of killed sessions without asking")
+	killCmd.Flags().Bool("force-worktree", false, "Remove the git worktree of killed sessions even with uncommitted changes")
 }
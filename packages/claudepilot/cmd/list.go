@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"claude-pilot/core/api"
 	"claude-pilot/internal/ui"
@@ -15,6 +16,7 @@ var listCmd = &cobra.Command{
 	Short: "List all active Claude sessions",
 	Long: `List all active Claude coding sessions with their details.
 Shows session ID, name, status, creation time, last activity, message count, and pane count.
+Panes and windows attached to a session are listed under it.
 
 Examples:
   claude-pilot list           	# List all sessions
@@ -60,6 +62,8 @@ Examples:
 			}
 		}
 
+		ctx.Client.WithAgentState(sessions)
+
 		// Display header with enhanced styling
 		fmt.Println(ui.Header("Claude Pilot Sessions"))
 		fmt.Printf("%s Backend: %s\n", ui.InfoMsg("Current"), ui.Highlight(ctx.Client.GetBackend()))
@@ -108,7 +112,7 @@ Examples:
 		activeCount := 0
 		inactiveCount := 0
 		for _, sess := range sessions {
-			if sess.Status == api.StatusActive || sess.Status == api.StatusConnected {
+			if sess.Status == api.StatusActive || sess.Status == api.StatusConnected || sess.Status == api.StatusRunning {
 				activeCount++
 			} else {
 				inactiveCount++
@@ -118,6 +122,17 @@ Examples:
 		fmt.Println(ui.SessionSummary(len(sessions), activeCount, inactiveCount))
 		ui.DisplaySessionSummary(len(sessions), activeCount, inactiveCount, false)
 
+		// Point out agents that are blocked on the user
+		var needAttention []string
+		for _, sess := range sessions {
+			if sess.AgentState.NeedsAttention() {
+				needAttention = append(needAttention, fmt.Sprintf("%s (%s)", sess.Name, sess.AgentState))
+			}
+		}
+		if len(needAttention) > 0 {
+			fmt.Println(ui.WarningMsg("Waiting for you: " + strings.Join(needAttention, ", ")))
+		}
+
 		// Show helpful commands with enhanced styling
 		fmt.Println(ui.AvailableCommands(
 			"claude-pilot attach <session-name>",
@@ -145,12 +160,15 @@ func convertToSessionData(sessions []*api.Session, client *api.Client) []compone
 			ID:          sess.ID,
 			Name:        sess.Name,
 			Status:      string(sess.Status),
+			AgentState:  string(sess.AgentState),
 			Backend:     sess.Backend,
 			Created:     sess.CreatedAt,
 			LastActive:  sess.LastActive,
 			ProjectPath: sess.ProjectPath,
 			Panes:       paneCount,
+			ParentID:    sess.ParentID,
 		}
+		sessionData[i].SetUsage(sess.Usage)
 	}
 
 	return sessionData
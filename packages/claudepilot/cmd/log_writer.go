@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+
+	"github.com/spf13/cobra"
+)
+
+// logWriterCmd writes the output of a logged session to its log. The
+// multiplexer pipes the session's output into it; it is not meant to be run by hand.
+var logWriterCmd = &cobra.Command{
+	Use:                api.LogWriterCommand + " <path> <max-size> <max-files> <strip-ansi>",
+	Short:              "Write a session's output log",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.RunLogWriter(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing session log: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logWriterCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"claude-pilot/core/api"
+
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [session-name-or-id]",
+	Short: "Show the output log of a session",
+	Long: `Print what a session has printed, as kept in its output log. Logs survive
+the session's panes and are continued when it is resumed. Sessions are
+logged when created with --log or when session_logs.enabled is set.
+
+The log of a headless run holds Claude's stream-json events; they are shown
+as readable text unless --raw is given.
+
+--since takes a duration such as 30m or 2d, or a date such as 2006-01-02.
+
+Examples:
+  claude-pilot logs my-session                  # The whole log
+  claude-pilot logs my-session -f               # Follow new output
+  claude-pilot logs my-session --since 1h       # Output of the last hour
+  claude-pilot logs my-session --timestamps     # With the time of each line
+  claude-pilot logs my-run --raw                # The events of a headless run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		follow, _ := cmd.Flags().GetBool("follow")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		timestamps, _ := cmd.Flags().GetBool("timestamps")
+		raw, _ := cmd.Flags().GetBool("raw")
+
+		var since time.Time
+		if sinceFlag != "" {
+			if since, err = parseSince(sinceFlag); err != nil {
+				HandleError(err, "parse --since")
+			}
+		}
+
+		// Events of headless runs are described unless asked for raw
+		describe := false
+		if !raw {
+			if sess, err := ctx.Client.GetSession(args[0]); err == nil && sess.Backend == api.HeadlessBackend {
+				describe = true
+			}
+		}
+
+		printLine := func(line api.OutputLine) {
+			if describe {
+				text, shown := api.DescribeRunEvent(line.Text)
+				if !shown {
+					return
+				}
+				line.Text = text
+			}
+			if timestamps && !line.Time.IsZero() {
+				fmt.Printf("%s %s\n", line.Time.Local().Format("2006-01-02 15:04:05"), line.Text)
+				return
+			}
+			fmt.Println(line.Text)
+		}
+
+		if follow {
+			followCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := ctx.Client.FollowOutputLog(followCtx, args[0], since, printLine); err != nil {
+				HandleError(err, "follow session log")
+			}
+			return
+		}
+
+		lines, err := ctx.Client.ReadOutputLog(args[0], since)
+		if err != nil {
+			HandleError(err, "read session log")
+		}
+		for _, line := range lines {
+			printLine(line)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	// Add flags
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep printing new output as it is logged")
+	logsCmd.Flags().String("since", "", "Show output logged since a duration ago or a date")
+	logsCmd.Flags().Bool("timestamps", false, "Show the time each line was logged")
+	logsCmd.Flags().Bool("raw", false, "Show the events of a headless run as logged")
+}
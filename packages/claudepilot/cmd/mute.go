@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var muteCmd = &cobra.Command{
+	Use:   "mute [session-name-or-id]",
+	Short: "Stop notifications for a session",
+	Long: `Stop 'claude-pilot watch' from sending notifications for a session.
+
+Examples:
+  claude-pilot mute my-session     # Silence my-session`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setSessionMuted(args[0], true)
+	},
+}
+
+var unmuteCmd = &cobra.Command{
+	Use:   "unmute [session-name-or-id]",
+	Short: "Resume notifications for a muted session",
+	Long: `Let 'claude-pilot watch' send notifications for a muted session again.
+
+Examples:
+  claude-pilot unmute my-session   # Notify about my-session again`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setSessionMuted(args[0], false)
+	},
+}
+
+// setSessionMuted mutes or unmutes a session and reports the result
+func setSessionMuted(identifier string, muted bool) {
+	// Initialize common command context
+	ctx, err := InitializeCommand()
+	if err != nil {
+		HandleError(err, "initialize command")
+	}
+
+	sess, err := ctx.Client.SetMuted(identifier, muted)
+	if err != nil {
+		HandleError(err, "update session")
+	}
+
+	if muted {
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Notifications for '%s' muted", sess.Name)))
+	} else {
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Notifications for '%s' unmuted", sess.Name)))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(muteCmd)
+	rootCmd.AddCommand(unmuteCmd)
+}
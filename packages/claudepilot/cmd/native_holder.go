@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+
+	"github.com/spf13/cobra"
+)
+
+// nativeHolderCmd runs the process that owns a native backend session's terminal.
+// It is started by the native backend itself and is not meant to be run by hand.
+var nativeHolderCmd = &cobra.Command{
+	Use:                api.NativeHolderCommand + " <state-dir> <session>",
+	Short:              "Hold a native backend session",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.RunNativeHolder(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running session holder: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nativeHolderCmd)
+	api.EnableNativeBackend()
+}
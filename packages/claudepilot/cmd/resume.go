@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [session-name-or-id]",
+	Short: "Resume an inactive Claude session",
+	Long: `Resume an inactive Claude coding session by recreating its multiplexer
+session in the stored project path. The session keeps its ID, and Claude is
+started with --continue so the conversation picks up where it stopped.
+Sessions running another command than default_shell start it again as it was.
+
+Examples:
+  claude-pilot resume my-session     # Resume a specific session
+  claude-pilot resume --all          # Resume every inactive session, e.g. after a reboot`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		resumeAll, _ := cmd.Flags().GetBool("all")
+
+		if resumeAll {
+			resumeAllSessions(ctx.Client)
+			return
+		}
+
+		if len(args) == 0 {
+			fmt.Println(ui.ErrorMsg("No session name provided"))
+			fmt.Println()
+			fmt.Println(ui.InfoMsg("Usage:"))
+			fmt.Printf("  %s %s\n", ui.Arrow(), ui.Highlight("claude-pilot resume <session-name>"))
+			fmt.Printf("  %s %s\n", ui.Arrow(), ui.Highlight("claude-pilot resume --all"))
+			os.Exit(1)
+		}
+
+		sess, err := ctx.Client.ResumeSession(args[0])
+		if err != nil {
+			HandleError(err, "resume session")
+		}
+
+		fmt.Println(ui.SuccessMsg(fmt.Sprintf("Session '%s' resumed", sess.Name)))
+		ui.DisplayNextSteps("claude-pilot attach " + sess.Name)
+	},
+}
+
+// resumeAllSessions resumes every inactive session, reporting each result.
+// Panes and windows come back with the session they are attached to.
+func resumeAllSessions(client *api.Client) {
+	sessions, err := client.ResumeTargets()
+	if err != nil {
+		HandleError(err, "list inactive sessions")
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println(ui.InfoMsg("No inactive sessions to resume"))
+		return
+	}
+
+	var failed int
+	for _, sess := range sessions {
+		if _, err := client.ResumeSession(sess.ID); err != nil {
+			failed++
+			fmt.Printf("  %s %s: %v\n", ui.CrossMark(), ui.Highlight(sess.Name), err)
+			continue
+		}
+		fmt.Printf("  %s %s resumed\n", ui.CheckMark(), ui.Highlight(sess.Name))
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Println(ui.WarningMsg(fmt.Sprintf("Resumed %d of %d sessions", len(sessions)-failed, len(sessions))))
+		os.Exit(1)
+	}
+	fmt.Println(ui.SuccessMsg(fmt.Sprintf("Resumed %d sessions", len(sessions))))
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+
+	// Add flags
+	resumeCmd.Flags().BoolP("all", "a", false, "Resume all inactive sessions")
+}
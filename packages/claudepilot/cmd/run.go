@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [session-name]",
+	Short: "Run Claude headless with a prompt",
+	Long: `Run claude -p with a prompt in the background, without a terminal. The run is
+tracked as a session of the headless backend: list shows whether it is running,
+succeeded or failed, logs shows what Claude did, and details shows the result.
+Killing the session stops the run.
+
+The prompt can be given as text or read from stdin with --prompt -.
+
+Examples:
+  claude-pilot run --prompt "Fix the failing tests"          # Run in the current directory
+  claude-pilot run lint --prompt "Fix lint errors" -p ./api  # Named run in ./api
+  claude-pilot run --prompt - < task.md                      # Prompt from a file
+  claude-pilot run --prompt "Review" --arg --model --arg opus # Pass arguments to claude
+  claude-pilot run --prompt "Update deps" --wait              # Wait and print the result`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get command-specific parameters
+		var sessionName string
+		if len(args) > 0 {
+			sessionName = args[0]
+		}
+
+		// Get flags
+		promptArg, _ := cmd.Flags().GetString("prompt")
+		description, _ := cmd.Flags().GetString("description")
+		projectPath, _ := cmd.Flags().GetString("project")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		claudeArgs, _ := cmd.Flags().GetStringArray("arg")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		prompt, err := readSendText(promptArg)
+		if err != nil {
+			HandleError(err, "read prompt")
+		}
+		if prompt == "" {
+			HandleError(fmt.Errorf("a prompt is required, give it with --prompt"), "start run")
+		}
+
+		sess, err := ctx.Client.RunHeadless(api.RunRequest{
+			Name:        sessionName,
+			Description: description,
+			ProjectPath: GetProjectPath(projectPath),
+			Prompt:      prompt,
+			Args:        claudeArgs,
+			Tags:        tags,
+		})
+		if err != nil {
+			HandleError(err, "start run")
+		}
+
+		if !wait {
+			fmt.Println(ui.SuccessMsg(fmt.Sprintf("Started run '%s'", sess.Name)))
+			fmt.Println()
+			fmt.Println(ui.SessionDetailsFormatted(sess, ctx.Client.GetBackend()))
+			fmt.Println()
+			fmt.Println(ui.NextSteps(
+				fmt.Sprintf("claude-pilot logs %s -f", sess.Name),
+				fmt.Sprintf("claude-pilot details %s", sess.Name),
+			))
+			return
+		}
+
+		fmt.Println(ui.InfoMsg(fmt.Sprintf("Waiting for run '%s' to finish...", sess.Name)))
+		for sess.Status == api.StatusRunning {
+			time.Sleep(time.Second)
+			if sess, err = ctx.Client.GetSession(sess.ID); err != nil {
+				HandleError(err, "get run status")
+			}
+		}
+
+		fmt.Println(ui.SessionDetailsFormatted(sess, ctx.Client.GetBackend()))
+		if sess.Status != api.StatusSucceeded {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	// Add flags
+	runCmd.Flags().String("prompt", "", "The prompt for Claude, or - to read it from stdin")
+	runCmd.Flags().StringP("description", "d", "", "Description for the session")
+	runCmd.Flags().StringP("project", "p", "", "Directory to run in (defaults to current directory)")
+	runCmd.Flags().StringSliceP("tag", "t", nil, "Tag the session (repeatable)")
+	runCmd.Flags().StringArray("arg", nil, "Argument passed on to claude, e.g. --arg --model --arg opus (repeatable)")
+	runCmd.Flags().Bool("wait", false, "Wait for the run to finish and print its result; exits 1 if it failed")
+}
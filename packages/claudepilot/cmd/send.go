@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var sendCmd = &cobra.Command{
+	Use:   "send [session-name-or-id] [text|-]",
+	Short: "Send a prompt or keys to a session",
+	Long: `Type a prompt into a session without attaching to it. The text is pasted in
+one piece, so multi-line prompts arrive intact, and Enter submits it unless
+--no-enter is given. Use - to read the text from stdin.
+
+Keys given with --key are pressed before the text, using tmux key names such
+as Escape, C-c, Up or Tab; keys alone are sent without Enter. With --all or
+--tag the same input is sent to every running session, or every running
+session with the tag. Headless runs and panes or windows attached to a
+session are skipped.
+
+Examples:
+  claude-pilot send my-session "Run the tests and fix failures"
+  claude-pilot send my-session - < prompt.md          # Send a prompt from a file
+  claude-pilot send my-session --key Escape           # Interrupt the agent
+  claude-pilot send my-session --key C-c              # Press Ctrl+C
+  claude-pilot send my-session -t 1 "make build"      # Type into window 1
+  claude-pilot send --tag backend "Rebase on main"    # Broadcast to tagged sessions`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		noEnter, _ := cmd.Flags().GetBool("no-enter")
+		target, _ := cmd.Flags().GetString("target")
+		keys, _ := cmd.Flags().GetStringArray("key")
+		all, _ := cmd.Flags().GetBool("all")
+		tag, _ := cmd.Flags().GetString("tag")
+
+		broadcast := all || tag != ""
+
+		// Broadcasts take only the text, everything else a session first
+		var identifier, textArg string
+		switch {
+		case broadcast && len(args) > 1:
+			HandleError(fmt.Errorf("--all and --tag take no session name"), "parse arguments")
+		case broadcast && len(args) == 1:
+			textArg = args[0]
+		case !broadcast && len(args) == 0:
+			HandleError(fmt.Errorf("no session name provided, or use --all or --tag"), "parse arguments")
+		case !broadcast:
+			identifier = args[0]
+			if len(args) > 1 {
+				textArg = args[1]
+			}
+		}
+
+		text, err := readSendText(textArg)
+		if err != nil {
+			HandleError(err, "read input")
+		}
+		if text == "" && len(keys) == 0 {
+			HandleError(fmt.Errorf("nothing to send, give a text or --key"), "send input")
+		}
+
+		opts := api.SendInputOptions{
+			Target: target,
+			Keys:   keys,
+			// A trailing Enter after bare keys would also submit whatever is in the input box
+			Enter: !noEnter && text != "",
+		}
+
+		if !broadcast {
+			if err := ctx.Client.SendInput(identifier, text, opts); err != nil {
+				HandleError(err, "send input")
+			}
+			fmt.Println(ui.SuccessMsg(fmt.Sprintf("Sent input to '%s'", identifier)))
+			return
+		}
+
+		broadcastInput(ctx.Client, tag, text, opts)
+	},
+}
+
+// readSendText returns the text argument, reading stdin for "-". A single
+// trailing newline, as left by files and heredocs, is dropped so that it
+// does not submit the prompt early.
+func readSendText(arg string) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(text, "\r"), nil
+}
+
+// broadcastInput sends the same input to every running session, or every
+// running session with tag, reporting each result
+func broadcastInput(client *api.Client, tag, text string, opts api.SendInputOptions) {
+	targets, err := client.InputTargets(tag)
+	if err != nil {
+		HandleError(err, "list sessions")
+	}
+
+	if len(targets) == 0 {
+		fmt.Println(ui.InfoMsg("No running sessions to send input to"))
+		return
+	}
+
+	var failed int
+	for _, sess := range targets {
+		if err := client.SendInput(sess.ID, text, opts); err != nil {
+			failed++
+			fmt.Printf("  %s %s: %v\n", ui.CrossMark(), ui.Highlight(sess.Name), err)
+			continue
+		}
+		fmt.Printf("  %s %s\n", ui.CheckMark(), ui.Highlight(sess.Name))
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Println(ui.WarningMsg(fmt.Sprintf("Sent input to %d of %d sessions", len(targets)-failed, len(targets))))
+		os.Exit(1)
+	}
+	fmt.Println(ui.SuccessMsg(fmt.Sprintf("Sent input to %d sessions", len(targets))))
+}
+
+func init() {
+	rootCmd.AddCommand(sendCmd)
+
+	// Add flags
+	sendCmd.Flags().Bool("no-enter", false, "Do not press Enter after the text")
+	sendCmd.Flags().StringP("target", "t", "", "Window or pane to type into, e.g. 1 or 1.2 (default: the first pane)")
+	sendCmd.Flags().StringArrayP("key", "k", nil, "Key to press before the text, e.g. Escape or C-c (repeatable)")
+	sendCmd.Flags().BoolP("all", "a", false, "Send to all running sessions")
+	sendCmd.Flags().String("tag", "", "Send to all running sessions with this tag")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+	"claude-pilot/shared/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile session metadata with the multiplexer",
+	Long: `Reconcile session metadata with the running multiplexer sessions.
+Multiplexer sessions with the configured prefix but no metadata (e.g. created
+by hand, or whose metadata file was deleted) are adopted. Metadata whose
+multiplexer session is gone is reported, and deleted with --prune along with
+its output log. Git worktrees of pruned sessions are kept and listed.
+
+Examples:
+  claude-pilot sync              # Adopt orphaned sessions, report stale metadata
+  claude-pilot sync --prune      # Also delete stale metadata
+  claude-pilot sync --dry-run    # Show what would change`,
+	Aliases: []string{"reconcile"},
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		result, err := ctx.Client.Reconcile(interfaces.ReconcileOptions{
+			DryRun: dryRun,
+			Prune:  prune,
+		})
+		if err != nil {
+			HandleError(err, "reconcile sessions")
+		}
+
+		if len(result.Adopted) == 0 && len(result.Stale) == 0 {
+			fmt.Println(ui.SuccessMsg("Session metadata is in sync with " + ctx.Client.GetBackend()))
+			return
+		}
+
+		adoptVerb, pruneVerb := "Adopted", "Pruned"
+		if result.DryRun {
+			adoptVerb, pruneVerb = "Would adopt", "Would prune"
+		}
+
+		if len(result.Adopted) > 0 {
+			fmt.Println(ui.InfoMsg(fmt.Sprintf("%s %d orphaned %s session(s):", adoptVerb, len(result.Adopted), ctx.Client.GetBackend())))
+			printSyncSessions(result.Adopted)
+			fmt.Println()
+		}
+
+		if len(result.Stale) > 0 {
+			if prune {
+				fmt.Println(ui.WarningMsg(fmt.Sprintf("%s %d stale session(s):", pruneVerb, len(result.Stale))))
+			} else {
+				fmt.Println(ui.WarningMsg(fmt.Sprintf("Found %d stale session(s) without a running %s session:", len(result.Stale), ctx.Client.GetBackend())))
+			}
+			printSyncSessions(result.Stale)
+
+			// Pruning keeps worktrees, which may hold unmerged work
+			for _, sess := range result.Pruned {
+				if sess.Worktree != nil {
+					fmt.Println(ui.InfoMsg(fmt.Sprintf("Kept worktree %s (branch %s) of %s", sess.Worktree.Path, sess.Worktree.Branch, sess.Name)))
+				}
+			}
+
+			if !prune {
+				ui.DisplayNextSteps("claude-pilot sync --prune")
+			}
+		}
+	},
+}
+
+// printSyncSessions prints one line per session affected by sync
+func printSyncSessions(sessions []*api.Session) {
+	for _, sess := range sessions {
+		path := sess.ProjectPath
+		if path == "" {
+			path = "-"
+		}
+		fmt.Printf("  %s %s %s\n", ui.Arrow(), ui.Highlight(sess.Name), ui.Dim(path))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	// Add flags
+	syncCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	syncCmd.Flags().Bool("prune", false, "Delete metadata whose multiplexer session is gone")
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:     "templates",
+	Aliases: []string{"template"},
+	Short:   "List and show session templates",
+	Long: `Session templates are named presets for new sessions, defined in the
+templates section of the config file. A template can set the command, Claude's
+options, environment variables, the description, where the session starts and
+how it is attached, and the prompt Claude starts with.
+
+Create a session from one with 'claude-pilot create --template <name>'.
+
+Examples:
+  claude-pilot templates list           # List the templates
+  claude-pilot templates show reviewer  # Show what a template sets`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the session templates",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		names := ctx.Client.TemplateNames()
+		if len(names) == 0 {
+			fmt.Println(ui.InfoMsg("No templates configured, add them to the templates section of the config file"))
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Name\tDescription\tSets\t")
+		for _, name := range names {
+			template, _ := ctx.Client.GetTemplate(name)
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", name, template.Description, strings.Join(templateKeys(template), ", "))
+		}
+		w.Flush()
+	},
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <template-name>",
+	Short: "Show what a session template sets",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		template, err := ctx.Client.GetTemplate(args[0])
+		if err != nil {
+			HandleError(err, "show template")
+		}
+
+		fmt.Println(ui.Title("Template " + strings.ToLower(args[0])))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, field := range templateFields(template) {
+			if field.value != "" {
+				fmt.Fprintf(w, "%s:\t%s\n", field.key, field.value)
+			}
+		}
+		w.Flush()
+	},
+}
+
+// templateField is a config key of a template and its value, as shown
+type templateField struct {
+	key   string
+	value string
+}
+
+// templateFields returns the keys a template can set with their values,
+// empty for the ones it doesn't set
+func templateFields(t *api.SessionTemplate) []templateField {
+	list := func(values []string) string { return strings.Join(values, ", ") }
+
+	var worktree string
+	if t.Worktree {
+		worktree = "yes"
+	}
+	var panes []string
+	for _, pane := range t.Panes {
+		panes = append(panes, ui.PaneSummary(pane.Command, pane.Split, pane.Size))
+	}
+	return []templateField{
+		{"description", t.Description},
+		{"command", t.Command},
+		{"model", t.Model},
+		{"permission_mode", t.PermissionMode},
+		{"allowed_tools", list(t.AllowedTools)},
+		{"disallowed_tools", list(t.DisallowedTools)},
+		{"mcp_config", t.MCPConfig},
+		{"append_system_prompt", t.AppendSystemPrompt},
+		{"add_dirs", list(t.AddDirs)},
+		{"args", strings.Join(t.Args, " ")},
+		{"env", list(t.Env)},
+		{"working_dir", t.WorkingDir},
+		{"worktree", worktree},
+		{"branch", t.Branch},
+		{"attach_to", t.AttachTo},
+		{"attachment", t.Attachment},
+		{"split", t.Split},
+		{"prompt", t.Prompt},
+		{"tags", list(t.Tags)},
+		{"panes", strings.Join(panes, "; ")},
+	}
+}
+
+// templateKeys returns the keys a template sets, other than its description
+func templateKeys(t *api.SessionTemplate) []string {
+	var keys []string
+	for _, field := range templateFields(t)[1:] {
+		if field.value != "" {
+			keys = append(keys, field.key)
+		}
+	}
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+	"claude-pilot/shared/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage [session-name-or-id...]",
+	Short: "Show token usage and cost of sessions",
+	Long: `Show the tokens used by the Claude conversations of your sessions and what
+they cost, read from Claude Code's transcripts. Costs come from the price
+table in the usage section of the configuration.
+
+Examples:
+  claude-pilot usage                      # Usage per session
+  claude-pilot usage my-session           # Usage of a single session
+  claude-pilot usage --by day --since 7d  # Daily usage over the last week
+  claude-pilot usage --by project -f csv  # Usage per project as CSV`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		groupBy, _ := cmd.Flags().GetString("by")
+		since, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+
+		var cutoff time.Time
+		if since != "" {
+			if cutoff, err = parseSince(since); err != nil {
+				HandleError(err, "parse --since")
+			}
+		}
+
+		var sessions []*api.Session
+		if len(args) > 0 {
+			for _, arg := range args {
+				session, err := ctx.Client.GetSession(arg)
+				if err != nil {
+					HandleError(err, "get session")
+				}
+				sessions = append(sessions, session)
+			}
+		} else {
+			sessions, err = ctx.Client.ListSessions()
+			if err != nil {
+				HandleError(err, "list sessions")
+			}
+		}
+
+		rows, err := buildUsageRows(ctx.Client, sessions, groupBy, cutoff)
+		if err != nil {
+			HandleError(err, "summarize usage")
+		}
+
+		switch format {
+		case "table":
+			printUsageTable(rows, groupBy)
+		case "json":
+			data, err := json.MarshalIndent(rows, "", "  ")
+			if err != nil {
+				HandleError(err, "encode usage")
+			}
+			fmt.Println(string(data))
+		case "csv":
+			if err := writeUsageCSV(rows, groupBy); err != nil {
+				HandleError(err, "write usage")
+			}
+		default:
+			HandleError(fmt.Errorf("unknown format '%s', use table, json or csv", format), "show usage")
+		}
+	},
+}
+
+// usageRow is the usage of one session, project or day
+type usageRow struct {
+	Key string `json:"key"`
+	interfaces.TokenUsage
+	TotalTokens    int64   `json:"total_tokens"`
+	Requests       int     `json:"requests"`
+	ContextPercent float64 `json:"context_percent,omitempty"` // Only when grouped by session
+	Model          string  `json:"model,omitempty"`           // Only when grouped by session
+}
+
+// buildUsageRows groups the usage of sessions by session, project or day,
+// counting only requests made at or after cutoff
+func buildUsageRows(client *api.Client, sessions []*api.Session, groupBy string, cutoff time.Time) ([]*usageRow, error) {
+	if groupBy != "session" && groupBy != "project" && groupBy != "day" {
+		return nil, fmt.Errorf("unknown grouping '%s', use session, project or day", groupBy)
+	}
+
+	rows := make(map[string]*usageRow)
+	for _, session := range sessions {
+		records, err := client.GetUsage(session.ID)
+		if err != nil {
+			// Keep stdout clean for JSON and CSV output
+			fmt.Fprintln(os.Stderr, ui.WarningMsg(fmt.Sprintf("Skipping session '%s': %v", session.Name, err)))
+			continue
+		}
+
+		for _, record := range records {
+			if record.Timestamp.Before(cutoff) {
+				continue
+			}
+
+			var key string
+			switch groupBy {
+			case "session":
+				key = session.Name
+			case "project":
+				key = session.ProjectPath
+			case "day":
+				key = record.Timestamp.Local().Format("2006-01-02")
+			}
+
+			row, ok := rows[key]
+			if !ok {
+				row = &usageRow{Key: key}
+				rows[key] = row
+			}
+			row.Add(record.TokenUsage)
+			row.Requests++
+		}
+
+		if row, ok := rows[session.Name]; ok && groupBy == "session" && session.Usage != nil {
+			row.ContextPercent = session.Usage.ContextPercent()
+			row.Model = session.Usage.Model
+		}
+	}
+
+	result := make([]*usageRow, 0, len(rows))
+	for _, row := range rows {
+		row.TotalTokens = row.TokenUsage.TotalTokens()
+		result = append(result, row)
+	}
+
+	// Days read best in order; sessions and projects by what they cost
+	sort.Slice(result, func(i, j int) bool {
+		if groupBy == "day" {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Cost > result[j].Cost
+	})
+
+	return result, nil
+}
+
+// printUsageTable prints usage rows as an aligned table with a total line
+func printUsageTable(rows []*usageRow, groupBy string) {
+	if len(rows) == 0 {
+		fmt.Println(ui.InfoMsg("No usage found"))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "\tInput\tOutput\tCache Write\tCache Read\tCost\t"
+	if groupBy == "session" {
+		header += "Context\t"
+	}
+	fmt.Fprintln(w, usageKeyHeader(groupBy)+header)
+
+	var total interfaces.TokenUsage
+	for _, row := range rows {
+		line := fmt.Sprintf("%s\t%d\t%d\t%d\t%d\t$%.2f\t", row.Key,
+			row.InputTokens, row.OutputTokens, row.CacheCreationTokens, row.CacheReadTokens, row.Cost)
+		if groupBy == "session" {
+			line += fmt.Sprintf("%.0f%%\t", row.ContextPercent)
+		}
+		fmt.Fprintln(w, line)
+		total.Add(row.TokenUsage)
+	}
+	fmt.Fprintf(w, "Total\t%d\t%d\t%d\t%d\t$%.2f\t\n",
+		total.InputTokens, total.OutputTokens, total.CacheCreationTokens, total.CacheReadTokens, total.Cost)
+	w.Flush()
+}
+
+// writeUsageCSV writes usage rows as CSV to stdout
+func writeUsageCSV(rows []*usageRow, groupBy string) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	header := []string{usageKeyHeader(groupBy), "Input", "Output", "Cache Write", "Cache Read", "Total Tokens", "Requests", "Cost"}
+	if groupBy == "session" {
+		header = append(header, "Context", "Model")
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.Key,
+			strconv.FormatInt(row.InputTokens, 10),
+			strconv.FormatInt(row.OutputTokens, 10),
+			strconv.FormatInt(row.CacheCreationTokens, 10),
+			strconv.FormatInt(row.CacheReadTokens, 10),
+			strconv.FormatInt(row.TotalTokens, 10),
+			strconv.Itoa(row.Requests),
+			fmt.Sprintf("%.4f", row.Cost),
+		}
+		if groupBy == "session" {
+			record = append(record, fmt.Sprintf("%.1f", row.ContextPercent), row.Model)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// usageKeyHeader returns the column header for a grouping
+func usageKeyHeader(groupBy string) string {
+	switch groupBy {
+	case "project":
+		return "Project"
+	case "day":
+		return "Day"
+	default:
+		return "Session"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	// Add flags
+	usageCmd.Flags().String("by", "session", "Group usage by session, project or day")
+	usageCmd.Flags().String("since", "", "Only count usage since a duration ago (e.g. 24h or 7d) or a date")
+	usageCmd.Flags().StringP("format", "f", "table", "Output format: table, json or csv")
+}
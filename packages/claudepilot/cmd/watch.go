@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Notify when a session needs your attention",
+	Long: `Watch your sessions and send a notification whenever the Claude agent in one
+of them starts waiting for input, asks for a tool permission, or stops on an
+error. Notifications are shown in attached tmux clients, ring the terminal
+bell, run a command, or are appended to a file, as configured in the
+notifications section of the configuration.
+
+Run it in a spare terminal or tmux window; it keeps watching until interrupted.
+Use 'claude-pilot mute' to silence individual sessions.
+
+Examples:
+  claude-pilot watch              # Watch all sessions until Ctrl+C`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		watchCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Println(ui.InfoMsg("Watching sessions for notifications, press Ctrl+C to stop"))
+
+		err = ctx.Client.WatchNotifications(watchCtx, func(n api.Notification) {
+			fmt.Printf("%s %s %s\n", ui.Dim(n.Time.Format("15:04:05")), ui.Arrow(), n.Message())
+		})
+		if err != nil {
+			HandleError(err, "watch sessions")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"claude-pilot/core/api"
+	"claude-pilot/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var upCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Bring up the sessions of the repository's workspace file",
+	Long: `Bring up the sessions declared in the workspace file, ` + api.WorkspaceFileName + `, found in the
+current directory or the closest of its parents. Sessions are created in the
+order they are declared, with their command, Claude options, working directory
+(relative to the file), panes and windows, and initial prompt.
+
+up can be run again at any time: sessions that are running are left as they are
+and inactive ones are resumed, along with their panes and windows. Missing panes
+and windows are added to the session they are attached to, even a running one.
+
+A workspace file looks like:
+
+  sessions:
+    - name: api
+      working_dir: api
+      model: opus
+      prompt: Read the README and wait for instructions.
+    - name: api-tests
+      attach_to: api
+      split: h
+      command: go test ./... -count=1
+    - name: web
+      working_dir: web
+      template: frontend   # a template of the config file
+
+Examples:
+  claude-pilot up                       # Bring up the workspace of the repository
+  claude-pilot up -f ~/work/app/` + api.WorkspaceFileName + `  # Use a given workspace file`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		file, _ := cmd.Flags().GetString("file")
+
+		ws, err := loadWorkspace(file)
+		if err != nil {
+			HandleError(err, "load workspace")
+		}
+
+		results, err := ctx.Client.WorkspaceUp(ws)
+		printWorkspaceResults(results)
+		if err != nil {
+			HandleError(err, "bring up workspace")
+		}
+
+		fmt.Println()
+		fmt.Println(ui.NextSteps(
+			fmt.Sprintf("claude-pilot attach %s", ws.Sessions[0].Name),
+			"claude-pilot list",
+		))
+	},
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Kill the sessions of the repository's workspace file",
+	Long: `Kill the sessions declared in the workspace file, ` + api.WorkspaceFileName + `, found in the
+current directory or the closest of its parents, along with their panes and
+windows. Sessions that were not started from the workspace file are left alone.
+
+Git worktrees of the sessions are kept unless --remove-worktrees is given; those
+with uncommitted changes are kept either way.
+
+Examples:
+  claude-pilot down                     # Kill the sessions of the workspace
+  claude-pilot down --remove-worktrees  # Also remove their clean worktrees`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize common command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "initialize command")
+		}
+
+		// Get flags
+		file, _ := cmd.Flags().GetString("file")
+		removeWorktrees, _ := cmd.Flags().GetBool("remove-worktrees")
+
+		ws, err := loadWorkspace(file)
+		if err != nil {
+			HandleError(err, "load workspace")
+		}
+
+		results, err := ctx.Client.WorkspaceDown(ws)
+		printWorkspaceResults(results)
+
+		for _, result := range results {
+			if result.Session == nil || result.Session.Worktree == nil {
+				continue
+			}
+			wt := result.Session.Worktree
+			if !removeWorktrees {
+				fmt.Println(ui.InfoMsg(fmt.Sprintf("Kept worktree %s on branch %s", wt.Path, wt.Branch)))
+				continue
+			}
+			if err := ctx.Client.RemoveWorktree(wt, false); errors.Is(err, api.ErrWorktreeDirty) {
+				fmt.Println(ui.WarningMsg(fmt.Sprintf("Kept worktree %s, it has uncommitted changes", wt.Path)))
+			} else if err != nil {
+				fmt.Println(ui.ErrorMsg(fmt.Sprintf("Failed to remove worktree %s: %v", wt.Path, err)))
+			} else {
+				fmt.Println(ui.SuccessMsg(fmt.Sprintf("Removed worktree %s", wt.Path)))
+			}
+		}
+
+		if err != nil {
+			HandleError(err, "tear down workspace")
+		}
+	},
+}
+
+// loadWorkspace loads the given workspace file, or the one of the current
+// directory or its parents
+func loadWorkspace(file string) (*api.Workspace, error) {
+	if file == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		if file, err = api.FindWorkspace(cwd); err != nil {
+			return nil, err
+		}
+	}
+	return api.LoadWorkspace(file)
+}
+
+// printWorkspaceResults prints what was done with each session of a workspace
+func printWorkspaceResults(results []api.WorkspaceResult) {
+	for _, result := range results {
+		message := fmt.Sprintf("%s: %s", result.Name, result.Action)
+		switch result.Action {
+		case api.WorkspaceCreated, api.WorkspaceResumed, api.WorkspaceKilled:
+			fmt.Println(ui.SuccessMsg(message))
+		case api.WorkspaceForeign:
+			fmt.Println(ui.WarningMsg(message))
+		default:
+			fmt.Println(ui.InfoMsg(message))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(upCmd)
+	rootCmd.AddCommand(downCmd)
+
+	// Add flags
+	upCmd.Flags().StringP("file", "f", "", "Workspace file (default: "+api.WorkspaceFileName+" of the current directory or a parent)")
+	downCmd.Flags().StringP("file", "f", "", "Workspace file (default: "+api.WorkspaceFileName+" of the current directory or a parent)")
+	downCmd.Flags().Bool("remove-worktrees", false, "Remove the git worktrees of the sessions, unless they have uncommitted changes")
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"claude-pilot/shared/interfaces"
 	"claude-pilot/shared/styles"
@@ -101,8 +102,12 @@ func FormatStatus(status string) string {
 		return styles.StatusInactive(status)
 	case "connected":
 		return styles.StatusConnected(status)
-	case "error":
+	case "error", "failed":
 		return styles.StatusError(status)
+	case "running":
+		return styles.InfoStyle.Render("▶ " + status)
+	case "succeeded":
+		return styles.SuccessStyle.Render("✓ " + status)
 	default:
 		return styles.Dim("● " + status)
 	}
@@ -179,14 +184,79 @@ func SessionDetailsFormatted(session *interfaces.Session, backend string) string
 	lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("ID:"), session.ID))
 	lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Name:"), styles.Title(session.Name)))
 	lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Status:"), FormatStatus(string(session.Status))))
+	if session.AgentState != interfaces.AgentUnknown {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Agent:"), styles.FormatAgentState(string(session.AgentState))))
+	}
+	if session.Muted {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Notifications:"), styles.Dim("muted")))
+	}
+	if session.Backend == interfaces.HeadlessBackend {
+		backend = session.Backend
+	}
 	lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Backend:"), backend))
 	lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Created:"), session.CreatedAt.Format("2006-01-02 15:04:05")))
 	if session.ProjectPath != "" {
 		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Project:"), session.ProjectPath))
 	}
+	if session.ParentID != "" {
+		attached := fmt.Sprintf("%s of session %s", session.Attachment, session.ParentID)
+		if session.Target != "" {
+			attached = fmt.Sprintf("%s %s of session %s", session.Attachment, session.Target, session.ParentID)
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Attached as:"), attached))
+	}
+	if session.Command != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Command:"), session.Command))
+	}
+	if args := session.Launch.CommandArgs(); len(args) > 0 {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Claude options:"), strings.Join(args, " ")))
+	}
+	if wt := session.Worktree; wt != nil {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Repository:"), wt.Repo))
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Branch:"), wt.Branch))
+	}
 	if session.Description != "" {
 		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Description:"), session.Description))
 	}
+	if len(session.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Tags:"), strings.Join(session.Tags, ", ")))
+	}
+	if session.Group != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Group:"), session.Group))
+	}
+	if session.Workspace != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Workspace:"), session.Workspace))
+	}
+	if len(session.Blueprint) > 0 {
+		panes := make([]string, len(session.Blueprint))
+		for i, pane := range session.Blueprint {
+			panes[i] = PaneSummary(pane.Command, string(pane.SplitDirection), pane.Size)
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Extra panes:"), strings.Join(panes, "; ")))
+	}
+	if run := session.Run; run != nil {
+		lines = append(lines, runDetails(run, labelWidth)...)
+	}
+	if session.LogFile != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Output log:"), session.LogFile))
+	}
+	if session.ConversationID != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Conversation:"), session.ConversationID))
+	}
+	if hook := session.LastHook; hook != nil {
+		event := hook.Name
+		if hook.ToolName != "" {
+			event += " (" + hook.ToolName + ")"
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %s at %s", labelWidth, styles.Bold("Last hook:"), event, hook.Time.Format("2006-01-02 15:04:05")))
+	}
+	if usage := session.Usage; usage != nil {
+		lines = append(lines, fmt.Sprintf("%-*s %d input, %d output, %d cache write, %d cache read", labelWidth, styles.Bold("Tokens:"),
+			usage.InputTokens, usage.OutputTokens, usage.CacheCreationTokens, usage.CacheReadTokens))
+		lines = append(lines, fmt.Sprintf("%-*s $%.2f", labelWidth, styles.Bold("Cost:"), usage.Cost))
+		lines = append(lines, fmt.Sprintf("%-*s %.0f%% of %d tokens (%s)", labelWidth, styles.Bold("Context:"),
+			usage.ContextPercent(), usage.ContextWindow, usage.Model))
+	}
 	return strings.Join(lines, "\n")
 }
 
@@ -222,3 +292,44 @@ func CommandList(commands map[string]string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// PaneSummary describes an extra pane, e.g. "go test ./... (h, 30%)"
+func PaneSummary(command, split string, size int) string {
+	if command == "" {
+		command = "shell"
+	}
+	var layout []string
+	if split != "" {
+		layout = append(layout, split)
+	}
+	if size > 0 {
+		layout = append(layout, fmt.Sprintf("%d%%", size))
+	}
+	if len(layout) == 0 {
+		return command
+	}
+	return fmt.Sprintf("%s (%s)", command, strings.Join(layout, ", "))
+}
+
+// runDetails formats the prompt and outcome of a headless run
+func runDetails(run *interfaces.HeadlessRun, labelWidth int) []string {
+	prompt, _, multiline := strings.Cut(strings.TrimSpace(run.Prompt), "\n")
+	if multiline {
+		prompt += " …"
+	}
+
+	lines := []string{fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Prompt:"), prompt)}
+	if run.Finished() {
+		lines = append(lines, fmt.Sprintf("%-*s %s (exit code %d)", labelWidth, styles.Bold("Finished:"), run.FinishedAt.Format("2006-01-02 15:04:05"), run.ExitCode))
+	}
+	if run.NumTurns > 0 {
+		lines = append(lines, fmt.Sprintf("%-*s %d turns in %s, $%.2f", labelWidth, styles.Bold("Run:"), run.NumTurns, run.Duration.Round(time.Second), run.Cost))
+	}
+	if run.Error != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Error:"), styles.ErrorStyle.Render(run.Error)))
+	}
+	if run.Result != "" {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, styles.Bold("Result:"), run.Result))
+	}
+	return lines
+}
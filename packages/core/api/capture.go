@@ -0,0 +1,20 @@
+package api
+
+import (
+	"claude-pilot/core/internal/ansi"
+	"claude-pilot/shared/interfaces"
+)
+
+// CaptureOptions selects what is captured from a session (re-exported for convenience)
+type CaptureOptions = interfaces.CaptureOptions
+
+// Capture returns the screen of a running session's pane without attaching
+// to it, optionally with scrollback and ANSI colors
+func (c *Client) Capture(identifier string, opts CaptureOptions) (string, error) {
+	return c.service.Capture(identifier, opts)
+}
+
+// CaptureHTML renders a capture taken with ANSI colors as a standalone HTML page
+func CaptureHTML(title, capture string) string {
+	return ansi.HTMLDocument(title, capture)
+}
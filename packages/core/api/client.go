@@ -3,10 +3,13 @@ package api
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"claude-pilot/core/internal/claude"
 	"claude-pilot/core/internal/config"
 	"claude-pilot/core/internal/logger"
 	"claude-pilot/core/internal/multiplexer"
+	"claude-pilot/core/internal/outputlog"
 	"claude-pilot/core/internal/service"
 	"claude-pilot/core/internal/storage"
 	"claude-pilot/shared/interfaces"
@@ -24,6 +27,10 @@ type Client struct {
 type ClientConfig struct {
 	ConfigFile string
 	Verbose    bool
+
+	// Multiplexer overrides the configured backend, e.g. with a
+	// multiplexertest.FakeMultiplexer in tests
+	Multiplexer interfaces.TerminalMultiplexer
 }
 
 // NewClient creates a new API client with the specified configuration
@@ -55,30 +62,47 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
-	// Create multiplexer instance based on configuration
-	mux, err := multiplexer.CreateMultiplexer(config.Backend, config.Tmux.SessionPrefix)
-	if err != nil {
-		log.Error("Failed to create multiplexer",
-			"backend", config.Backend,
-			"prefix", config.Tmux.SessionPrefix,
-			"error", err)
-		return nil, fmt.Errorf("failed to create multiplexer: %w", err)
+	// Create multiplexer instance based on configuration unless one was provided
+	mux := cfg.Multiplexer
+	if mux == nil {
+		mux, err = newMultiplexer(config, config.Tmux.ControlMode, log)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create repository
-	repository, err := storage.NewFileSessionRepository(config.SessionsDir)
+	repository, err := newRepository(config, log)
 	if err != nil {
-		log.Error("Failed to create repository",
-			"sessions_dir", config.SessionsDir,
-			"error", err)
-		return nil, fmt.Errorf("failed to create repository: %w", err)
+		return nil, err
 	}
 
 	// Create service with logger
 	sessionService := service.NewSessionServiceWithLogger(repository, mux, log)
 
+	prices := make(claude.PriceTable, len(config.Usage.Prices))
+	for model, price := range config.Usage.Prices {
+		prices[model] = claude.Price(price)
+	}
+	sessionService.SetPricing(prices, config.Usage.ContextWindow)
+
+	sessionService.SetClaudeCommand(config.DefaultShell)
+	sessionService.SetRunsDir(filepath.Join(config.SessionsDir, "runs"))
+	sessionService.SetWorktreesDir(config.Worktrees.Dir)
+
+	sessionService.SetOutputLogging(service.OutputLogging{
+		Enabled: config.SessionLogs.Enabled,
+		Dir:     config.SessionsDir,
+		Archive: config.SessionLogs.OnDelete == "archive",
+		Options: outputlog.Options{
+			MaxSize:   config.SessionLogs.MaxSize * 1024 * 1024,
+			MaxFiles:  config.SessionLogs.MaxFiles,
+			StripANSI: config.SessionLogs.StripANSI,
+		},
+	})
+
 	log.Info("Client initialized successfully",
-		"backend", config.Backend,
+		"backend", mux.GetName(),
 		"sessions_dir", config.SessionsDir,
 		"ui_mode", config.UI.Mode,
 		"logging_enabled", config.Logging.Enabled,
@@ -92,6 +116,40 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 	}, nil
 }
 
+// newMultiplexer creates the configured multiplexer backend
+func newMultiplexer(config *config.Config, controlMode bool, log *logger.Logger) (interfaces.TerminalMultiplexer, error) {
+	mux, err := multiplexer.CreateMultiplexerWithOptions(config.Backend, multiplexer.Options{
+		SessionPrefix:   config.Tmux.SessionPrefix,
+		BinaryPath:      config.BackendPath,
+		TmuxSocketName:  config.Tmux.SocketName,
+		TmuxSocketPath:  config.Tmux.SocketPath,
+		TmuxControlMode: controlMode,
+		TmuxLayout:      config.Tmux.DefaultLayout,
+		Logger:          log,
+	})
+	if err != nil {
+		log.Error("Failed to create multiplexer",
+			"backend", config.Backend,
+			"backend_path", config.BackendPath,
+			"prefix", config.Tmux.SessionPrefix,
+			"error", err)
+		return nil, fmt.Errorf("failed to create multiplexer: %w", err)
+	}
+	return mux, nil
+}
+
+// newRepository creates the repository of session metadata
+func newRepository(config *config.Config, log *logger.Logger) (*storage.FileSessionRepository, error) {
+	repository, err := storage.NewFileSessionRepository(config.SessionsDir)
+	if err != nil {
+		log.Error("Failed to create repository",
+			"sessions_dir", config.SessionsDir,
+			"error", err)
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	return repository, nil
+}
+
 // GetConfig returns the current configuration
 func (c *Client) GetConfig() *config.Config {
 	return c.config
@@ -115,19 +173,43 @@ type CreateSessionRequest struct {
 	AttachTo       string                    // Target session to attach to
 	AttachmentType interfaces.AttachmentType // How to attach (pane, window, or standalone)
 	SplitDirection interfaces.SplitDirection // Direction for pane splits
+	Tags           []string
+	LogOutput      *bool  // Log the session's output (default: session_logs.enabled)
+	Worktree       *bool  // Run in a new git worktree of ProjectPath's repository (default: the template's choice, else no)
+	Branch         string // Branch of the worktree (default: the session name)
+	Launch         *LaunchOptions
+	Command        string            // Program to run (default: default_shell)
+	Env            map[string]string // Environment variables of the session
+	Prompt         string            // Initial prompt Claude starts with
+	Workspace      string            // Workspace file declaring the session
+	Blueprint      []BlueprintPane   // Extra panes next to Claude
 }
 
 // CreateSession creates a new session with the specified parameters
 func (c *Client) CreateSession(req CreateSessionRequest) (*interfaces.Session, error) {
+	command := req.Command
+	if command == "" {
+		command = c.config.DefaultShell
+	}
+
 	// Convert API request to service request
 	serviceReq := interfaces.CreateSessionRequest{
 		Name:           req.Name,
 		Description:    req.Description,
 		WorkingDir:     req.ProjectPath,
-		Command:        "claude",
+		Command:        command,
 		AttachTo:       req.AttachTo,
 		AttachmentType: req.AttachmentType,
 		SplitDirection: req.SplitDirection,
+		Tags:           req.Tags,
+		LogOutput:      req.LogOutput,
+		Worktree:       req.Worktree != nil && *req.Worktree,
+		Branch:         req.Branch,
+		Launch:         req.Launch,
+		Env:            req.Env,
+		Prompt:         req.Prompt,
+		Workspace:      req.Workspace,
+		Blueprint:      req.Blueprint,
 	}
 
 	return c.service.CreateSessionAdvanced(serviceReq)
@@ -143,16 +225,97 @@ func (c *Client) ListFilteredSessions(filter string) ([]*interfaces.Session, err
 	return c.service.ListFilteredSessions(filter)
 }
 
+// WithAgentState detects what the Claude agent in each running session is
+// doing and returns the sessions. It reads their panes, so it is only used
+// where the state is shown.
+func (c *Client) WithAgentState(sessions []*interfaces.Session) []*interfaces.Session {
+	c.service.DetectAgentStates(sessions)
+	return sessions
+}
+
+// NestSessions orders sessions so that the panes and windows attached to a
+// session follow it. Those whose session is not among them keep their place.
+func NestSessions(sessions []*interfaces.Session) []*interfaces.Session {
+	present := make(map[string]bool, len(sessions))
+	children := make(map[string][]*interfaces.Session)
+	for _, session := range sessions {
+		present[session.ID] = true
+	}
+	for _, session := range sessions {
+		if present[session.ParentID] {
+			children[session.ParentID] = append(children[session.ParentID], session)
+		}
+	}
+
+	nested := make([]*interfaces.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !present[session.ParentID] {
+			nested = append(nested, session)
+			nested = append(nested, children[session.ID]...)
+		}
+	}
+	return nested
+}
+
 // GetSession retrieves a session by ID or name
 func (c *Client) GetSession(identifier string) (*interfaces.Session, error) {
 	return c.service.GetSession(identifier)
 }
 
+// GetSessionDetails retrieves a session with everything shown about it in
+// its details, which takes longer than GetSession
+func (c *Client) GetSessionDetails(identifier string) (*interfaces.Session, error) {
+	return c.service.GetSessionDetails(identifier)
+}
+
 // AttachToSession connects to an existing session
 func (c *Client) AttachToSession(identifier string) error {
 	return c.service.AttachToSession(identifier)
 }
 
+// ResumeSession recreates the multiplexer session of an inactive session,
+// continuing its last Claude conversation
+func (c *Client) ResumeSession(identifier string) (*interfaces.Session, error) {
+	return c.service.ResumeSession(identifier)
+}
+
+// ResumeTargets returns the inactive sessions to resume to bring all of them
+// back; panes and windows of inactive sessions are resumed along with them
+func (c *Client) ResumeTargets() ([]*interfaces.Session, error) {
+	return c.service.ResumeTargets()
+}
+
+// GetMessages returns the messages of the Claude conversation in a session,
+// read from Claude Code's transcript
+func (c *Client) GetMessages(identifier string) ([]Message, error) {
+	return c.service.GetMessages(identifier)
+}
+
+// GetUsage returns the token usage and cost of each Claude API request made
+// in a session
+func (c *Client) GetUsage(identifier string) ([]UsageRecord, error) {
+	return c.service.GetUsage(identifier)
+}
+
+// SetMuted turns notifications for a session off or back on
+func (c *Client) SetMuted(identifier string, muted bool) (*interfaces.Session, error) {
+	return c.service.SetMuted(identifier, muted)
+}
+
+// SendInput types text into a running session without attaching to it.
+// Options select the pane, keys pressed before the text such as Escape or
+// C-c, and whether Enter submits the text.
+func (c *Client) SendInput(identifier, text string, opts SendInputOptions) error {
+	return c.service.SendInput(identifier, text, opts)
+}
+
+// InputTargets returns the running sessions input can be sent to together,
+// optionally only those with tag. Headless runs and attached panes and
+// windows are left out.
+func (c *Client) InputTargets(tag string) ([]*interfaces.Session, error) {
+	return c.service.InputTargets(tag)
+}
+
 // KillSession terminates a specific session
 func (c *Client) KillSession(identifier string) error {
 	return c.service.DeleteSession(identifier)
@@ -173,6 +336,23 @@ func (c *Client) GetSessionPaneCount(identifier string) (int, error) {
 	return c.service.GetSessionPaneCount(identifier)
 }
 
+// Reconcile adopts multiplexer sessions that have no metadata and reports,
+// or with opts.Prune deletes, metadata whose multiplexer session is gone
+func (c *Client) Reconcile(opts interfaces.ReconcileOptions) (*interfaces.ReconcileResult, error) {
+	return c.service.Reconcile(opts)
+}
+
+// SubscribeSessionEvents returns a channel that receives multiplexer state
+// changes and a function that ends the subscription. The channel is nil when
+// the backend cannot push changes, in which case callers should poll.
+func (c *Client) SubscribeSessionEvents() (<-chan interfaces.SessionEvent, func()) {
+	source, ok := c.multiplexer.(interfaces.SessionEventSource)
+	if !ok {
+		return nil, func() {}
+	}
+	return source.SubscribeSessionEvents()
+}
+
 // Session represents a session with all its data (re-exported for convenience)
 type Session = interfaces.Session
 
@@ -182,10 +362,25 @@ type SessionStatus = interfaces.SessionStatus
 // Message represents a message in a session (re-exported for convenience)
 type Message = interfaces.Message
 
+// ToolCall represents a tool invocation in a message (re-exported for convenience)
+type ToolCall = interfaces.ToolCall
+
+// SendInputOptions controls how input is sent to a session (re-exported for convenience)
+type SendInputOptions = interfaces.SendInputOptions
+
+// UsageRecord represents the usage of one Claude API request (re-exported for convenience)
+type UsageRecord = interfaces.UsageRecord
+
+// LaunchOptions are the command line options Claude is started with (re-exported for convenience)
+type LaunchOptions = interfaces.LaunchOptions
+
 // Status constants (re-exported for convenience)
 const (
 	StatusActive    = interfaces.StatusActive
 	StatusInactive  = interfaces.StatusInactive
 	StatusConnected = interfaces.StatusConnected
 	StatusError     = interfaces.StatusError
+	StatusRunning   = interfaces.StatusRunning
+	StatusSucceeded = interfaces.StatusSucceeded
+	StatusFailed    = interfaces.StatusFailed
 )
@@ -0,0 +1,33 @@
+package api
+
+import "claude-pilot/shared/interfaces"
+
+// FanoutRequest contains parameters for starting one task in several sessions
+type FanoutRequest struct {
+	Name        string // Group ID, and prefix of the sessions' names and branches
+	Description string
+	ProjectPath string
+	Prompt      string
+	Count       int
+	Tags        []string
+}
+
+// Fanout creates Count sessions that start on the same prompt, each in its
+// own git worktree branched from HEAD, linked by the group Name. When some
+// sessions fail, the others are returned with the error.
+func (c *Client) Fanout(req FanoutRequest) ([]*interfaces.Session, error) {
+	return c.service.Fanout(interfaces.FanoutRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		WorkingDir:  req.ProjectPath,
+		Command:     c.config.DefaultShell,
+		Prompt:      req.Prompt,
+		Count:       req.Count,
+		Tags:        req.Tags,
+	})
+}
+
+// ListGroupSessions returns the sessions of a fanout group
+func (c *Client) ListGroupSessions(group string) ([]*interfaces.Session, error) {
+	return c.service.ListGroupSessions(group)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/core/internal/headless"
+	"claude-pilot/shared/interfaces"
+)
+
+// HeadlessBackend is the backend name of headless runs (re-exported for convenience)
+const HeadlessBackend = interfaces.HeadlessBackend
+
+// HeadlessRunnerCommand is the hidden subcommand that runs headless sessions
+// in the background; binaries must dispatch it to RunHeadlessRunner
+const HeadlessRunnerCommand = headless.RunnerCommand
+
+// RunHeadlessRunner runs Claude for a headless session with the given arguments
+func RunHeadlessRunner(args []string) error {
+	return headless.RunRunner(args)
+}
+
+// RunRequest contains parameters for starting a headless run
+type RunRequest struct {
+	Name        string
+	Description string
+	ProjectPath string
+	Prompt      string
+	Args        []string // Extra arguments for claude, e.g. "--model" "opus"
+	Tags        []string
+}
+
+// RunHeadless starts claude -p with a prompt in the background, tracked as a
+// session whose status tells whether the run is running, succeeded or failed
+func (c *Client) RunHeadless(req RunRequest) (*interfaces.Session, error) {
+	return c.service.RunHeadless(interfaces.RunRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		WorkingDir:  req.ProjectPath,
+		Prompt:      req.Prompt,
+		Args:        req.Args,
+		Tags:        req.Tags,
+	})
+}
+
+// DescribeRunEvent turns a line of a headless run's log, a stream-json
+// event, into readable text; false means there is nothing to show for it
+func DescribeRunEvent(line string) (string, bool) {
+	return claude.DescribeStreamEvent(line)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"io"
+
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/core/internal/config"
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/core/internal/service"
+	"claude-pilot/shared/interfaces"
+)
+
+// HookEvent is an event reported by a Claude Code hook (re-exported for convenience)
+type HookEvent = interfaces.HookEvent
+
+// HookOrigin tells where a Claude Code hook ran (re-exported for convenience)
+type HookOrigin = interfaces.HookOrigin
+
+// SessionIDEnv is the environment variable holding the ID of the session a
+// process runs in
+const SessionIDEnv = interfaces.SessionIDEnv
+
+// ReadHookEvent reads the JSON a Claude Code hook command receives on stdin
+func ReadHookEvent(r io.Reader) (*HookEvent, error) {
+	return claude.ParseHookInput(r)
+}
+
+// InstallHooks adds command as a Claude Code hook to the shared settings of
+// the project at projectPath, returning the settings file and the hook
+// events that were added
+func InstallHooks(projectPath, command string) (string, []string, error) {
+	path := claude.SettingsPath(projectPath)
+	added, err := claude.InstallHooks(path, command)
+	return path, added, err
+}
+
+// NewHookClient creates a client that only records hook events. Claude Code
+// runs a hook for every tool call, so unlike NewClient it sets up no output
+// logs, pricing or tmux control client. It logs to the log file only, since
+// Claude Code may read what hooks print.
+func NewHookClient(cfg ClientConfig) (*Client, error) {
+	config, err := config.NewConfigManager(cfg.ConfigFile).Load()
+	if err != nil {
+		return nil, fmt.Errorf("load configuration: %w", err)
+	}
+
+	log, err := logger.NewBuilder().
+		WithEnabled(config.Logging.Enabled || cfg.Verbose).
+		WithLevel(config.Logging.Level).
+		WithFile(config.Logging.File).
+		WithMaxSize(config.Logging.MaxSize).
+		WithTUIMode(true).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	mux := cfg.Multiplexer
+	if mux == nil {
+		mux, err = newMultiplexer(config, false, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+	repository, err := newRepository(config, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		config:      config,
+		logger:      log,
+		service:     service.NewSessionServiceWithLogger(repository, mux, log),
+		multiplexer: mux,
+	}, nil
+}
+
+// RecordHookEvent records a Claude Code hook event in the session it came from
+func (c *Client) RecordHookEvent(event HookEvent, origin HookOrigin) (*Session, error) {
+	return c.service.RecordHookEvent(event, origin)
+}
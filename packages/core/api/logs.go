@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"claude-pilot/core/internal/outputlog"
+)
+
+// OutputLine is a line of a session's output log (re-exported for convenience)
+type OutputLine = outputlog.Line
+
+// ReadOutputLog returns the lines a session printed at or after since, as
+// kept in its output log
+func (c *Client) ReadOutputLog(identifier string, since time.Time) ([]OutputLine, error) {
+	path, err := c.outputLogFile(identifier)
+	if err != nil {
+		return nil, err
+	}
+	return outputlog.Read(path, since)
+}
+
+// FollowOutputLog calls fn with the lines a session printed at or after
+// since, then with each new line as it is logged, until ctx is done
+func (c *Client) FollowOutputLog(ctx context.Context, identifier string, since time.Time, fn func(OutputLine)) error {
+	path, err := c.outputLogFile(identifier)
+	if err != nil {
+		return err
+	}
+	return outputlog.Follow(ctx, path, since, fn)
+}
+
+// outputLogFile returns the output log of a session
+func (c *Client) outputLogFile(identifier string) (string, error) {
+	session, err := c.service.GetSession(identifier)
+	if err != nil {
+		return "", err
+	}
+	if session.LogFile == "" {
+		return "", fmt.Errorf("output of session '%s' is not logged", session.Name)
+	}
+	return session.LogFile, nil
+}
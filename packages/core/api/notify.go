@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"claude-pilot/core/internal/notify"
+	"claude-pilot/shared/interfaces"
+)
+
+// Notification reports that a session needs attention (re-exported for convenience)
+type Notification = notify.Notification
+
+// WatchNotifications checks sessions at the configured interval until ctx is
+// done, sending a notification through the configured sinks whenever an
+// agent starts needing attention. onNotify, if not nil, is also called for
+// every notification.
+func (c *Client) WatchNotifications(ctx context.Context, onNotify func(Notification)) error {
+	cfg := c.config.Notifications
+
+	states := make([]interfaces.AgentState, len(cfg.States))
+	for i, state := range cfg.States {
+		states[i] = interfaces.AgentState(state)
+	}
+
+	notifier := notify.NewNotifier(notify.Options{
+		Debounce: cfg.Debounce,
+		States:   states,
+		Sinks:    c.notificationSinks(),
+		Logger:   c.logger,
+	})
+
+	c.logger.Info("Watching sessions for notifications",
+		"interval", cfg.Interval,
+		"debounce", cfg.Debounce,
+		"states", cfg.States)
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		sessions, err := c.service.ListSessions()
+		if err != nil {
+			// The multiplexer may come back, so keep watching
+			c.logger.Warn("Failed to list sessions for notifications", "error", err)
+		} else {
+			c.service.DetectAgentStates(sessions)
+			for _, notification := range notifier.Observe(sessions, time.Now()) {
+				if onNotify != nil {
+					onNotify(notification)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// notificationSinks creates the notification sinks enabled in the configuration
+func (c *Client) notificationSinks() []notify.Sink {
+	cfg := c.config.Notifications
+
+	var sinks []notify.Sink
+	if messenger, ok := c.multiplexer.(interfaces.ClientMessenger); ok && cfg.TmuxMessage {
+		sinks = append(sinks, notify.NewMessageSink(messenger))
+	}
+	if cfg.Bell {
+		sinks = append(sinks, notify.NewBellSink(os.Stdout))
+	}
+	if cfg.Command != "" {
+		sinks = append(sinks, notify.NewCommandSink(cfg.Command))
+	}
+	if cfg.File != "" {
+		sinks = append(sinks, notify.NewFileSink(cfg.File))
+	}
+	return sinks
+}
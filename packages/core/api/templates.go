@@ -0,0 +1,203 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"claude-pilot/core/internal/config"
+	"claude-pilot/shared/interfaces"
+)
+
+// SessionTemplate is a named preset for new sessions (re-exported for convenience)
+type SessionTemplate = config.SessionTemplate
+
+// BlueprintPane is an extra pane of a new session (re-exported for convenience)
+type BlueprintPane = interfaces.BlueprintPane
+
+// TemplateNames returns the names of the configured session templates, sorted
+func (c *Client) TemplateNames() []string {
+	names := make([]string, 0, len(c.config.Templates))
+	for name := range c.config.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTemplate returns the session template with the given name
+func (c *Client) GetTemplate(name string) (*SessionTemplate, error) {
+	// Config keys are case-insensitive
+	template, ok := c.config.Templates[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("template '%s' not found", name)
+	}
+	return &template, nil
+}
+
+// ApplyTemplate fills the fields of req that are not set from the named
+// template, so that values already in the request win, including a Worktree
+// explicitly set to false. Launch options and
+// environment variables are merged key by key and tags are combined. A
+// session without a name is named after the template and the current time.
+func (c *Client) ApplyTemplate(name string, req *CreateSessionRequest) error {
+	template, err := c.GetTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("%s-%s", strings.ToLower(name), time.Now().Format("20060102-150405"))
+	}
+	return applyTemplate(template, req)
+}
+
+// applyTemplate fills the fields of req that are not set from template
+func applyTemplate(template *SessionTemplate, req *CreateSessionRequest) error {
+	var err error
+	if req.ProjectPath == "" {
+		req.ProjectPath = template.WorkingDir
+	}
+	if req.ProjectPath, err = absPath(req.ProjectPath, ""); err != nil {
+		return fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	placeholders := strings.NewReplacer("{name}", req.Name, "{project}", filepath.Base(req.ProjectPath))
+	if req.Description == "" {
+		req.Description = placeholders.Replace(template.Description)
+	}
+	if req.Command == "" {
+		req.Command = template.Command
+	}
+	if req.Prompt == "" {
+		req.Prompt = template.Prompt
+	}
+	for _, tag := range template.Tags {
+		if !slices.Contains(req.Tags, tag) {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+
+	if req.Worktree == nil && template.Worktree {
+		worktree := true
+		req.Worktree = &worktree
+	}
+	if req.Worktree != nil && *req.Worktree && req.Branch == "" {
+		req.Branch = placeholders.Replace(template.Branch)
+	}
+
+	if req.AttachTo == "" && template.AttachTo != "" {
+		req.AttachTo = template.AttachTo
+		req.AttachmentType = interfaces.AttachmentPane
+		if template.Attachment == "window" {
+			req.AttachmentType = interfaces.AttachmentWindow
+		}
+	}
+	if req.SplitDirection == "" {
+		switch template.Split {
+		case "h":
+			req.SplitDirection = interfaces.SplitHorizontal
+		case "v":
+			req.SplitDirection = interfaces.SplitVertical
+		}
+	}
+
+	if len(template.Env) > 0 {
+		env := make(map[string]string, len(template.Env)+len(req.Env))
+		for _, variable := range template.Env {
+			key, value, _ := strings.Cut(variable, "=")
+			env[key] = value
+		}
+		for key, value := range req.Env {
+			env[key] = value
+		}
+		req.Env = env
+	}
+
+	if len(req.Blueprint) == 0 {
+		for _, pane := range template.Panes {
+			workingDir := ""
+			if pane.WorkingDir != "" {
+				if workingDir, err = absPath(pane.WorkingDir, req.ProjectPath); err != nil {
+					return fmt.Errorf("failed to resolve pane directory '%s': %w", pane.WorkingDir, err)
+				}
+			}
+			req.Blueprint = append(req.Blueprint, BlueprintPane{
+				Command:        pane.Command,
+				SplitDirection: interfaces.SplitDirection(pane.Split),
+				Size:           pane.Size,
+				WorkingDir:     workingDir,
+			})
+		}
+	}
+
+	req.Launch, err = mergeLaunchOptions(req.Launch, template, req.ProjectPath)
+	return err
+}
+
+// mergeLaunchOptions returns opts with the options it doesn't set taken from
+// template, whose paths are relative to the session's project directory
+func mergeLaunchOptions(opts *LaunchOptions, template *SessionTemplate, projectPath string) (*LaunchOptions, error) {
+	merged := LaunchOptions{}
+	if opts != nil {
+		merged = *opts
+	}
+
+	if merged.Model == "" {
+		merged.Model = template.Model
+	}
+	if merged.PermissionMode == "" {
+		merged.PermissionMode = template.PermissionMode
+	}
+	if len(merged.AllowedTools) == 0 {
+		merged.AllowedTools = template.AllowedTools
+	}
+	if len(merged.DisallowedTools) == 0 {
+		merged.DisallowedTools = template.DisallowedTools
+	}
+	if merged.AppendSystemPrompt == "" {
+		merged.AppendSystemPrompt = template.AppendSystemPrompt
+	}
+	if len(merged.Args) == 0 {
+		merged.Args = template.Args
+	}
+	if merged.MCPConfig == "" && template.MCPConfig != "" {
+		path, err := absPath(template.MCPConfig, projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve MCP config path: %w", err)
+		}
+		merged.MCPConfig = path
+	}
+	if len(merged.AddDirs) == 0 {
+		for _, dir := range template.AddDirs {
+			path, err := absPath(dir, projectPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve directory '%s': %w", dir, err)
+			}
+			merged.AddDirs = append(merged.AddDirs, path)
+		}
+	}
+
+	if len(merged.CommandArgs()) == 0 {
+		return nil, nil
+	}
+	return &merged, nil
+}
+
+// absPath expands ~ in path and makes it absolute relative to base, or to
+// the current directory when base is empty. An empty path resolves to base.
+func absPath(path, base string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path = config.ExpandHomePath(path, homeDir)
+	if base != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Abs(path)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"claude-pilot/core/internal/config"
+	"claude-pilot/shared/interfaces"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	project := t.TempDir()
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		template SessionTemplate
+		req      CreateSessionRequest
+		want     CreateSessionRequest
+	}{
+		{
+			name:     "template fills scalars",
+			template: SessionTemplate{Command: "my-claude", Prompt: "Review the diff", WorkingDir: project},
+			req:      CreateSessionRequest{Name: "api"},
+			want:     CreateSessionRequest{Name: "api", ProjectPath: project, Command: "my-claude", Prompt: "Review the diff"},
+		},
+		{
+			name:     "request scalars win",
+			template: SessionTemplate{Description: "From template", Command: "my-claude", Prompt: "Review the diff", WorkingDir: "/elsewhere"},
+			req:      CreateSessionRequest{Name: "api", Description: "Mine", ProjectPath: project, Command: "claude", Prompt: "Fix it"},
+			want:     CreateSessionRequest{Name: "api", Description: "Mine", ProjectPath: project, Command: "claude", Prompt: "Fix it"},
+		},
+		{
+			name:     "placeholders",
+			template: SessionTemplate{Description: "{name} on {project}", Worktree: true, Branch: "review/{name}"},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project},
+			want: CreateSessionRequest{Name: "api", ProjectPath: project, Description: "api on " + filepath.Base(project),
+				Worktree: &yes, Branch: "review/api"},
+		},
+		{
+			name:     "environment is merged",
+			template: SessionTemplate{Env: []string{"A=1", "B=2"}},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project, Env: map[string]string{"B": "3"}},
+			want:     CreateSessionRequest{Name: "api", ProjectPath: project, Env: map[string]string{"A": "1", "B": "3"}},
+		},
+		{
+			name:     "launch options are merged",
+			template: SessionTemplate{Model: "opus", AllowedTools: []string{"Edit"}, MCPConfig: "mcp.json"},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project, Launch: &LaunchOptions{Model: "sonnet"}},
+			want: CreateSessionRequest{Name: "api", ProjectPath: project,
+				Launch: &LaunchOptions{Model: "sonnet", AllowedTools: []string{"Edit"}, MCPConfig: filepath.Join(project, "mcp.json")}},
+		},
+		{
+			name:     "tags are combined",
+			template: SessionTemplate{Tags: []string{"review", "backend"}},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project, Tags: []string{"backend", "urgent"}},
+			want:     CreateSessionRequest{Name: "api", ProjectPath: project, Tags: []string{"backend", "urgent", "review"}},
+		},
+		{
+			name:     "template attaches",
+			template: SessionTemplate{AttachTo: "main", Attachment: "window", Split: "h"},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project},
+			want: CreateSessionRequest{Name: "api", ProjectPath: project, AttachTo: "main",
+				AttachmentType: interfaces.AttachmentWindow, SplitDirection: interfaces.SplitHorizontal},
+		},
+		{
+			name:     "request attachment wins",
+			template: SessionTemplate{AttachTo: "main", Attachment: "window", Split: "h"},
+			req: CreateSessionRequest{Name: "api", ProjectPath: project, AttachTo: "other",
+				AttachmentType: interfaces.AttachmentPane, SplitDirection: interfaces.SplitVertical},
+			want: CreateSessionRequest{Name: "api", ProjectPath: project, AttachTo: "other",
+				AttachmentType: interfaces.AttachmentPane, SplitDirection: interfaces.SplitVertical},
+		},
+		{
+			name:     "template panes",
+			template: SessionTemplate{Panes: []config.PaneTemplate{{Command: "go test ./...", Split: "h", Size: 30, WorkingDir: "src"}}},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project},
+			want: CreateSessionRequest{Name: "api", ProjectPath: project, Blueprint: []BlueprintPane{
+				{Command: "go test ./...", SplitDirection: interfaces.SplitHorizontal, Size: 30, WorkingDir: filepath.Join(project, "src")}}},
+		},
+		{
+			name:     "template worktree",
+			template: SessionTemplate{Worktree: true},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project},
+			want:     CreateSessionRequest{Name: "api", ProjectPath: project, Worktree: &yes},
+		},
+		{
+			name:     "request turns the worktree off",
+			template: SessionTemplate{Worktree: true, Branch: "review/{name}"},
+			req:      CreateSessionRequest{Name: "api", ProjectPath: project, Worktree: &no},
+			want:     CreateSessionRequest{Name: "api", ProjectPath: project, Worktree: &no},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if err := applyTemplate(&tt.template, &req); err != nil {
+				t.Fatalf("applyTemplate failed: %v", err)
+			}
+			if !reflect.DeepEqual(req, tt.want) {
+				t.Errorf("request = %+v\nwant %+v", req, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateNamesSession(t *testing.T) {
+	client := &Client{config: &config.Config{Templates: map[string]SessionTemplate{
+		"reviewer": {WorkingDir: t.TempDir()},
+	}}}
+
+	req := CreateSessionRequest{}
+	if err := client.ApplyTemplate("Reviewer", &req); err != nil {
+		t.Fatalf("ApplyTemplate failed: %v", err)
+	}
+	if !strings.HasPrefix(req.Name, "reviewer-") {
+		t.Errorf("session name = %q, want it named after the template", req.Name)
+	}
+
+	if err := client.ApplyTemplate("missing", &req); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"os"
 	"path/filepath"
+
+	"claude-pilot/core/internal/multiplexer"
+	"claude-pilot/core/internal/outputlog"
 )
 
 // DefaultConfigFile returns the default configuration file path
@@ -71,3 +74,28 @@ func GetProjectPath(projectPath string) string {
 
 	return projectPath
 }
+
+// NativeHolderCommand is the hidden subcommand the native backend uses to
+// start session holder processes; binaries must dispatch it to RunNativeHolder
+// and call EnableNativeBackend
+const NativeHolderCommand = multiplexer.NativeHolderCommand
+
+// RunNativeHolder runs a native session holder with the given arguments
+func RunNativeHolder(args []string) error {
+	return multiplexer.RunNativeHolder(args)
+}
+
+// EnableNativeBackend makes the native backend available, for binaries that
+// dispatch NativeHolderCommand
+func EnableNativeBackend() {
+	multiplexer.EnableNativeBackend()
+}
+
+// LogWriterCommand is the hidden subcommand that session output is piped into
+// when it is logged; binaries must dispatch it to RunLogWriter
+const LogWriterCommand = outputlog.WriterCommand
+
+// RunLogWriter writes session output read from stdin to a log
+func RunLogWriter(args []string) error {
+	return outputlog.RunWriter(args)
+}
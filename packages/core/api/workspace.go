@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+
+	"claude-pilot/core/internal/workspace"
+	"claude-pilot/shared/interfaces"
+)
+
+// WorkspaceFileName is the name of the file a repository declares its
+// sessions in
+const WorkspaceFileName = workspace.FileName
+
+// Workspace is the set of sessions declared by a workspace file (re-exported for convenience)
+type Workspace = workspace.Workspace
+
+// FindWorkspace returns the path of the workspace file in dir or the closest
+// of its parent directories
+func FindWorkspace(dir string) (string, error) {
+	return workspace.Find(dir)
+}
+
+// LoadWorkspace reads and validates a workspace file
+func LoadWorkspace(path string) (*Workspace, error) {
+	return workspace.Load(path)
+}
+
+// WorkspaceAction is what WorkspaceUp or WorkspaceDown did with a session
+type WorkspaceAction string
+
+const (
+	WorkspaceCreated WorkspaceAction = "created"
+	WorkspaceResumed WorkspaceAction = "resumed"
+	WorkspaceRunning WorkspaceAction = "already running"
+	WorkspaceKilled  WorkspaceAction = "killed"
+	WorkspaceAbsent  WorkspaceAction = "not running"
+	WorkspaceForeign WorkspaceAction = "left alone, not started from this workspace"
+)
+
+// WorkspaceResult is the outcome for one session of a workspace
+type WorkspaceResult struct {
+	Name   string
+	Action WorkspaceAction
+
+	// Session is nil for absent sessions
+	Session *interfaces.Session
+}
+
+// WorkspaceUp brings up the sessions of a workspace that are not running, in
+// the order they are declared: missing sessions are created and inactive ones
+// resumed, with their panes and windows. Missing panes and windows are added
+// to the session they are attached to, which is running by then. It stops at
+// the first session that fails, returning the results so far, and refuses to
+// touch sessions of the same name that were not started from the workspace.
+func (c *Client) WorkspaceUp(ws *Workspace) ([]WorkspaceResult, error) {
+	var results []WorkspaceResult
+	for i := range ws.Sessions {
+		declared := &ws.Sessions[i]
+		result := WorkspaceResult{Name: declared.Name, Action: WorkspaceCreated}
+
+		if existing, err := c.GetSession(declared.Name); err == nil {
+			if existing.Workspace != ws.Path {
+				return results, fmt.Errorf("session '%s' already exists and was not started from this workspace", declared.Name)
+			}
+
+			result.Action, result.Session = WorkspaceRunning, existing
+			if existing.Status == StatusInactive {
+				if result.Session, err = c.ResumeSession(existing.ID); err != nil {
+					return results, fmt.Errorf("failed to resume session '%s': %w", declared.Name, err)
+				}
+				result.Action = WorkspaceResumed
+			}
+			results = append(results, result)
+			continue
+		}
+		req, err := c.workspaceRequest(ws, declared)
+		if err != nil {
+			return results, fmt.Errorf("session '%s': %w", declared.Name, err)
+		}
+		if result.Session, err = c.CreateSession(*req); err != nil {
+			return results, fmt.Errorf("failed to create session '%s': %w", declared.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// workspaceRequest returns the request creating a session of a workspace,
+// whose values win over those of the template it names
+func (c *Client) workspaceRequest(ws *Workspace, declared *workspace.Session) (*CreateSessionRequest, error) {
+	projectPath, err := absPath(declared.WorkingDir, ws.Dir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	req := &CreateSessionRequest{
+		Name:        declared.Name,
+		ProjectPath: projectPath,
+		Workspace:   ws.Path,
+	}
+	if err := applyTemplate(&declared.SessionTemplate, req); err != nil {
+		return nil, err
+	}
+	if declared.Template != "" {
+		if err := c.ApplyTemplate(declared.Template, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.AttachTo != "" && req.SplitDirection == "" {
+		req.SplitDirection = interfaces.SplitVertical
+	}
+	return req, nil
+}
+
+// WorkspaceDown kills the sessions of a workspace, last declared first, along
+// with their panes and windows. Sessions of the same name that were not
+// started from the workspace are left alone.
+func (c *Client) WorkspaceDown(ws *Workspace) ([]WorkspaceResult, error) {
+	var results []WorkspaceResult
+	for i := len(ws.Sessions) - 1; i >= 0; i-- {
+		declared := &ws.Sessions[i]
+		if declared.AttachTo != "" {
+			continue
+		}
+
+		existing, err := c.GetSession(declared.Name)
+		if err != nil {
+			results = append(results, WorkspaceResult{Name: declared.Name, Action: WorkspaceAbsent})
+			continue
+		}
+		if existing.Workspace != ws.Path {
+			results = append(results, WorkspaceResult{Name: declared.Name, Action: WorkspaceForeign})
+			continue
+		}
+		if err := c.KillSession(existing.ID); err != nil {
+			return results, fmt.Errorf("failed to kill session '%s': %w", declared.Name, err)
+		}
+		results = append(results, WorkspaceResult{Name: declared.Name, Action: WorkspaceKilled, Session: existing})
+	}
+	return results, nil
+}
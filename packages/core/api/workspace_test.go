@@ -0,0 +1,202 @@
+package api
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"claude-pilot/core/internal/config"
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/core/internal/service"
+	"claude-pilot/core/internal/storage"
+	"claude-pilot/core/internal/workspace"
+	"claude-pilot/core/multiplexertest"
+)
+
+// newTestClient returns a client keeping its sessions in a temporary
+// directory and running them in a fake multiplexer
+func newTestClient(t *testing.T) (*Client, *multiplexertest.FakeMultiplexer) {
+	t.Helper()
+
+	repository, err := storage.NewFileSessionRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	log, _ := logger.Setup.Disabled().Build()
+
+	fake := multiplexertest.NewFakeMultiplexer()
+	sessionService := service.NewSessionServiceWithLogger(repository, fake, log)
+	sessionService.SetClaudeDir(t.TempDir())
+
+	return &Client{
+		config:      &config.Config{DefaultShell: "claude"},
+		logger:      log,
+		service:     sessionService,
+		multiplexer: fake,
+	}, fake
+}
+
+// newTestWorkspace returns a workspace of a session with a pane and of a
+// second session, in a temporary directory
+func newTestWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+
+	dir := t.TempDir()
+	return &Workspace{
+		Path: filepath.Join(dir, WorkspaceFileName),
+		Sessions: []workspace.Session{
+			{Name: "api"},
+			{Name: "api-tests", SessionTemplate: config.SessionTemplate{AttachTo: "api", Command: "go test ./..."}},
+			{Name: "web"},
+		},
+	}
+}
+
+// workspaceActions returns the names and actions of workspace results
+func workspaceActions(results []WorkspaceResult) []string {
+	var actions []string
+	for _, result := range results {
+		actions = append(actions, result.Name+": "+string(result.Action))
+	}
+	return actions
+}
+
+func TestWorkspaceUpTwice(t *testing.T) {
+	client, fake := newTestClient(t)
+	ws := newTestWorkspace(t)
+
+	results, err := client.WorkspaceUp(ws)
+	if err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+	want := []string{"api: created", "api-tests: created", "web: created"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("first up = %q, want %q", got, want)
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || len(windows[0].Panes) != 2 {
+		t.Errorf("api windows = %+v, want Claude and the tests", windows)
+	}
+
+	// Nothing is created again
+	results, err = client.WorkspaceUp(ws)
+	if err != nil {
+		t.Fatalf("second WorkspaceUp failed: %v", err)
+	}
+	want = []string{"api: already running", "api-tests: already running", "web: already running"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("second up = %q, want %q", got, want)
+	}
+	if muxSessions, _ := fake.ListSessions(); len(muxSessions) != 2 {
+		t.Errorf("%d multiplexer sessions after the second up, want 2", len(muxSessions))
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || len(windows[0].Panes) != 2 {
+		t.Errorf("api windows after the second up = %+v, want the same two panes", windows)
+	}
+}
+
+func TestWorkspaceUpResumesKilledSession(t *testing.T) {
+	client, fake := newTestClient(t)
+	ws := newTestWorkspace(t)
+
+	if _, err := client.WorkspaceUp(ws); err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+	_ = fake.KillSession("api")
+
+	results, err := client.WorkspaceUp(ws)
+	if err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+	want := []string{"api: resumed", "api-tests: already running", "web: already running"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("up after kill = %q, want %q", got, want)
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || len(windows[0].Panes) != 2 {
+		t.Errorf("api windows after resuming = %+v, want Claude and the tests", windows)
+	}
+}
+
+func TestWorkspaceUpAddsMissingPane(t *testing.T) {
+	client, fake := newTestClient(t)
+	ws := newTestWorkspace(t)
+
+	if _, err := client.WorkspaceUp(ws); err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+	if err := client.KillSession("api-tests"); err != nil {
+		t.Fatalf("KillSession failed: %v", err)
+	}
+
+	// The pane is added to its running session again
+	results, err := client.WorkspaceUp(ws)
+	if err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+	want := []string{"api: already running", "api-tests: created", "web: already running"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("up after killing the pane = %q, want %q", got, want)
+	}
+	if results[1].Session == nil || results[1].Session.ParentID != results[0].Session.ID {
+		t.Errorf("pane session = %+v, want it attached to api", results[1].Session)
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || len(windows[0].Panes) != 2 {
+		t.Errorf("api windows = %+v, want Claude and the tests", windows)
+	}
+}
+
+func TestWorkspaceUpRefusesForeignSession(t *testing.T) {
+	client, _ := newTestClient(t)
+	ws := newTestWorkspace(t)
+
+	if _, err := client.CreateSession(CreateSessionRequest{Name: "web"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	results, err := client.WorkspaceUp(ws)
+	if err == nil {
+		t.Fatal("expected WorkspaceUp to refuse a session it did not start")
+	}
+	want := []string{"api: created", "api-tests: created"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("up = %q, want %q before the foreign session", got, want)
+	}
+
+	// Nor does down kill it
+	results, err = client.WorkspaceDown(ws)
+	if err != nil {
+		t.Fatalf("WorkspaceDown failed: %v", err)
+	}
+	if results[0].Action != WorkspaceForeign || !client.IsSessionRunning("web") {
+		t.Errorf("down = %q, want web left alone", workspaceActions(results))
+	}
+}
+
+func TestWorkspaceDownInReverseOrder(t *testing.T) {
+	client, fake := newTestClient(t)
+	ws := newTestWorkspace(t)
+
+	if _, err := client.WorkspaceUp(ws); err != nil {
+		t.Fatalf("WorkspaceUp failed: %v", err)
+	}
+
+	results, err := client.WorkspaceDown(ws)
+	if err != nil {
+		t.Fatalf("WorkspaceDown failed: %v", err)
+	}
+	want := []string{"web: killed", "api: killed"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("down = %q, want %q", got, want)
+	}
+	if muxSessions, _ := fake.ListSessions(); len(muxSessions) != 0 {
+		t.Errorf("%d multiplexer sessions after down, want none", len(muxSessions))
+	}
+
+	results, err = client.WorkspaceDown(ws)
+	if err != nil {
+		t.Fatalf("second WorkspaceDown failed: %v", err)
+	}
+	want = []string{"web: not running", "api: not running"}
+	if got := workspaceActions(results); !slices.Equal(got, want) {
+		t.Errorf("second down = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"claude-pilot/core/internal/worktree"
+	"claude-pilot/shared/interfaces"
+)
+
+// Worktree is the git worktree of a session (re-exported for convenience)
+type Worktree = interfaces.Worktree
+
+// ErrWorktreeDirty is returned when removing a worktree with uncommitted
+// changes without force
+var ErrWorktreeDirty = worktree.ErrDirty
+
+// RemoveWorktree removes the git worktree of a session, keeping its branch.
+// Sessions keep running in their worktree, so remove it after killing them.
+func (c *Client) RemoveWorktree(wt *Worktree, force bool) error {
+	return c.service.RemoveWorktree(wt, force)
+}
+
+// DiffStats summarizes the changes in a worktree (re-exported for convenience)
+type DiffStats = interfaces.DiffStats
+
+// WorktreeDiff summarizes the changes made in a session's worktree since it
+// was created, committed or not
+func (c *Client) WorktreeDiff(wt *Worktree) (*DiffStats, error) {
+	return c.service.WorktreeDiff(wt)
+}
@@ -4,8 +4,10 @@ go 1.24.5
 
 require (
 	claude-pilot/shared v0.0.0-00010101000000-000000000000
+	github.com/creack/pty v1.1.24
 	github.com/google/uuid v1.6.0
 	github.com/spf13/viper v1.20.1
+	golang.org/x/term v0.33.0
 )
 
 replace claude-pilot/shared => ../shared
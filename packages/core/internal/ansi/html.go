@@ -0,0 +1,278 @@
+// Package ansi renders terminal output containing ANSI escape sequences,
+// such as pane captures with colors, for other media.
+package ansi
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Colors of text without an explicit color, as in a dark terminal
+const (
+	defaultForeground = "#d0d0d0"
+	defaultBackground = "#1e1e1e"
+)
+
+// palette holds the 16 basic terminal colors, as xterm shows them
+var palette = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// style is the text style set by SGR (Select Graphic Rendition) sequences
+type style struct {
+	fg, bg    string // CSS colors, empty for the default
+	bold      bool
+	dim       bool
+	italic    bool
+	underline bool
+	strike    bool
+	reverse   bool
+}
+
+// htmlTemplate is a standalone page showing a capture like a terminal
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { margin: 0; background: %s; }
+pre { margin: 0; padding: 1em; color: %s; font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 13px; line-height: 1.3; }
+</style>
+</head>
+<body>
+<pre>%s</pre>
+</body>
+</html>
+`
+
+// HTMLDocument renders text as a standalone HTML page with its colors
+func HTMLDocument(title, text string) string {
+	return fmt.Sprintf(htmlTemplate, html.EscapeString(title), defaultBackground, defaultForeground, ToHTML(text))
+}
+
+// ToHTML converts text with ANSI escape sequences to HTML, turning colors
+// and text attributes into styled spans. Other escape sequences are dropped.
+func ToHTML(text string) string {
+	var b strings.Builder
+	var current style
+
+	for len(text) > 0 {
+		if text[0] == '\x1b' {
+			n, params, final := parseEscape(text)
+			if final == 'm' {
+				current = current.apply(params)
+			}
+			text = text[n:]
+			continue
+		}
+
+		end := strings.IndexByte(text, '\x1b')
+		if end < 0 {
+			end = len(text)
+		}
+
+		chunk := html.EscapeString(text[:end])
+		if css := current.css(); css != "" {
+			fmt.Fprintf(&b, `<span style="%s">%s</span>`, css, chunk)
+		} else {
+			b.WriteString(chunk)
+		}
+		text = text[end:]
+	}
+
+	return b.String()
+}
+
+// parseEscape parses the escape sequence at the start of s, returning its
+// length, and for CSI sequences their parameters and final byte
+func parseEscape(s string) (int, string, byte) {
+	if len(s) < 2 {
+		return len(s), "", 0
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, then a final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1, s[2:i], s[i]
+			}
+		}
+		return len(s), "", 0
+	case ']':
+		// OSC: terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1, "", 0
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, "", 0
+			}
+		}
+		return len(s), "", 0
+	case '(', ')', '*', '+':
+		// Character set designation, e.g. ESC ( B
+		return min(3, len(s)), "", 0
+	default:
+		return 2, "", 0
+	}
+}
+
+// apply returns the style after an SGR sequence with the given parameters
+func (st style) apply(params string) style {
+	codes := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(codes) == 0 {
+		return style{}
+	}
+
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case code == 0:
+			st = style{}
+		case code == 1:
+			st.bold = true
+		case code == 2:
+			st.dim = true
+		case code == 3:
+			st.italic = true
+		case code == 4:
+			st.underline = true
+		case code == 7:
+			st.reverse = true
+		case code == 9:
+			st.strike = true
+		case code == 22:
+			st.bold, st.dim = false, false
+		case code == 23:
+			st.italic = false
+		case code == 24:
+			st.underline = false
+		case code == 27:
+			st.reverse = false
+		case code == 29:
+			st.strike = false
+		case code >= 30 && code <= 37:
+			st.fg = palette[code-30]
+		case code >= 90 && code <= 97:
+			st.fg = palette[code-90+8]
+		case code >= 40 && code <= 47:
+			st.bg = palette[code-40]
+		case code >= 100 && code <= 107:
+			st.bg = palette[code-100+8]
+		case code == 39:
+			st.fg = ""
+		case code == 49:
+			st.bg = ""
+		case code == 38 || code == 48:
+			color, n := extendedColor(codes[i+1:])
+			i += n
+			if code == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+		}
+	}
+
+	return st
+}
+
+// extendedColor parses the arguments of a 38 or 48 SGR code, either 5;n for
+// the 256-color palette or 2;r;g;b, returning the color and how many
+// arguments it used
+func extendedColor(args []string) (string, int) {
+	if len(args) == 0 {
+		return "", 0
+	}
+
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return "", len(args)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 || n > 255 {
+			return "", 2
+		}
+		return color256(n), 2
+	case "2":
+		if len(args) < 4 {
+			return "", len(args)
+		}
+		var rgb [3]int
+		for j := range rgb {
+			rgb[j], _ = strconv.Atoi(args[j+1])
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0]&0xff, rgb[1]&0xff, rgb[2]&0xff), 4
+	default:
+		return "", 1
+	}
+}
+
+// color256 returns a color of the xterm 256-color palette
+func color256(n int) string {
+	switch {
+	case n < 16:
+		return palette[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+// css returns the inline CSS for the style, empty for the default style
+func (st style) css() string {
+	fg, bg := st.fg, st.bg
+	if st.reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = defaultBackground
+		}
+		if bg == "" {
+			bg = defaultForeground
+		}
+	}
+
+	var rules []string
+	if fg != "" {
+		rules = append(rules, "color:"+fg)
+	}
+	if bg != "" {
+		rules = append(rules, "background:"+bg)
+	}
+	if st.bold {
+		rules = append(rules, "font-weight:bold")
+	}
+	if st.dim {
+		rules = append(rules, "opacity:0.6")
+	}
+	if st.italic {
+		rules = append(rules, "font-style:italic")
+	}
+
+	var decorations []string
+	if st.underline {
+		decorations = append(decorations, "underline")
+	}
+	if st.strike {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		rules = append(rules, "text-decoration:"+strings.Join(decorations, " "))
+	}
+
+	return strings.Join(rules, ";")
+}
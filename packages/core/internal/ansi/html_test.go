@@ -0,0 +1,45 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text is escaped", "a < b && c", "a &lt; b &amp;&amp; c"},
+		{"basic colors", "\x1b[31mred\x1b[0m plain", `<span style="color:#cd0000">red</span> plain`},
+		{"attributes combine", "\x1b[1;4mstrong\x1b[22mline\x1b[m", `<span style="font-weight:bold;text-decoration:underline">strong</span><span style="text-decoration:underline">line</span>`},
+		{"256 colors", "\x1b[38;5;208mo\x1b[48;5;244mg", `<span style="color:#ff8700">o</span><span style="color:#ff8700;background:#808080">g</span>`},
+		{"true color", "\x1b[38;2;215;119;87mc", `<span style="color:#d77757">c</span>`},
+		{"reverse uses the defaults", "\x1b[7mr", `<span style="color:#1e1e1e;background:#d0d0d0">r</span>`},
+		{"other sequences are dropped", "\x1b[2K\x1b]0;title\x07x\x1b(By", "xy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.input); got != tt.want {
+				t.Errorf("ToHTML(%q) =\n%s\nwant\n%s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	input := "\x1b]0;title\x07\x1b[1;31mError\x1b[0m: \x1b[38;5;208mbuild\x1b[m failed\x1b[K"
+	if got, want := Strip(input), "Error: build failed"; got != want {
+		t.Errorf("Strip(%q) = %q, want %q", input, got, want)
+	}
+}
+
+func TestHTMLDocument(t *testing.T) {
+	doc := HTMLDocument("api <capture>", "\x1b[32mok\x1b[0m\n")
+	if !strings.Contains(doc, "<title>api &lt;capture&gt;</title>") ||
+		!strings.Contains(doc, `<pre><span style="color:#00cd00">ok</span>`+"\n</pre>") {
+		t.Errorf("unexpected document:\n%s", doc)
+	}
+}
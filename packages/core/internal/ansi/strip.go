@@ -0,0 +1,25 @@
+package ansi
+
+import "strings"
+
+// Strip removes the ANSI escape sequences from text, leaving plain text
+func Strip(text string) string {
+	var b strings.Builder
+
+	for len(text) > 0 {
+		if text[0] == '\x1b' {
+			n, _, _ := parseEscape(text)
+			text = text[n:]
+			continue
+		}
+
+		end := strings.IndexByte(text, '\x1b')
+		if end < 0 {
+			end = len(text)
+		}
+		b.WriteString(text[:end])
+		text = text[end:]
+	}
+
+	return b.String()
+}
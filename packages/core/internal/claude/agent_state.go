@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"regexp"
+	"strings"
+
+	"claude-pilot/shared/interfaces"
+)
+
+const (
+	// agentScreenLines is how much of the bottom of the screen is classified;
+	// Claude Code keeps its status line, prompts and input box there
+	agentScreenLines = 30
+
+	// agentPromptLines is how close to the bottom the input box must be
+	agentPromptLines = 8
+
+	// agentErrorLines is how far above the input box errors are looked for
+	agentErrorLines = 6
+)
+
+var (
+	// permissionQuestion matches the question of a tool permission prompt,
+	// e.g. "Do you want to proceed?" or "Do you want to make this edit to main.go?"
+	permissionQuestion = regexp.MustCompile(`(Do you want to|Would you like to) .*\?`)
+
+	// permissionChoice matches the first choice of a permission prompt
+	permissionChoice = regexp.MustCompile(`\b1\. Yes\b`)
+
+	// inputPrompt matches the line of Claude Code's input box
+	inputPrompt = regexp.MustCompile(`^[\s│]*[>❯](\s|$)`)
+)
+
+// DetectAgentState classifies a capture of the pane running Claude Code
+func DetectAgentState(screen string) interfaces.AgentState {
+	lines := bottomLines(screen, agentScreenLines)
+	text := strings.Join(lines, "\n")
+
+	switch {
+	case permissionQuestion.MatchString(text) && permissionChoice.MatchString(text):
+		return interfaces.AgentWaitingPermission
+	case strings.Contains(text, "esc to interrupt"):
+		return interfaces.AgentWorking
+	}
+
+	// The input box is the lowest prompt line; earlier user messages are
+	// echoed with the same prompt character
+	prompt := -1
+	for i := len(lines) - 1; i >= 0 && i >= len(lines)-agentPromptLines; i-- {
+		if inputPrompt.MatchString(lines[i]) {
+			prompt = i
+			break
+		}
+	}
+	if prompt < 0 {
+		// Claude is starting up, has exited, or the pane shows something else
+		return interfaces.AgentUnknown
+	}
+
+	// Claude returns to the prompt after an error it cannot recover from
+	for _, line := range lines[max(0, prompt-agentErrorLines):prompt] {
+		if strings.Contains(line, "API Error") || strings.Contains(line, "Request timed out") {
+			return interfaces.AgentErrored
+		}
+	}
+
+	return interfaces.AgentWaitingInput
+}
+
+// bottomLines returns up to n lines from the bottom of screen, ignoring the
+// blank lines below the cursor
+func bottomLines(screen string, n int) []string {
+	lines := strings.Split(strings.TrimRight(screen, " \n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,80 @@
+package claude
+
+import (
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestDetectAgentState(t *testing.T) {
+	const inputBox = `
+╭──────────────────────────────────────────────────────────╮
+│ >                                                        │
+╰──────────────────────────────────────────────────────────╯
+  ? for shortcuts
+`
+
+	tests := []struct {
+		name   string
+		screen string
+		want   interfaces.AgentState
+	}{
+		{
+			name: "working",
+			screen: `> Fix the failing test
+
+● Bash(go test ./...)
+  ⎿  Running…
+
+✻ Pondering… (12s · ↑ 1.2k tokens · esc to interrupt)
+` + inputBox,
+			want: interfaces.AgentWorking,
+		},
+		{
+			name: "waiting for input",
+			screen: `> Fix the failing test
+
+● The test passes now.
+` + inputBox + "\n\n\n",
+			want: interfaces.AgentWaitingInput,
+		},
+		{
+			name: "waiting for permission",
+			screen: `● Bash(rm -rf build)
+
+╭──────────────────────────────────────────────────────────╮
+│ Bash command                                             │
+│                                                          │
+│   rm -rf build                                           │
+│                                                          │
+│ Do you want to proceed?                                  │
+│ ❯ 1. Yes                                                 │
+│   2. Yes, and don't ask again for rm commands            │
+│   3. No, and tell Claude what to do differently (esc)    │
+╰──────────────────────────────────────────────────────────╯
+`,
+			want: interfaces.AgentWaitingPermission,
+		},
+		{
+			name: "errored",
+			screen: `> Fix the failing test
+
+  ⎿  API Error: 529 {"type":"error","error":{"type":"overloaded_error"}}
+` + inputBox,
+			want: interfaces.AgentErrored,
+		},
+		{
+			name:   "shell after claude exited",
+			screen: "$ claude\nBye!\n$ \n",
+			want:   interfaces.AgentUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectAgentState(tt.screen); got != tt.want {
+				t.Errorf("DetectAgentState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
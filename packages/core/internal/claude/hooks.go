@@ -0,0 +1,175 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// HookEvents are the Claude Code hook events claude-pilot records. Besides
+// the events that mark the agent stopping or asking for something, the
+// events that mark it starting work again are needed to leave those states,
+// and SessionEnd to stop reporting a state once Claude exits.
+var HookEvents = []string{
+	"SessionStart",
+	"UserPromptSubmit",
+	"PreToolUse",
+	"PostToolUse",
+	"Notification",
+	"Stop",
+	"SessionEnd",
+}
+
+// hookInput is the JSON Claude Code passes to hook commands on stdin
+type hookInput struct {
+	SessionID     string `json:"session_id"`
+	Cwd           string `json:"cwd"`
+	HookEventName string `json:"hook_event_name"`
+	ToolName      string `json:"tool_name"`
+	Message       string `json:"message"`
+}
+
+// ParseHookInput reads the JSON a Claude Code hook command receives on stdin
+func ParseHookInput(r io.Reader) (*interfaces.HookEvent, error) {
+	var input hookInput
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return nil, fmt.Errorf("failed to decode hook input: %w", err)
+	}
+	if input.HookEventName == "" {
+		return nil, fmt.Errorf("hook input has no hook_event_name")
+	}
+
+	return &interfaces.HookEvent{
+		Name:           input.HookEventName,
+		ConversationID: input.SessionID,
+		Cwd:            input.Cwd,
+		ToolName:       input.ToolName,
+		Message:        input.Message,
+		AgentState:     hookAgentState(input.HookEventName, input.Message),
+		Time:           time.Now(),
+	}, nil
+}
+
+// hookAgentState returns what the agent is doing after a hook event
+func hookAgentState(event, message string) interfaces.AgentState {
+	switch event {
+	case "SessionStart", "Stop":
+		return interfaces.AgentWaitingInput
+	case "UserPromptSubmit", "PreToolUse", "PostToolUse":
+		return interfaces.AgentWorking
+	case "Notification":
+		// e.g. "Claude needs your permission to use Bash" or
+		// "Claude is waiting for your input"
+		if strings.Contains(strings.ToLower(message), "permission") {
+			return interfaces.AgentWaitingPermission
+		}
+		return interfaces.AgentWaitingInput
+	case "SessionEnd":
+		// Claude exited, whatever runs in the pane now is not reported
+		return interfaces.AgentUnknown
+	default:
+		return interfaces.AgentUnknown
+	}
+}
+
+// hookMatcher is an entry of a hook event in Claude Code's settings
+type hookMatcher struct {
+	Matcher string        `json:"matcher,omitempty"`
+	Hooks   []hookCommand `json:"hooks"`
+}
+
+// hookCommand is a command run by a hook matcher
+type hookCommand struct {
+	Type    string `json:"type"`
+	Command string `json:"command"`
+}
+
+// SettingsPath returns the shared Claude Code settings file of a project
+func SettingsPath(projectPath string) string {
+	return filepath.Join(projectPath, ".claude", "settings.json")
+}
+
+// InstallHooks adds command as a hook for every event in HookEvents to the
+// Claude Code settings file at path, keeping all other settings and hooks.
+// It returns the events it added; events already running command are left
+// alone.
+func InstallHooks(path, command string) ([]string, error) {
+	settings := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var hooks map[string][]json.RawMessage
+	if raw, ok := settings["hooks"]; ok {
+		if err := json.Unmarshal(raw, &hooks); err != nil {
+			return nil, fmt.Errorf("failed to parse hooks in %s: %w", path, err)
+		}
+	}
+	if hooks == nil {
+		hooks = make(map[string][]json.RawMessage)
+	}
+
+	var added []string
+	for _, event := range HookEvents {
+		if hasHookCommand(hooks[event], command) {
+			continue
+		}
+
+		entry, err := json.Marshal(hookMatcher{Hooks: []hookCommand{{Type: "command", Command: command}}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode hook: %w", err)
+		}
+		hooks[event] = append(hooks[event], entry)
+		added = append(added, event)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if settings["hooks"], err = json.Marshal(hooks); err != nil {
+		return nil, fmt.Errorf("failed to encode hooks: %w", err)
+	}
+	if data, err = json.MarshalIndent(settings, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create settings directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return added, nil
+}
+
+// hasHookCommand reports whether any of the matchers of an event runs command
+func hasHookCommand(matchers []json.RawMessage, command string) bool {
+	for _, raw := range matchers {
+		var matcher hookMatcher
+		if err := json.Unmarshal(raw, &matcher); err != nil {
+			continue
+		}
+		for _, hook := range matcher.Hooks {
+			if hook.Command == command {
+				return true
+			}
+		}
+	}
+	return false
+}
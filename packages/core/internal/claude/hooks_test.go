@@ -0,0 +1,97 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestParseHookInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  interfaces.AgentState
+	}{
+		{`{"hook_event_name":"SessionStart","source":"startup"}`, interfaces.AgentWaitingInput},
+		{`{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}`, interfaces.AgentWorking},
+		{`{"hook_event_name":"Notification","message":"Claude needs your permission to use Bash"}`, interfaces.AgentWaitingPermission},
+		{`{"hook_event_name":"Notification","message":"Claude is waiting for your input"}`, interfaces.AgentWaitingInput},
+		{`{"hook_event_name":"Stop","stop_hook_active":false}`, interfaces.AgentWaitingInput},
+		{`{"hook_event_name":"PreCompact"}`, interfaces.AgentUnknown},
+		{`{"hook_event_name":"SessionEnd","reason":"prompt_input_exit"}`, interfaces.AgentUnknown},
+	}
+
+	for _, tt := range tests {
+		event, err := ParseHookInput(strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatalf("ParseHookInput(%s) failed: %v", tt.input, err)
+		}
+		if event.AgentState != tt.want {
+			t.Errorf("ParseHookInput(%s).AgentState = %q, want %q", tt.input, event.AgentState, tt.want)
+		}
+	}
+
+	event, err := ParseHookInput(strings.NewReader(`{"session_id":"abc","cwd":"/src/api","hook_event_name":"PreToolUse","tool_name":"Edit"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ConversationID != "abc" || event.Cwd != "/src/api" || event.ToolName != "Edit" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if _, err := ParseHookInput(strings.NewReader(`{"session_id":"abc"}`)); err == nil {
+		t.Error("expected an error for input without an event name")
+	}
+}
+
+func TestInstallHooks(t *testing.T) {
+	path := SettingsPath(t.TempDir())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{
+  "permissions": {"allow": ["Bash(go test:*)"]},
+  "hooks": {"Stop": [{"hooks": [{"type": "command", "command": "say done"}]}]}
+}`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := InstallHooks(path, "claude-pilot hook")
+	if err != nil {
+		t.Fatalf("InstallHooks failed: %v", err)
+	}
+	if len(added) != len(HookEvents) {
+		t.Errorf("added hooks for %v, want all of %v", added, HookEvents)
+	}
+
+	// Installing again changes nothing
+	if added, err := InstallHooks(path, "claude-pilot hook"); err != nil || len(added) != 0 {
+		t.Errorf("second InstallHooks added %v, err %v", added, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Permissions struct {
+			Allow []string `json:"allow"`
+		} `json:"permissions"`
+		Hooks map[string][]hookMatcher `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings are not valid JSON: %v\n%s", err, data)
+	}
+
+	if len(settings.Permissions.Allow) != 1 {
+		t.Errorf("existing permissions were lost:\n%s", data)
+	}
+	stop := settings.Hooks["Stop"]
+	if len(stop) != 2 || stop[0].Hooks[0].Command != "say done" || stop[1].Hooks[0].Command != "claude-pilot hook" {
+		t.Errorf("unexpected Stop hooks: %+v", stop)
+	}
+}
@@ -0,0 +1,187 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// maxTranscriptLine bounds a single transcript entry; tool results such as
+// large file reads can make entries much longer than bufio's default
+const maxTranscriptLine = 64 * 1024 * 1024
+
+// transcriptEntry is one line of a Claude Code transcript. Only user and
+// assistant entries carry conversation messages.
+type transcriptEntry struct {
+	Type        string            `json:"type"`
+	UUID        string            `json:"uuid"`
+	Timestamp   time.Time         `json:"timestamp"`
+	IsSidechain bool              `json:"isSidechain"`
+	IsMeta      bool              `json:"isMeta"`
+	Message     transcriptMessage `json:"message"`
+}
+
+// transcriptMessage is the API message inside a transcript entry. Content is
+// either a string or a list of content blocks.
+type transcriptMessage struct {
+	ID      string          `json:"id"`
+	Content json.RawMessage `json:"content"`
+}
+
+// contentBlock is a text, thinking, tool_use or tool_result content block
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// TranscriptPath returns the transcript file of a conversation
+func TranscriptPath(claudeDir, projectPath, conversationID string) string {
+	return filepath.Join(ProjectDir(claudeDir, projectPath), conversationID+".jsonl")
+}
+
+// ReadMessages parses a transcript into the conversation's messages, oldest
+// first. Assistant replies that Claude Code stores one content block per line
+// are merged, and tool results are attached to the tool calls they answer
+// instead of being reported as user messages. Thinking, subagent (sidechain)
+// and meta entries are skipped.
+func ReadMessages(path string) ([]interfaces.Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	var messages []interfaces.Message
+	results := make(map[string]contentBlock) // tool results by tool call ID
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLine)
+
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // a partially written last line, or an entry format we don't know
+		}
+		if (entry.Type != "user" && entry.Type != "assistant") || entry.IsSidechain || entry.IsMeta {
+			continue
+		}
+
+		text, blocks := parseContent(entry.Message.Content)
+
+		var calls []interfaces.ToolCall
+		var texts []string
+		if text != "" {
+			texts = append(texts, text)
+		}
+		for _, block := range blocks {
+			switch block.Type {
+			case "text":
+				if block.Text != "" {
+					texts = append(texts, block.Text)
+				}
+			case "tool_use":
+				calls = append(calls, interfaces.ToolCall{
+					ID:    block.ID,
+					Name:  block.Name,
+					Input: string(block.Input),
+				})
+			case "tool_result":
+				results[block.ToolUseID] = block
+			}
+		}
+
+		if len(texts) == 0 && len(calls) == 0 {
+			continue
+		}
+
+		// Continuation of the previous assistant reply
+		if last := len(messages) - 1; entry.Type == "assistant" && last >= 0 &&
+			entry.Message.ID != "" && messages[last].ID == entry.Message.ID {
+			messages[last].Content = joinNonEmpty(messages[last].Content, strings.Join(texts, "\n\n"))
+			messages[last].ToolCalls = append(messages[last].ToolCalls, calls...)
+		} else {
+			id := entry.UUID
+			if entry.Type == "assistant" && entry.Message.ID != "" {
+				id = entry.Message.ID
+			}
+			messages = append(messages, interfaces.Message{
+				ID:        id,
+				Role:      entry.Type,
+				Content:   strings.Join(texts, "\n\n"),
+				Timestamp: entry.Timestamp,
+				ToolCalls: calls,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	// Results arrive in later entries than the calls they answer
+	for i := range messages {
+		for j := range messages[i].ToolCalls {
+			call := &messages[i].ToolCalls[j]
+			if result, ok := results[call.ID]; ok {
+				call.Output = toolResultText(result.Content)
+				call.IsError = result.IsError
+			}
+		}
+	}
+
+	return messages, nil
+}
+
+// parseContent decodes message content, which is either a plain string or a
+// list of content blocks
+func parseContent(raw json.RawMessage) (string, []contentBlock) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var blocks []contentBlock
+	_ = json.Unmarshal(raw, &blocks)
+	return "", blocks
+}
+
+// toolResultText returns the text of a tool result, which is either a plain
+// string or a list of content blocks
+func toolResultText(raw json.RawMessage) string {
+	text, blocks := parseContent(raw)
+	if text != "" {
+		return text
+	}
+
+	var texts []string
+	for _, block := range blocks {
+		if block.Type == "text" && block.Text != "" {
+			texts = append(texts, block.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// joinNonEmpty joins two paragraphs, skipping empty ones
+func joinNonEmpty(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n\n" + b
+	}
+}
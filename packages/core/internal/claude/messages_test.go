@@ -0,0 +1,54 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMessages(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"summary","summary":"Fix tests"}`,
+		`{"type":"user","uuid":"u1","timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"Fix the failing test"}}`,
+		`{"type":"user","uuid":"m1","isMeta":true,"timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"<command-name>/clear</command-name>"}}`,
+		`{"type":"assistant","uuid":"a1","timestamp":"2025-06-01T10:00:01Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"thinking","thinking":"hmm"}]}}`,
+		`{"type":"assistant","uuid":"a2","timestamp":"2025-06-01T10:00:02Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Running the tests."}]}}`,
+		`{"type":"assistant","uuid":"a3","timestamp":"2025-06-01T10:00:03Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"go test ./..."}}]}}`,
+		`{"type":"user","uuid":"u2","timestamp":"2025-06-01T10:00:04Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"FAIL"}],"is_error":true}]}}`,
+		`{"type":"assistant","uuid":"s1","isSidechain":true,"timestamp":"2025-06-01T10:00:05Z","message":{"id":"msg_2","role":"assistant","content":[{"type":"text","text":"subagent"}]}}`,
+		`{"type":"assistant","uuid":"a4","timestamp":"2025-06-01T10:00:06Z","message":{"id":"msg_3","role":"assistant","content":[{"type":"text","text":"Fixed."}]}}`,
+		`{"type":"assistant","uuid":"a5","timestamp":`,
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := ReadMessages(path)
+	if err != nil {
+		t.Fatalf("ReadMessages failed: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want 3: %+v", len(messages), messages)
+	}
+
+	if messages[0].Role != "user" || messages[0].Content != "Fix the failing test" {
+		t.Errorf("unexpected first message: %+v", messages[0])
+	}
+
+	// The reply split across lines is one message with its tool result attached
+	reply := messages[1]
+	if reply.ID != "msg_1" || reply.Content != "Running the tests." || len(reply.ToolCalls) != 1 {
+		t.Fatalf("unexpected merged reply: %+v", reply)
+	}
+	call := reply.ToolCalls[0]
+	if call.Name != "Bash" || call.Input != `{"command":"go test ./..."}` || call.Output != "FAIL" || !call.IsError {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+
+	if messages[2].Content != "Fixed." {
+		t.Errorf("unexpected last message: %+v", messages[2])
+	}
+}
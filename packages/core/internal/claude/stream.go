@@ -0,0 +1,99 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StreamEvent is a line of `claude -p --output-format stream-json` output.
+// Fields are filled depending on the type: system, assistant, user or result.
+type StreamEvent struct {
+	Type      string            `json:"type"`
+	Subtype   string            `json:"subtype"`
+	SessionID string            `json:"session_id"` // The conversation ID
+	Model     string            `json:"model"`
+	Message   transcriptMessage `json:"message"`
+
+	// Final result
+	Result       string  `json:"result"`
+	IsError      bool    `json:"is_error"`
+	NumTurns     int     `json:"num_turns"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	DurationMS   int64   `json:"duration_ms"`
+}
+
+// Duration returns how long the run took, as reported by a result event
+func (e *StreamEvent) Duration() time.Duration {
+	return time.Duration(e.DurationMS) * time.Millisecond
+}
+
+// ParseStreamEvent parses a line of stream-json output
+func ParseStreamEvent(line string) (*StreamEvent, error) {
+	var event StreamEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return nil, fmt.Errorf("failed to parse stream event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("stream event has no type")
+	}
+	return &event, nil
+}
+
+// DescribeStreamEvent turns a line of stream-json output into readable text:
+// Claude's replies, its tool calls and the final result. Lines that are not
+// events are returned as they are, and events without anything to show,
+// such as tool results, are skipped.
+func DescribeStreamEvent(line string) (string, bool) {
+	event, err := ParseStreamEvent(line)
+	if err != nil {
+		return line, true
+	}
+
+	switch event.Type {
+	case "system":
+		if event.Subtype != "init" {
+			return "", false
+		}
+		return fmt.Sprintf("Started conversation %s (%s)", event.SessionID, event.Model), true
+	case "assistant":
+		text, blocks := parseContent(event.Message.Content)
+		var parts []string
+		if text != "" {
+			parts = append(parts, text)
+		}
+		for _, block := range blocks {
+			switch block.Type {
+			case "text":
+				if block.Text != "" {
+					parts = append(parts, block.Text)
+				}
+			case "tool_use":
+				parts = append(parts, fmt.Sprintf("→ %s %s", block.Name, abbreviate(string(block.Input), 120)))
+			}
+		}
+		if len(parts) == 0 {
+			return "", false
+		}
+		return strings.Join(parts, "\n"), true
+	case "result":
+		outcome := "succeeded"
+		if event.IsError {
+			outcome = "failed"
+		}
+		summary := fmt.Sprintf("Run %s after %d turns in %s, $%.2f", outcome, event.NumTurns, event.Duration().Round(time.Second), event.TotalCostUSD)
+		return joinNonEmpty(summary, event.Result), true
+	default:
+		return "", false
+	}
+}
+
+// abbreviate shortens s to at most n runes, marking the cut with an ellipsis
+func abbreviate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,27 @@
+package claude
+
+import "testing"
+
+func TestDescribeStreamEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  string
+		shown bool
+	}{
+		{"init", `{"type":"system","subtype":"init","session_id":"abc","model":"opus"}`, "Started conversation abc (opus)", true},
+		{"text and tool call", `{"type":"assistant","message":{"content":[{"type":"text","text":"Reading it"},{"type":"tool_use","name":"Read","input":{"file_path":"a.go"}}]}}`, "Reading it\n→ Read {\"file_path\":\"a.go\"}", true},
+		{"tool results are skipped", `{"type":"user","message":{"content":[{"type":"tool_result","content":"package a"}]}}`, "", false},
+		{"result", `{"type":"result","is_error":true,"result":"Out of credits","num_turns":1,"total_cost_usd":0.25,"duration_ms":2400}`, "Run failed after 1 turns in 2s, $0.25\n\nOut of credits", true},
+		{"other lines are kept", "Error: not logged in", "Error: not logged in", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, shown := DescribeStreamEvent(tt.line)
+			if got != tt.want || shown != tt.shown {
+				t.Errorf("DescribeStreamEvent() = %q, %v, want %q, %v", got, shown, tt.want, tt.shown)
+			}
+		})
+	}
+}
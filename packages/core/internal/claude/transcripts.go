@@ -0,0 +1,124 @@
+// Package claude locates Claude Code's on-disk state, such as the
+// conversation transcripts it keeps for every project.
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// nonAlphanumeric matches the characters Claude Code replaces when it turns a
+// project path into a directory name
+var nonAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// Transcript is a conversation transcript file of a project
+type Transcript struct {
+	ConversationID string
+	Path           string
+	ModTime        time.Time
+}
+
+// DefaultDir returns Claude Code's configuration directory, honouring
+// CLAUDE_CONFIG_DIR like Claude Code itself
+func DefaultDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".claude")
+}
+
+// ProjectDir returns the directory holding the transcripts of projectPath,
+// e.g. ~/.claude/projects/-home-me-src-api for /home/me/src/api
+func ProjectDir(claudeDir, projectPath string) string {
+	return filepath.Join(claudeDir, "projects", nonAlphanumeric.ReplaceAllString(projectPath, "-"))
+}
+
+// ListTranscripts returns the conversation transcripts of a project, newest
+// first. A project Claude has never run in has no transcripts.
+func ListTranscripts(claudeDir, projectPath string) ([]Transcript, error) {
+	dir := ProjectDir(claudeDir, projectPath)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read transcript directory: %w", err)
+	}
+
+	var transcripts []Transcript
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() || !IsConversationID(id) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed while listing
+		}
+
+		transcripts = append(transcripts, Transcript{
+			ConversationID: id,
+			Path:           filepath.Join(dir, entry.Name()),
+			ModTime:        info.ModTime(),
+		})
+	}
+
+	sort.Slice(transcripts, func(i, j int) bool {
+		return transcripts[i].ModTime.After(transcripts[j].ModTime)
+	})
+
+	return transcripts, nil
+}
+
+// transcriptStartLines bounds how far into a transcript its first timestamp
+// is looked for; the entries before it are summaries and snapshots
+const transcriptStartLines = 20
+
+// TranscriptStart returns when the conversation of a transcript started,
+// from its first timestamped entry, or its modification time when it has
+// none yet
+func TranscriptStart(transcript Transcript) time.Time {
+	file, err := os.Open(transcript.Path)
+	if err != nil {
+		return transcript.ModTime
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLine)
+	for i := 0; i < transcriptStartLines && scanner.Scan(); i++ {
+		var entry struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil && !entry.Timestamp.IsZero() {
+			return entry.Timestamp
+		}
+	}
+	return transcript.ModTime
+}
+
+// HasTranscript reports whether a conversation's transcript exists in a project
+func HasTranscript(claudeDir, projectPath, conversationID string) bool {
+	if !IsConversationID(conversationID) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(ProjectDir(claudeDir, projectPath), conversationID+".jsonl"))
+	return err == nil
+}
+
+// IsConversationID reports whether id is a Claude conversation ID (a UUID)
+func IsConversationID(id string) bool {
+	return uuid.Validate(id) == nil
+}
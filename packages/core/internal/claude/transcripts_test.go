@@ -0,0 +1,82 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProjectDir(t *testing.T) {
+	got := ProjectDir("/home/me/.claude", "/home/me/src/my.app_v2")
+	want := "/home/me/.claude/projects/-home-me-src-my-app-v2"
+	if got != want {
+		t.Errorf("ProjectDir = %q, want %q", got, want)
+	}
+}
+
+func TestListTranscripts(t *testing.T) {
+	claudeDir := t.TempDir()
+	projectPath := "/src/api"
+
+	if transcripts, err := ListTranscripts(claudeDir, projectPath); err != nil || len(transcripts) != 0 {
+		t.Fatalf("ListTranscripts for an unknown project = %v, %v; want none", transcripts, err)
+	}
+
+	dir := ProjectDir(claudeDir, projectPath)
+	if err := os.MkdirAll(filepath.Join(dir, "0bbafd23-3ced-4393-bd8c-a45ab806a7f1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]time.Time{
+		"0bbafd23-3ced-4393-bd8c-a45ab806a7f1.jsonl": time.Now().Add(-time.Hour),
+		"fa63cc01-aec7-4573-8adb-2ef2d7bae30a.jsonl": time.Now(),
+		"notes.jsonl": time.Now(),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transcripts, err := ListTranscripts(claudeDir, projectPath)
+	if err != nil {
+		t.Fatalf("ListTranscripts failed: %v", err)
+	}
+	if len(transcripts) != 2 || transcripts[0].ConversationID != "fa63cc01-aec7-4573-8adb-2ef2d7bae30a" {
+		t.Errorf("unexpected transcripts, want the two conversations newest first: %+v", transcripts)
+	}
+	if !HasTranscript(claudeDir, projectPath, "0bbafd23-3ced-4393-bd8c-a45ab806a7f1") {
+		t.Error("HasTranscript = false for an existing transcript")
+	}
+}
+
+func TestTranscriptStart(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	write := func(name, content string) Transcript {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return Transcript{Path: path, ModTime: modTime}
+	}
+
+	started := write("started.jsonl", `{"type":"summary","summary":"Fix login"}
+{"type":"user","timestamp":"2025-06-01T10:00:00Z","message":{"content":"hi"}}
+{"type":"assistant","timestamp":"2025-06-01T10:00:05Z"}
+`)
+	if got, want := TranscriptStart(started), time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("TranscriptStart = %v, want the first timestamp %v", got, want)
+	}
+
+	empty := write("empty.jsonl", "{}\n")
+	if got := TranscriptStart(empty); !got.Equal(modTime) {
+		t.Errorf("TranscriptStart without timestamps = %v, want the modification time %v", got, modTime)
+	}
+}
@@ -0,0 +1,155 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// DefaultContextWindow is the context window of current Claude models, in tokens
+const DefaultContextWindow = 200_000
+
+// syntheticModel marks messages Claude Code wrote itself, e.g. for API errors
+const syntheticModel = "<synthetic>"
+
+// usageEntry is the part of a transcript entry that carries token usage
+type usageEntry struct {
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	IsSidechain bool      `json:"isSidechain"`
+	Message     struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// Price is what a model costs, in USD per million tokens
+type Price struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// PriceTable maps model names to prices. Keys match any model whose name
+// contains them, e.g. "sonnet" matches "claude-sonnet-4-5-20250929"; the
+// longest matching key wins.
+type PriceTable map[string]Price
+
+// Lookup returns the price of model
+func (p PriceTable) Lookup(model string) (Price, bool) {
+	model = strings.ToLower(model)
+
+	var price Price
+	match := ""
+	for key, candidate := range p {
+		if strings.Contains(model, strings.ToLower(key)) && len(key) > len(match) {
+			price, match = candidate, key
+		}
+	}
+	return price, match != ""
+}
+
+// Cost returns the cost of usage on model, or 0 for models without a price
+func (p PriceTable) Cost(model string, usage interfaces.TokenUsage) float64 {
+	price, ok := p.Lookup(model)
+	if !ok {
+		return 0
+	}
+
+	return (float64(usage.InputTokens)*price.Input +
+		float64(usage.OutputTokens)*price.Output +
+		float64(usage.CacheCreationTokens)*price.CacheWrite +
+		float64(usage.CacheReadTokens)*price.CacheRead) / 1_000_000
+}
+
+// ReadUsage returns the usage of every API request in a transcript, oldest
+// first. Claude Code repeats a reply's usage on each line it splits the reply
+// into, so requests are identified by message ID and counted once.
+func ReadUsage(path string) ([]interfaces.UsageRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	var records []interfaces.UsageRecord
+	seen := make(map[string]int) // record index by message ID
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLine)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		// Most lines by volume are tool results, which never carry usage
+		if !bytes.Contains(line, []byte(`"usage"`)) {
+			continue
+		}
+
+		var entry usageEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry.Type != "assistant" || entry.Message.Usage == nil || entry.Message.Model == syntheticModel {
+			continue
+		}
+
+		usage := entry.Message.Usage
+		record := interfaces.UsageRecord{
+			TokenUsage: interfaces.TokenUsage{
+				InputTokens:         usage.InputTokens,
+				OutputTokens:        usage.OutputTokens,
+				CacheCreationTokens: usage.CacheCreationInputTokens,
+				CacheReadTokens:     usage.CacheReadInputTokens,
+			},
+			Timestamp: entry.Timestamp,
+			Model:     entry.Message.Model,
+			Sidechain: entry.IsSidechain,
+		}
+
+		// Later lines of a reply carry the most complete usage
+		if i, ok := seen[entry.Message.ID]; ok && entry.Message.ID != "" {
+			record.Timestamp = records[i].Timestamp
+			records[i] = record
+			continue
+		}
+		seen[entry.Message.ID] = len(records)
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	return records, nil
+}
+
+// SummarizeUsage totals the usage of a conversation's requests. The context
+// fill is taken from the latest request of the main conversation, as
+// subagents have context windows of their own.
+func SummarizeUsage(records []interfaces.UsageRecord, contextWindow int64) *interfaces.SessionUsage {
+	summary := &interfaces.SessionUsage{ContextWindow: contextWindow}
+
+	for _, record := range records {
+		summary.Add(record.TokenUsage)
+		if !record.Sidechain {
+			summary.Model = record.Model
+			summary.ContextTokens = record.InputTokens + record.CacheCreationTokens + record.CacheReadTokens
+		}
+	}
+
+	return summary
+}
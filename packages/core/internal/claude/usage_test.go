@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestReadUsage(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"user","timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"Fix the failing test"}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:01Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":10,"cache_creation_input_tokens":1000,"cache_read_input_tokens":0}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:02Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":1000,"cache_read_input_tokens":0}}}`,
+		`{"type":"assistant","isSidechain":true,"timestamp":"2025-06-01T10:00:03Z","message":{"id":"msg_2","model":"claude-haiku-4-5","usage":{"input_tokens":5000,"output_tokens":100,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:04Z","message":{"id":"msg_3","model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:05Z","message":{"id":"msg_4","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":0,"cache_read_input_tokens":1100}}}`,
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3: %+v", len(records), records)
+	}
+
+	// A reply split across lines is counted once, with its final usage
+	if records[0].OutputTokens != 50 || records[0].Timestamp.Second() != 1 {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+
+	summary := SummarizeUsage(records, 10_000)
+	if summary.InputTokens != 5110 || summary.OutputTokens != 170 || summary.TotalTokens() != 7380 {
+		t.Errorf("unexpected totals: %+v", summary.TokenUsage)
+	}
+
+	// The subagent's context does not count towards the conversation's
+	if summary.ContextTokens != 1110 || summary.ContextPercent() != 11.1 || summary.Model != "claude-sonnet-4-5" {
+		t.Errorf("unexpected context: %d tokens, %.1f%%, model %s", summary.ContextTokens, summary.ContextPercent(), summary.Model)
+	}
+}
+
+func TestPriceTableCost(t *testing.T) {
+	prices := PriceTable{
+		"sonnet":    {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+		"haiku":     {Input: 0.8, Output: 4},
+		"haiku-4-5": {Input: 1, Output: 5},
+	}
+	usage := interfaces.TokenUsage{InputTokens: 1_000_000, OutputTokens: 1_000_000, CacheReadTokens: 1_000_000}
+
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-sonnet-4-5-20250929", 18.3},
+		{"claude-haiku-4-5-20251001", 6},
+		{"claude-3-5-haiku-20241022", 4.8},
+		{"gpt-4", 0},
+	}
+	for _, tt := range tests {
+		if got := prices.Cost(tt.model, usage); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("Cost(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
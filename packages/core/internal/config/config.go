@@ -4,14 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the application configuration
 type Config struct {
-	// Backend specifies the terminal multiplexer to use (tmux only)
+	// Backend specifies the terminal multiplexer to use (auto, tmux, zellij, screen, native)
 	Backend string `mapstructure:"backend" yaml:"backend"`
 
 	// BackendPath specifies the custom path to the multiplexer binary
@@ -31,6 +33,21 @@ type Config struct {
 
 	// Tmux-specific configuration
 	Tmux TmuxConfig `mapstructure:"tmux" yaml:"tmux"`
+
+	// Usage and cost accounting configuration
+	Usage UsageConfig `mapstructure:"usage" yaml:"usage"`
+
+	// Notifications sent while watching sessions
+	Notifications NotificationsConfig `mapstructure:"notifications" yaml:"notifications"`
+
+	// Logs of what sessions print
+	SessionLogs SessionLogsConfig `mapstructure:"session_logs" yaml:"session_logs"`
+
+	// Git worktrees created for sessions
+	Worktrees WorktreesConfig `mapstructure:"worktrees" yaml:"worktrees"`
+
+	// Templates are named presets for sessions, used with create --template
+	Templates map[string]SessionTemplate `mapstructure:"templates" yaml:"templates"`
 }
 
 // UIConfig contains user interface configuration
@@ -50,11 +67,91 @@ type TmuxConfig struct {
 	// SessionPrefix is prepended to all tmux session names
 	SessionPrefix string `mapstructure:"session_prefix" yaml:"session_prefix"`
 
-	// DefaultLayout specifies the default tmux layout
+	// DefaultLayout is the tmux layout applied when panes are added to a
+	// session, e.g. main-horizontal or tiled; none when empty. Blueprints
+	// whose panes set a split or size are not laid out.
 	DefaultLayout string `mapstructure:"default_layout" yaml:"default_layout"`
 
 	// StatusBar configuration
 	StatusBar bool `mapstructure:"status_bar" yaml:"status_bar"`
+
+	// SocketName runs sessions on a dedicated tmux server (tmux -L)
+	SocketName string `mapstructure:"socket_name" yaml:"socket_name"`
+
+	// SocketPath runs sessions on a tmux server at this socket path (tmux -S)
+	SocketPath string `mapstructure:"socket_path" yaml:"socket_path"`
+
+	// ControlMode keeps a tmux control-mode client (tmux -C) for event-driven
+	// state while the TUI runs
+	ControlMode bool `mapstructure:"control_mode" yaml:"control_mode"`
+}
+
+// UsageConfig contains token usage and cost accounting configuration
+type UsageConfig struct {
+	// ContextWindow is the model context window in tokens, for the context fill percentage
+	ContextWindow int64 `mapstructure:"context_window" yaml:"context_window"`
+
+	// Prices maps model names to prices; a key matches every model whose name contains it
+	Prices map[string]ModelPrice `mapstructure:"prices" yaml:"prices"`
+}
+
+// ModelPrice is the price of a model in USD per million tokens
+type ModelPrice struct {
+	Input      float64 `mapstructure:"input" yaml:"input"`
+	Output     float64 `mapstructure:"output" yaml:"output"`
+	CacheWrite float64 `mapstructure:"cache_write" yaml:"cache_write"`
+	CacheRead  float64 `mapstructure:"cache_read" yaml:"cache_read"`
+}
+
+// NotificationsConfig contains the configuration of notifications about
+// sessions that need attention
+type NotificationsConfig struct {
+	// Interval is how often sessions are checked
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+
+	// Debounce is how long a session must stay in a state before it notifies
+	Debounce time.Duration `mapstructure:"debounce" yaml:"debounce"`
+
+	// States are the agent states that notify (waiting, permission, error)
+	States []string `mapstructure:"states" yaml:"states"`
+
+	// TmuxMessage shows a message to every client of the tmux server
+	TmuxMessage bool `mapstructure:"tmux_message" yaml:"tmux_message"`
+
+	// Bell rings the terminal bell of the watching terminal
+	Bell bool `mapstructure:"bell" yaml:"bell"`
+
+	// Command is run with sh -c and the session details in CLAUDE_PILOT_* variables
+	Command string `mapstructure:"command" yaml:"command"`
+
+	// File is a file or FIFO that notifications are appended to, one per line
+	File string `mapstructure:"file" yaml:"file"`
+}
+
+// SessionLogsConfig contains the configuration of session output logs, kept
+// in the sessions directory next to the session metadata
+type SessionLogsConfig struct {
+	// Enabled logs the output of new sessions unless they opt out
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MaxSize is the size in MB a log reaches before it is rotated (0 = no rotation)
+	MaxSize int64 `mapstructure:"max_size" yaml:"max_size"`
+
+	// MaxFiles is how many rotated logs are kept per session
+	MaxFiles int `mapstructure:"max_files" yaml:"max_files"`
+
+	// StripANSI writes plain text instead of raw terminal output
+	StripANSI bool `mapstructure:"strip_ansi" yaml:"strip_ansi"`
+
+	// OnDelete is what happens to the logs of deleted sessions (archive, delete)
+	OnDelete string `mapstructure:"on_delete" yaml:"on_delete"`
+}
+
+// WorktreesConfig contains the configuration of git worktrees created with
+// create --worktree
+type WorktreesConfig struct {
+	// Dir holds the worktrees, in a directory per repository
+	Dir string `mapstructure:"dir" yaml:"dir"`
 }
 
 // LoggingConfig contains logging configuration
@@ -72,11 +169,99 @@ type LoggingConfig struct {
 	MaxSize int64 `mapstructure:"max_size" yaml:"max_size"`
 }
 
+// SessionTemplate presets the sessions created from it. Options given when
+// creating a session win over the template's.
+type SessionTemplate struct {
+	// Description of the sessions, where {name} and {project} are replaced
+	// by the session name and the name of its project directory
+	Description string `mapstructure:"description" yaml:"description"`
+
+	// Command is started instead of default_shell
+	Command string `mapstructure:"command" yaml:"command"`
+
+	// Claude options, as the create flags of the same names
+	Model              string   `mapstructure:"model" yaml:"model"`
+	PermissionMode     string   `mapstructure:"permission_mode" yaml:"permission_mode"`
+	AllowedTools       []string `mapstructure:"allowed_tools" yaml:"allowed_tools"`
+	DisallowedTools    []string `mapstructure:"disallowed_tools" yaml:"disallowed_tools"`
+	MCPConfig          string   `mapstructure:"mcp_config" yaml:"mcp_config"`
+	AppendSystemPrompt string   `mapstructure:"append_system_prompt" yaml:"append_system_prompt"`
+	AddDirs            []string `mapstructure:"add_dirs" yaml:"add_dirs"`
+	Args               []string `mapstructure:"args" yaml:"args"`
+
+	// Env holds KEY=value variables set in the sessions
+	Env []string `mapstructure:"env" yaml:"env"`
+
+	// WorkingDir is where sessions start, absolute or relative to the
+	// current directory
+	WorkingDir string `mapstructure:"working_dir" yaml:"working_dir"`
+
+	// Worktree starts sessions in a new git worktree on Branch, where {name}
+	// is replaced by the session name (default: the session name)
+	Worktree bool   `mapstructure:"worktree" yaml:"worktree"`
+	Branch   string `mapstructure:"branch" yaml:"branch"`
+
+	// AttachTo adds the sessions to an existing session as a pane or window
+	// (Attachment), with panes split along Split (h, v)
+	AttachTo   string `mapstructure:"attach_to" yaml:"attach_to"`
+	Attachment string `mapstructure:"attachment" yaml:"attachment"`
+	Split      string `mapstructure:"split" yaml:"split"`
+
+	// Prompt is the initial prompt Claude starts with
+	Prompt string `mapstructure:"prompt" yaml:"prompt"`
+
+	// Panes are added to the session's window next to Claude
+	Panes []PaneTemplate `mapstructure:"panes" yaml:"panes"`
+
+	// Tags are added to the sessions
+	Tags []string `mapstructure:"tags" yaml:"tags"`
+}
+
+// PaneTemplate is an extra pane of the sessions created from a template.
+// Each pane splits the one before it.
+type PaneTemplate struct {
+	// Command run in the pane (default: your shell)
+	Command string `mapstructure:"command" yaml:"command"`
+
+	// Split of the previous pane (h, v)
+	Split string `mapstructure:"split" yaml:"split"`
+
+	// Size is the percentage of the split pane the new one takes (default: half)
+	Size int `mapstructure:"size" yaml:"size"`
+
+	// WorkingDir of the pane, relative to the session's
+	WorkingDir string `mapstructure:"working_dir" yaml:"working_dir"`
+}
+
+// Validate checks the values of a template that are limited to a few choices
+func (t *SessionTemplate) Validate() error {
+	if !slices.Contains([]string{"", "pane", "window"}, t.Attachment) {
+		return fmt.Errorf("invalid attachment '%s', must be one of: pane, window", t.Attachment)
+	}
+	if !slices.Contains([]string{"", "h", "v"}, t.Split) {
+		return fmt.Errorf("invalid split '%s', must be one of: h, v", t.Split)
+	}
+	for _, variable := range t.Env {
+		if !strings.Contains(variable, "=") {
+			return fmt.Errorf("invalid env entry '%s', must be KEY=value", variable)
+		}
+	}
+	for i, pane := range t.Panes {
+		if !slices.Contains([]string{"", "h", "v"}, pane.Split) {
+			return fmt.Errorf("invalid split '%s' of pane %d, must be one of: h, v", pane.Split, i+1)
+		}
+		if pane.Size < 0 || pane.Size > 99 {
+			return fmt.Errorf("invalid size %d of pane %d, must be a percentage from 1 to 99", pane.Size, i+1)
+		}
+	}
+	return nil
+}
+
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
-        return &Config{
-                Backend:      "auto", // Auto-detect available backend
+	return &Config{
+		Backend:      "auto", // Auto-detect available backend
 		BackendPath:  "",     // Use system PATH
 		SessionsDir:  filepath.Join(homeDir, ".config", "claude-pilot", "sessions"),
 		DefaultShell: "claude",
@@ -96,6 +281,33 @@ func DefaultConfig() *Config {
 			DefaultLayout: "main-horizontal",
 			StatusBar:     true,
 		},
+		Usage: UsageConfig{
+			ContextWindow: 200000,
+			Prices: map[string]ModelPrice{
+				"opus":      {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+				"opus-4-5":  {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.5},
+				"sonnet":    {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+				"haiku":     {Input: 0.8, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+				"haiku-4-5": {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.1},
+			},
+		},
+		Notifications: NotificationsConfig{
+			Interval:    2 * time.Second,
+			Debounce:    5 * time.Second,
+			States:      []string{"waiting", "permission", "error"},
+			TmuxMessage: true,
+			Bell:        true,
+		},
+		SessionLogs: SessionLogsConfig{
+			Enabled:   false,
+			MaxSize:   10,
+			MaxFiles:  3,
+			StripANSI: true,
+			OnDelete:  "archive",
+		},
+		Worktrees: WorktreesConfig{
+			Dir: filepath.Join(homeDir, ".config", "claude-pilot", "worktrees"),
+		},
 	}
 }
 
@@ -149,13 +361,13 @@ func (cm *ConfigManager) Load() (*Config, error) {
 	cm.setDefaults()
 
 	// Try to read config file
-       if err := viper.ReadInConfig(); err != nil {
-               if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-                       // Config file not found, create a default one
-                       var configPath string
-                       if cm.configFile != "" {
-                               configPath = cm.configFile
-                       } else {
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			// Config file not found, create a default one
+			var configPath string
+			if cm.configFile != "" {
+				configPath = cm.configFile
+			} else {
 				homeDir, err := os.UserHomeDir()
 				if err != nil {
 					return cm.config, nil // Use defaults if we can't determine config dir
@@ -163,23 +375,23 @@ func (cm *ConfigManager) Load() (*Config, error) {
 				configPath = filepath.Join(homeDir, ".config", "claude-pilot", "claude-pilot.yaml")
 			}
 
-                       if err := cm.createDefaultConfigFileAt(configPath); err != nil {
-                               // If we can't create the config file, just use defaults without error
-                               // This ensures the application works even if filesystem is read-only
-                               return cm.config, nil
-                       }
-                       // Try to read the newly created config file
-                       if err := viper.ReadInConfig(); err != nil {
-                               // If we still can't read it, just use defaults
-                               return cm.config, nil
-                       }
-                       // Successfully created and loaded default config
-                       err = nil
-               }
-               if err != nil {
-                       return nil, fmt.Errorf("failed to read config file: %w", err)
-               }
-       }
+			if err := cm.createDefaultConfigFileAt(configPath); err != nil {
+				// If we can't create the config file, just use defaults without error
+				// This ensures the application works even if filesystem is read-only
+				return cm.config, nil
+			}
+			// Try to read the newly created config file
+			if err := viper.ReadInConfig(); err != nil {
+				// If we still can't read it, just use defaults
+				return cm.config, nil
+			}
+			// Successfully created and loaded default config
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
 
 	// Unmarshal config
 	if err := viper.Unmarshal(cm.config); err != nil {
@@ -208,6 +420,11 @@ func (cm *ConfigManager) Save() error {
 	viper.Set("logging", cm.config.Logging)
 	viper.Set("ui", cm.config.UI)
 	viper.Set("tmux", cm.config.Tmux)
+	viper.Set("usage", cm.config.Usage)
+	viper.Set("notifications", cm.config.Notifications)
+	viper.Set("session_logs", cm.config.SessionLogs)
+	viper.Set("worktrees", cm.config.Worktrees)
+	viper.Set("templates", cm.config.Templates)
 
 	return viper.WriteConfig()
 }
@@ -240,6 +457,31 @@ func (cm *ConfigManager) setDefaults() {
 	viper.SetDefault("tmux.session_prefix", defaults.Tmux.SessionPrefix)
 	viper.SetDefault("tmux.default_layout", defaults.Tmux.DefaultLayout)
 	viper.SetDefault("tmux.status_bar", defaults.Tmux.StatusBar)
+	viper.SetDefault("tmux.socket_name", defaults.Tmux.SocketName)
+	viper.SetDefault("tmux.socket_path", defaults.Tmux.SocketPath)
+	viper.SetDefault("tmux.control_mode", defaults.Tmux.ControlMode)
+	viper.SetDefault("usage.context_window", defaults.Usage.ContextWindow)
+	viper.SetDefault("notifications.interval", defaults.Notifications.Interval)
+	viper.SetDefault("notifications.debounce", defaults.Notifications.Debounce)
+	viper.SetDefault("notifications.states", defaults.Notifications.States)
+	viper.SetDefault("notifications.tmux_message", defaults.Notifications.TmuxMessage)
+	viper.SetDefault("notifications.bell", defaults.Notifications.Bell)
+	viper.SetDefault("notifications.command", defaults.Notifications.Command)
+	viper.SetDefault("notifications.file", defaults.Notifications.File)
+	viper.SetDefault("session_logs.enabled", defaults.SessionLogs.Enabled)
+	viper.SetDefault("session_logs.max_size", defaults.SessionLogs.MaxSize)
+	viper.SetDefault("session_logs.max_files", defaults.SessionLogs.MaxFiles)
+	viper.SetDefault("session_logs.strip_ansi", defaults.SessionLogs.StripANSI)
+	viper.SetDefault("session_logs.on_delete", defaults.SessionLogs.OnDelete)
+	viper.SetDefault("worktrees.dir", defaults.Worktrees.Dir)
+
+	// Set prices field by field so a configured price table extends the defaults
+	for model, price := range defaults.Usage.Prices {
+		viper.SetDefault("usage.prices."+model+".input", price.Input)
+		viper.SetDefault("usage.prices."+model+".output", price.Output)
+		viper.SetDefault("usage.prices."+model+".cache_write", price.CacheWrite)
+		viper.SetDefault("usage.prices."+model+".cache_read", price.CacheRead)
+	}
 }
 
 // validateAndSetDefaults validates configuration and sets computed defaults
@@ -249,12 +491,9 @@ func (cm *ConfigManager) validateAndSetDefaults() error {
 		return fmt.Errorf("failed to create sessions directory: %w", err)
 	}
 
-	// Validate backend selection - auto resolves to tmux
-	if cm.config.Backend == "auto" {
-		cm.config.Backend = "tmux"
-	}
-	
-	validBackends := []string{"tmux"}
+	// Validate backend selection - auto is resolved to an installed backend
+	// when the multiplexer is created
+	validBackends := []string{"auto", "tmux", "zellij", "screen", "native"}
 	isValid := false
 	for _, backend := range validBackends {
 		if cm.config.Backend == backend {
@@ -263,7 +502,35 @@ func (cm *ConfigManager) validateAndSetDefaults() error {
 		}
 	}
 	if !isValid {
-		return fmt.Errorf("invalid backend '%s', must be tmux (zellij support planned for future release)", cm.config.Backend)
+		return fmt.Errorf("invalid backend '%s', must be one of: %v", cm.config.Backend, validBackends)
+	}
+
+	// A tmux server is addressed either by socket name or by path
+	if cm.config.Tmux.SocketName != "" && cm.config.Tmux.SocketPath != "" {
+		return fmt.Errorf("tmux.socket_name and tmux.socket_path are mutually exclusive")
+	}
+
+	// Notifications can only be sent for the states sessions report
+	if cm.config.Notifications.Interval <= 0 {
+		return fmt.Errorf("notifications.interval must be positive")
+	}
+	for _, state := range cm.config.Notifications.States {
+		if !slices.Contains([]string{"working", "waiting", "permission", "error"}, state) {
+			return fmt.Errorf("invalid notification state '%s', must be one of: working, waiting, permission, error", state)
+		}
+	}
+
+	if cm.config.SessionLogs.MaxSize < 0 || cm.config.SessionLogs.MaxFiles < 0 {
+		return fmt.Errorf("session_logs.max_size and session_logs.max_files must not be negative")
+	}
+	if !slices.Contains([]string{"archive", "delete"}, cm.config.SessionLogs.OnDelete) {
+		return fmt.Errorf("invalid session_logs.on_delete '%s', must be one of: archive, delete", cm.config.SessionLogs.OnDelete)
+	}
+
+	for name, template := range cm.config.Templates {
+		if err := template.Validate(); err != nil {
+			return fmt.Errorf("invalid template '%s': %w", name, err)
+		}
 	}
 
 	// Validate UI mode
@@ -314,9 +581,15 @@ func (cm *ConfigManager) createDefaultConfigFileAt(configFilePath string) error
 #
 # For more information, visit: https://github.com/HexSleeves/claude-pilot
 
-# Backend selection: auto
+# Backend selection: auto, tmux, zellij, screen, or native
+# 'auto' picks the first installed backend, preferring tmux, and falls back
+# to the built-in native backend when no multiplexer is installed
 backend: auto
 
+# Custom path to the multiplexer binary (optional, defaults to PATH lookup)
+# With 'auto', the backend is taken from the binary name, e.g. /opt/tmux/bin/tmux
+# backend_path: /opt/tmux/bin/tmux
+
 # Directory where session metadata is stored
 # Will be created automatically if it doesn't exist
 sessions_dir: ` + filepath.Join(homeDir, ".config", "claude-pilot", "sessions") + `
@@ -353,10 +626,97 @@ ui:
 tmux:
   # Prefix for tmux session names (optional)
   session_prefix: claude-
-  # Default tmux layout
+  # Layout applied when panes are added to a session, e.g. main-horizontal,
+  # main-vertical, even-horizontal, even-vertical or tiled; empty for none.
+  # Template panes with a split or size keep their own arrangement.
   default_layout: main-horizontal
   # Display tmux status bar
   status_bar: true
+  # Run sessions on a dedicated tmux server so they stay out of your own
+  # 'tmux ls'. Set either a socket name (tmux -L) or a socket path (tmux -S)
+  # socket_name: claude-pilot
+  # socket_path: ~/.config/claude-pilot/tmux.sock
+  # Let the TUI read session state from one long-lived tmux control-mode
+  # client instead of running tmux for every query, and refresh as soon as
+  # tmux reports a change. Other commands always run tmux directly.
+  control_mode: false
+
+# Token usage and cost accounting, read from Claude's transcripts
+usage:
+  # Context window of your models in tokens, for the context fill percentage
+  context_window: 200000
+  # Prices in USD per million tokens. A key matches every model whose name
+  # contains it, and the longest match wins. Entries here are added to the
+  # built-in prices for opus, sonnet and haiku models, or replace them.
+  # prices:
+  #   sonnet:
+  #     input: 3
+  #     output: 15
+  #     cache_write: 3.75
+  #     cache_read: 0.3
+
+# Notifications sent by 'claude-pilot watch' when an agent needs you
+notifications:
+  # How often sessions are checked
+  interval: 2s
+  # How long an agent must stay in a state before it notifies, so that
+  # short pauses between tool calls stay quiet
+  debounce: 5s
+  # Agent states that notify: waiting, permission, error (or working)
+  states: [waiting, permission, error]
+  # Show a message to every client of the tmux server
+  tmux_message: true
+  # Ring the bell of the terminal running 'claude-pilot watch'
+  bell: true
+  # Run a command with CLAUDE_PILOT_SESSION_ID, CLAUDE_PILOT_SESSION_NAME,
+  # CLAUDE_PILOT_PROJECT_PATH, CLAUDE_PILOT_AGENT_STATE and
+  # CLAUDE_PILOT_MESSAGE set, e.g. to send a desktop notification
+  # command: notify-send "Claude Pilot" "$CLAUDE_PILOT_MESSAGE"
+  # Append notifications to a file or FIFO, one line each
+  # file: ~/.config/claude-pilot/notifications.log
+
+# Logs of what sessions print, kept next to the session metadata and read
+# with 'claude-pilot logs'
+session_logs:
+  # Log new sessions (or per session with 'claude-pilot create --log')
+  enabled: false
+  # Size in MB a log reaches before it is rotated (0 = no rotation)
+  max_size: 10
+  # Rotated logs kept per session
+  max_files: 3
+  # Write plain text instead of raw terminal output with colors
+  strip_ansi: true
+  # What happens to the logs of deleted sessions: archive (move them to
+  # the archive directory next to them) or delete
+  on_delete: archive
+
+# Git worktrees created with 'claude-pilot create --worktree', so that
+# sessions on the same repository do not share a working tree
+worktrees:
+  # Directory holding the worktrees, in a directory per repository
+  dir: ` + filepath.Join(homeDir, ".config", "claude-pilot", "worktrees") + `
+
+# Presets for sessions, used with 'claude-pilot create --template <name>'.
+# Flags given to create win over the template's values.
+# templates:
+#   reviewer:
+#     description: "Review of {project}"
+#     model: opus
+#     permission_mode: plan
+#     append_system_prompt: Review the changes on this branch, do not edit files.
+#     prompt: Review the diff against main and list the problems you find.
+#     tags: [review]
+#   tests:
+#     description: "Test fixer for {project}"
+#     allowed_tools: ["Bash(go test:*)", Edit]
+#     env: [GOFLAGS=-count=1]
+#     worktree: true
+#     branch: "fix-tests/{name}"
+#     prompt: Run the tests and fix the failures.
+#     panes:
+#       - command: go test ./... -count=1
+#         split: h
+#         size: 30
 `
 
 	// Write the default config file
@@ -380,9 +740,18 @@ func (cm *ConfigManager) expandHomePaths() error {
 	// Expand BackendPath if it starts with ~
 	cm.config.BackendPath = ExpandHomePath(cm.config.BackendPath, homeDir)
 
+	// Expand Tmux.SocketPath if it starts with ~
+	cm.config.Tmux.SocketPath = ExpandHomePath(cm.config.Tmux.SocketPath, homeDir)
+
 	// Expand Logging.File if it starts with ~
 	cm.config.Logging.File = ExpandHomePath(cm.config.Logging.File, homeDir)
 
+	// Expand Notifications.File if it starts with ~
+	cm.config.Notifications.File = ExpandHomePath(cm.config.Notifications.File, homeDir)
+
+	// Expand Worktrees.Dir if it starts with ~
+	cm.config.Worktrees.Dir = ExpandHomePath(cm.config.Worktrees.Dir, homeDir)
+
 	return nil
 }
 
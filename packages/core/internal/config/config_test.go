@@ -6,6 +6,7 @@ import (
 
 	"slices"
 	"testing"
+	"time"
 )
 
 func TestConfigDefaults(t *testing.T) {
@@ -159,3 +160,107 @@ func TestHomeDirectoryExpansion(t *testing.T) {
 		})
 	}
 }
+
+func TestUsagePricesExtendDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "claude-pilot.yaml")
+	content := "sessions_dir: " + filepath.Join(tempDir, "sessions") + `
+usage:
+  prices:
+    sonnet:
+      input: 2
+      output: 10
+    my-model:
+      input: 1
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	config, err := NewConfigManager(configPath).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	prices := config.Usage.Prices
+	if prices["sonnet"].Input != 2 || prices["sonnet"].Output != 10 || prices["sonnet"].CacheRead != 0.3 {
+		t.Errorf("sonnet price = %+v, want configured input and output with the default cache prices", prices["sonnet"])
+	}
+	if prices["my-model"].Input != 1 {
+		t.Errorf("my-model price = %+v, want the configured price", prices["my-model"])
+	}
+	if prices["opus"] != DefaultConfig().Usage.Prices["opus"] {
+		t.Errorf("opus price = %+v, want the default", prices["opus"])
+	}
+}
+
+func TestNotificationsConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "claude-pilot.yaml")
+	content := "sessions_dir: " + filepath.Join(tempDir, "sessions") + `
+notifications:
+  debounce: 30s
+  states: [permission]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	config, err := NewConfigManager(configPath).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	notifications := config.Notifications
+	if notifications.Debounce != 30*time.Second || notifications.Interval != 2*time.Second {
+		t.Errorf("debounce = %v, interval = %v; want 30s and the default 2s", notifications.Debounce, notifications.Interval)
+	}
+	if !slices.Equal(notifications.States, []string{"permission"}) || !notifications.Bell {
+		t.Errorf("unexpected notifications config: %+v", notifications)
+	}
+}
+
+func TestTemplatesConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "claude-pilot.yaml")
+	content := "sessions_dir: " + filepath.Join(tempDir, "sessions") + `
+templates:
+  Reviewer:
+    description: "Review of {project}"
+    model: opus
+    allowed_tools: [Read, "Bash(git diff:*)"]
+    env: [REVIEW=1]
+    worktree: true
+    split: h
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	config, err := NewConfigManager(configPath).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Config keys, and so template names, are case-insensitive
+	reviewer, ok := config.Templates["reviewer"]
+	if !ok {
+		t.Fatalf("templates = %+v, want reviewer", config.Templates)
+	}
+	if reviewer.Model != "opus" || !slices.Equal(reviewer.AllowedTools, []string{"Read", "Bash(git diff:*)"}) ||
+		!slices.Equal(reviewer.Env, []string{"REVIEW=1"}) || !reviewer.Worktree || reviewer.Split != "h" {
+		t.Errorf("unexpected template: %+v", reviewer)
+	}
+
+	invalid := "sessions_dir: " + filepath.Join(tempDir, "sessions") + `
+templates:
+  broken:
+    env: [REVIEW]
+`
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if _, err := NewConfigManager(configPath).Load(); err == nil {
+		t.Error("Load succeeded with an env entry without a value")
+	}
+}
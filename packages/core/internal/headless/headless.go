@@ -0,0 +1,201 @@
+// Package headless runs `claude -p` as a background process for sessions
+// without a terminal. A runner process, re-executed from this binary, starts
+// Claude, logs its stream-json events and records how the run ended in a
+// state file that the session service reads.
+package headless
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"claude-pilot/core/internal/outputlog"
+	"claude-pilot/shared/interfaces"
+)
+
+// startTimeout is how long Start waits for the runner to report its PID
+var startTimeout = 5 * time.Second
+
+const (
+	// stopTimeout is how long Stop waits for a run to end before killing it
+	stopTimeout = 5 * time.Second
+)
+
+// Spec describes a run
+type Spec struct {
+	Command    string            `json:"command"` // The claude executable
+	Args       []string          `json:"args"`    // Extra arguments after the headless ones
+	WorkingDir string            `json:"working_dir"`
+	Env        map[string]string `json:"env"`
+	LogFile    string            `json:"log_file"` // Where the stream-json events are logged
+	Log        outputlog.Options `json:"log"`
+}
+
+// runFile is the state file of a run, written by Start and then only by
+// the runner
+type runFile struct {
+	Spec Spec                   `json:"spec"`
+	Run  interfaces.HeadlessRun `json:"run"`
+}
+
+// statePath returns the state file of a session's run
+func statePath(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
+
+// Start starts a runner for a session's run and waits until it is running.
+// dir holds the state files of runs.
+func Start(dir, id, prompt string, spec Spec) (*interfaces.HeadlessRun, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find executable: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	path := statePath(dir, id)
+	state := &runFile{
+		Spec: spec,
+		Run:  interfaces.HeadlessRun{Prompt: prompt, StartedAt: time.Now()},
+	}
+	if err := writeRunFile(path, state); err != nil {
+		return nil, err
+	}
+
+	// The runner gets its own session so it survives the terminal that started it
+	cmd := exec.Command(executable, RunnerCommand, dir, id)
+	cmd.Dir = spec.WorkingDir
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to start runner: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	deadline := time.After(startTimeout)
+	for {
+		run, err := ReadRun(dir, id)
+		if err == nil && (run.PID != 0 || run.Finished()) {
+			return run, nil
+		}
+
+		select {
+		case <-exited:
+			// A run may finish before it is seen running
+			if run, err := ReadRun(dir, id); err == nil && run.Finished() {
+				return run, nil
+			}
+			// A run that never started would otherwise look running forever
+			_ = Remove(dir, id)
+			return nil, fmt.Errorf("runner exited during startup")
+		case <-deadline:
+			_ = cmd.Process.Kill()
+			<-exited
+			_ = Remove(dir, id)
+			return nil, fmt.Errorf("timed out waiting for runner")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// ReadRun returns the state of a session's run. A run whose runner died
+// without recording its end is reported as failed.
+func ReadRun(dir, id string) (*interfaces.HeadlessRun, error) {
+	state, err := readRunFile(statePath(dir, id))
+	if err != nil {
+		return nil, err
+	}
+
+	run := state.Run
+	if !run.Finished() && run.PID != 0 && !processAlive(run.PID) {
+		run.FinishedAt = time.Now()
+		run.ExitCode = -1
+		run.Error = "runner exited without recording a result"
+	}
+	return &run, nil
+}
+
+// Stop terminates a running run with its Claude process and waits for the
+// runner to record the end of the run
+func Stop(dir, id string) error {
+	run, err := ReadRun(dir, id)
+	if err != nil {
+		return err
+	}
+	if run.Finished() || run.PID == 0 {
+		return nil
+	}
+
+	// The runner leads the process group Claude runs in
+	if err := syscall.Kill(-run.PID, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to stop run: %w", err)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		if run, err := ReadRun(dir, id); err != nil || run.Finished() {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	_ = syscall.Kill(-run.PID, syscall.SIGKILL)
+	return nil
+}
+
+// Remove deletes the state file of a session's run
+func Remove(dir, id string) error {
+	if err := os.Remove(statePath(dir, id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove run state: %w", err)
+	}
+	return nil
+}
+
+// readRunFile reads a run state file
+func readRunFile(path string) (*runFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run state: %w", err)
+	}
+
+	var state runFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse run state: %w", err)
+	}
+	return &state, nil
+}
+
+// writeRunFile atomically stores a run state file
+func writeRunFile(path string, state *runFile) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run state: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write run state: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save run state: %w", err)
+	}
+	return nil
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
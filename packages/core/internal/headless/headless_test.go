@@ -0,0 +1,148 @@
+package headless
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claude-pilot/core/internal/outputlog"
+)
+
+// TestMain lets the test binary act as the runner process
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == RunnerCommand {
+		// Runners that die or hang before starting Claude
+		switch os.Getenv("HEADLESS_TEST_RUNNER") {
+		case "exit":
+			os.Exit(1)
+		case "hang":
+			time.Sleep(time.Minute)
+		}
+		if err := RunRunner(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeClaude writes a script that stands in for claude -p
+func fakeClaude(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// waitFinished waits for a run to end
+func waitFinished(t *testing.T, dir, id string) runSummary {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		r, err := ReadRun(dir, id)
+		if err != nil {
+			t.Fatalf("ReadRun failed: %v", err)
+		}
+		if r.Finished() {
+			return runSummary{r.Succeeded(), r.ExitCode, r.Error, r.Result, r.ConversationID, r.NumTurns}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("run did not finish")
+	return runSummary{}
+}
+
+// runSummary holds the fields of a finished run that tests compare
+type runSummary struct {
+	succeeded      bool
+	exitCode       int
+	err            string
+	result         string
+	conversationID string
+	numTurns       int
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "run.log")
+
+	// The prompt arrives on stdin and the headless flags come first
+	claude := fakeClaude(t, `read prompt
+echo "args: $*" >&2
+echo '{"type":"system","subtype":"init","session_id":"conv-1","model":"opus"}'
+echo "{\"type\":\"assistant\",\"message\":{\"content\":[{\"type\":\"text\",\"text\":\"Working on: $prompt\"}]}}"
+echo '{"type":"result","subtype":"success","is_error":false,"result":"All done","num_turns":2,"total_cost_usd":0.5,"duration_ms":1500,"session_id":"conv-1"}'
+test "$1 $2 $3 $4 $5" = "-p --output-format stream-json --verbose --model" || exit 3
+`)
+
+	spec := Spec{Command: claude, Args: []string{"--model", "opus"}, WorkingDir: dir, LogFile: logFile}
+	if _, err := Start(dir, "ok", "fix the tests", spec); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	got := waitFinished(t, dir, "ok")
+	want := runSummary{succeeded: true, result: "All done", conversationID: "conv-1", numTurns: 2}
+	if got != want {
+		t.Errorf("run = %+v, want %+v", got, want)
+	}
+
+	lines, err := outputlog.Read(logFile, time.Time{})
+	if err != nil || len(lines) != 3 || !strings.Contains(lines[1].Text, "Working on: fix the tests") {
+		t.Errorf("logged events = %+v (%v), want the three events", lines, err)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	dir := t.TempDir()
+	spec := Spec{WorkingDir: dir, LogFile: filepath.Join(dir, "run.log")}
+
+	spec.Command = fakeClaude(t, "echo 'API key missing' >&2\nexit 2\n")
+	if _, err := Start(dir, "exit", "hi", spec); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if got := waitFinished(t, dir, "exit"); got.succeeded || got.exitCode != 2 || !strings.Contains(got.err, "API key missing") {
+		t.Errorf("failed run = %+v, want exit code 2 with stderr", got)
+	}
+
+	spec.Command = fakeClaude(t, "cat >/dev/null\nsleep 30\n")
+	if _, err := Start(dir, "stopped", "hi", spec); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := Stop(dir, "stopped"); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got := waitFinished(t, dir, "stopped"); got.succeeded || got.err != "run was stopped" {
+		t.Errorf("stopped run = %+v, want it recorded as stopped", got)
+	}
+}
+
+func TestStartFailures(t *testing.T) {
+	dir := t.TempDir()
+	spec := Spec{Command: fakeClaude(t, "exit 0\n"), WorkingDir: dir, LogFile: filepath.Join(dir, "run.log")}
+
+	// A run that never started leaves no state behind to look running
+	t.Setenv("HEADLESS_TEST_RUNNER", "exit")
+	if _, err := Start(dir, "died", "hi", spec); err == nil {
+		t.Error("Start succeeded with a runner that exited")
+	}
+	if _, err := ReadRun(dir, "died"); err == nil {
+		t.Error("state of a runner that exited during startup was kept")
+	}
+
+	t.Setenv("HEADLESS_TEST_RUNNER", "hang")
+	defer func(timeout time.Duration) { startTimeout = timeout }(startTimeout)
+	startTimeout = 200 * time.Millisecond
+	if _, err := Start(dir, "hung", "hi", spec); err == nil {
+		t.Error("Start succeeded with a runner that never started")
+	}
+	if _, err := ReadRun(dir, "hung"); err == nil {
+		t.Error("state of a runner that timed out was kept")
+	}
+}
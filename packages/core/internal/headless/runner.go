@@ -0,0 +1,216 @@
+package headless
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/core/internal/outputlog"
+)
+
+// RunnerCommand is the hidden subcommand that runs a headless session.
+// Binaries that start headless sessions must dispatch it to RunRunner.
+const RunnerCommand = "__headless-run"
+
+// maxStderr bounds how much of Claude's stderr is kept to explain a failure
+const maxStderr = 4096
+
+// runner runs Claude for one headless session and keeps its state file
+type runner struct {
+	path    string
+	mu      sync.Mutex
+	state   *runFile
+	stopped bool // Stop was requested
+}
+
+// RunRunner runs Claude for a headless session until it exits. args are
+// the runs directory and the session ID, as passed by Start.
+func RunRunner(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s <runs-dir> <session-id>", RunnerCommand)
+	}
+
+	path := statePath(args[0], args[1])
+	state, err := readRunFile(path)
+	if err != nil {
+		return err
+	}
+
+	r := &runner{path: path, state: state}
+	return r.run()
+}
+
+// run starts Claude with the prompt on stdin, logs its events and records
+// how it ended
+func (r *runner) run() error {
+	spec := r.state.Spec
+
+	args := append([]string{"-p", "--output-format", "stream-json", "--verbose"}, spec.Args...)
+	cmd := exec.Command(spec.Command, args...)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = os.Environ()
+	for key, value := range spec.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stdin = strings.NewReader(r.state.Run.Prompt)
+
+	var stderr tailBuffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return r.finish(-1, fmt.Errorf("failed to read claude output: %w", err))
+	}
+
+	// Stop signals the whole process group; the runner outlives Claude to
+	// record the end of the run
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return r.finish(-1, fmt.Errorf("failed to start claude: %w", err))
+	}
+	if err := r.update(func(state *runFile) { state.Run.PID = os.Getpid() }); err != nil {
+		_ = cmd.Process.Kill()
+		return err
+	}
+
+	go func() {
+		for sig := range signals {
+			r.mu.Lock()
+			r.stopped = true
+			r.mu.Unlock()
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	logErr := r.logEvents(stdout)
+	waitErr := cmd.Wait()
+
+	exitCode := cmd.ProcessState.ExitCode()
+	r.mu.Lock()
+	stopped := r.stopped
+	r.mu.Unlock()
+
+	switch {
+	case stopped:
+		return r.finish(exitCode, fmt.Errorf("run was stopped"))
+	case logErr != nil:
+		return r.finish(exitCode, logErr)
+	case waitErr != nil:
+		reason := fmt.Errorf("claude exited with code %d", exitCode)
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			reason = fmt.Errorf("%w: %s", reason, text)
+		}
+		return r.finish(exitCode, reason)
+	default:
+		return r.finish(0, nil)
+	}
+}
+
+// logEvents writes Claude's stream-json events to the log and records the
+// conversation and the final result from them
+func (r *runner) logEvents(stdout io.Reader) error {
+	// Events are JSON, there are no escape sequences to strip
+	opts := r.state.Spec.Log
+	opts.StripANSI = false
+	log := outputlog.NewWriter(r.state.Spec.LogFile, opts)
+	defer log.Close()
+
+	reader := bufio.NewReader(stdout)
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" {
+			if err := log.WriteLine(line); err != nil {
+				return err
+			}
+			if err := r.recordEvent(line); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read claude output: %w", readErr)
+		}
+	}
+}
+
+// recordEvent stores what an event tells about the run
+func (r *runner) recordEvent(line string) error {
+	event, err := claude.ParseStreamEvent(line)
+	if err != nil {
+		return nil // Not an event, it is only logged
+	}
+
+	switch {
+	case event.Type == "system" && event.Subtype == "init":
+		return r.update(func(state *runFile) { state.Run.ConversationID = event.SessionID })
+	case event.Type == "result":
+		return r.update(func(state *runFile) {
+			state.Run.Result = event.Result
+			state.Run.IsError = event.IsError
+			state.Run.NumTurns = event.NumTurns
+			state.Run.Cost = event.TotalCostUSD
+			state.Run.Duration = event.Duration()
+			if event.SessionID != "" {
+				state.Run.ConversationID = event.SessionID
+			}
+		})
+	default:
+		return nil
+	}
+}
+
+// finish records the end of the run, returning the reason it failed
+func (r *runner) finish(exitCode int, reason error) error {
+	err := r.update(func(state *runFile) {
+		state.Run.FinishedAt = time.Now()
+		state.Run.ExitCode = exitCode
+		if reason != nil {
+			state.Run.Error = reason.Error()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return reason
+}
+
+// update changes the state and saves it
+func (r *runner) update(change func(*runFile)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change(r.state)
+	return writeRunFile(r.path, r.state)
+}
+
+// tailBuffer keeps the last maxStderr bytes written to it
+type tailBuffer struct {
+	data []byte
+}
+
+// Write appends p, dropping the oldest bytes beyond the limit
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > maxStderr {
+		b.data = b.data[len(b.data)-maxStderr:]
+	}
+	return len(p), nil
+}
+
+// String returns the kept bytes
+func (b *tailBuffer) String() string {
+	return string(b.data)
+}
@@ -0,0 +1,47 @@
+package multiplexer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// commonBinaryDirs are checked when a multiplexer binary is not in PATH
+var commonBinaryDirs = []string{
+	"/opt/homebrew/bin",
+	"/usr/local/bin",
+	"/usr/bin",
+}
+
+// lookupBinary finds a binary in PATH or in common install locations.
+// It returns the bare name and false when the binary cannot be found.
+func lookupBinary(name string) (string, bool) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, true
+	}
+
+	for _, dir := range commonBinaryDirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+
+	return name, false
+}
+
+// resolveBinary returns the binary to run for a backend. A custom path must
+// exist; otherwise the binary is looked up like lookupBinary does.
+func resolveBinary(name, customPath string) (string, bool, error) {
+	if customPath == "" {
+		path, found := lookupBinary(name)
+		return path, found, nil
+	}
+
+	path, err := exec.LookPath(customPath)
+	if err != nil {
+		return "", false, fmt.Errorf("%s binary not found at backend_path %q: %w", name, customPath, err)
+	}
+	return path, true, nil
+}
@@ -0,0 +1,120 @@
+package multiplexer
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"claude-pilot/core/multiplexertest"
+	"claude-pilot/shared/interfaces"
+)
+
+// contractPrefix keeps contract test sessions apart from real ones
+const contractPrefix = "claude-pilot-contract"
+
+// TestMain lets the test binary act as the native backend's holder process
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == NativeHolderCommand {
+		if err := RunNativeHolder(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	EnableNativeBackend()
+
+	os.Exit(m.Run())
+}
+
+func TestTmuxContract(t *testing.T) {
+	if _, found := lookupBinary("tmux"); !found {
+		t.Skip("tmux is not installed")
+	}
+
+	multiplexertest.Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			// A dedicated server keeps the suite away from the user's sessions
+			tm, err := NewTmuxMultiplexerWithOptions(Options{
+				SessionPrefix:  contractPrefix,
+				TmuxSocketName: contractPrefix,
+			})
+			if err != nil {
+				t.Fatalf("failed to create tmux multiplexer: %v", err)
+			}
+			return tm
+		},
+		SupportsPanes:   true,
+		SupportsWindows: true,
+	}.Run(t)
+}
+
+func TestTmuxControlModeContract(t *testing.T) {
+	if _, found := lookupBinary("tmux"); !found {
+		t.Skip("tmux is not installed")
+	}
+
+	multiplexertest.Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			tm, err := NewTmuxMultiplexerWithOptions(Options{
+				SessionPrefix:   contractPrefix,
+				TmuxSocketName:  contractPrefix,
+				TmuxControlMode: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to create tmux multiplexer: %v", err)
+			}
+			return tm
+		},
+		SupportsPanes:   true,
+		SupportsWindows: true,
+	}.Run(t)
+}
+
+func TestZellijContract(t *testing.T) {
+	if _, found := lookupBinary("zellij"); !found {
+		t.Skip("zellij is not installed")
+	}
+
+	multiplexertest.Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			zm, err := NewZellijMultiplexerWithOptions(Options{SessionPrefix: contractPrefix})
+			if err != nil {
+				t.Fatalf("failed to create zellij multiplexer: %v", err)
+			}
+			return zm
+		},
+		SupportsPanes:   true,
+		SupportsWindows: true,
+	}.Run(t)
+}
+
+func TestScreenContract(t *testing.T) {
+	if _, found := lookupBinary("screen"); !found {
+		t.Skip("screen is not installed")
+	}
+
+	// Screen regions only exist on an attached display, so only windows
+	// can be attached
+	multiplexertest.Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			sm, err := NewScreenMultiplexerWithOptions(Options{SessionPrefix: contractPrefix})
+			if err != nil {
+				t.Fatalf("failed to create screen multiplexer: %v", err)
+			}
+			return sm
+		},
+		SupportsWindows: true,
+	}.Run(t)
+}
+
+func TestNativeContract(t *testing.T) {
+	multiplexertest.Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			nm, err := NewNativeMultiplexer(contractPrefix)
+			if err != nil {
+				t.Fatalf("failed to create native multiplexer: %v", err)
+			}
+			return nm
+		},
+	}.Run(t)
+}
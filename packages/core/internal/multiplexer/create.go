@@ -2,11 +2,57 @@ package multiplexer
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 
+	"claude-pilot/core/internal/logger"
 	"claude-pilot/shared/interfaces"
 )
 
+// Options configures how a multiplexer backend is created
+type Options struct {
+	// SessionPrefix is prepended to the multiplexer's session names
+	SessionPrefix string
+
+	// BinaryPath overrides the backend binary found in PATH
+	BinaryPath string
+
+	// TmuxSocketName runs tmux on a dedicated named socket (-L)
+	TmuxSocketName string
+
+	// TmuxSocketPath runs tmux on a dedicated socket path (-S)
+	TmuxSocketPath string
+
+	// TmuxControlMode reads tmux state from a long-lived control-mode client
+	TmuxControlMode bool
+
+	// TmuxLayout is applied to the windows of new tmux sessions
+	TmuxLayout string
+
+	// Logger receives backend logs; logging is disabled when nil
+	Logger *logger.Logger
+}
+
+// sessionPrefix returns the configured session prefix or the default
+func (o Options) sessionPrefix() string {
+	if o.SessionPrefix == "" {
+		return "claude-pilot"
+	}
+	return o.SessionPrefix
+}
+
+// logger returns the configured logger or a disabled one
+func (o Options) logger() *logger.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	disabledLogger, _ := logger.Setup.Disabled().Build()
+	return disabledLogger
+}
+
 // MultiplexerCache caches multiplexer instances to avoid repeated creation
 var (
 	cache      = make(map[string]interfaces.TerminalMultiplexer)
@@ -15,12 +61,15 @@ var (
 
 // CreateMultiplexer creates a multiplexer instance for the given backend
 func CreateMultiplexer(backend, sessionPrefix string) (interfaces.TerminalMultiplexer, error) {
-	if sessionPrefix == "" {
-		sessionPrefix = "claude-pilot"
-	}
+	return CreateMultiplexerWithOptions(backend, Options{SessionPrefix: sessionPrefix})
+}
+
+// CreateMultiplexerWithOptions creates a multiplexer instance for the given backend
+func CreateMultiplexerWithOptions(backend string, opts Options) (interfaces.TerminalMultiplexer, error) {
+	opts.SessionPrefix = opts.sessionPrefix()
 
 	// Create cache key
-	cacheKey := fmt.Sprintf("%s:%s", backend, sessionPrefix)
+	cacheKey := fmt.Sprintf("%s:%s:%s:%s:%s:%t:%s", backend, opts.SessionPrefix, opts.BinaryPath, opts.TmuxSocketName, opts.TmuxSocketPath, opts.TmuxControlMode, opts.TmuxLayout)
 
 	// Check cache first
 	cacheMutex.RLock()
@@ -45,11 +94,17 @@ func CreateMultiplexer(backend, sessionPrefix string) (interfaces.TerminalMultip
 
 	switch backend {
 	case "tmux":
-		mux, err = NewTmuxMultiplexer(sessionPrefix)
+		mux, err = NewTmuxMultiplexerWithOptions(opts)
+	case "zellij":
+		mux, err = NewZellijMultiplexerWithOptions(opts)
+	case "screen":
+		mux, err = NewScreenMultiplexerWithOptions(opts)
+	case "native":
+		mux, err = NewNativeMultiplexerWithLogger(opts.SessionPrefix, opts.logger())
 	case "auto":
-		mux, err = createAutoMultiplexer(sessionPrefix)
+		mux, err = createAutoMultiplexer(opts)
 	default:
-		return nil, fmt.Errorf("unsupported multiplexer backend: %s (only tmux is currently supported)", backend)
+		return nil, fmt.Errorf("unsupported multiplexer backend: %s (supported: %s)", backend, strings.Join(SupportedBackends(), ", "))
 	}
 
 	if err != nil {
@@ -64,6 +119,11 @@ func CreateMultiplexer(backend, sessionPrefix string) (interfaces.TerminalMultip
 	return mux, nil
 }
 
+// SupportedBackends returns the names of all backends in order of preference
+func SupportedBackends() []string {
+	return []string{"tmux", "zellij", "screen", "native"}
+}
+
 // GetAvailableBackends returns list of available multiplexer backends
 func GetAvailableBackends(sessionPrefix string) []string {
 	if sessionPrefix == "" {
@@ -77,6 +137,21 @@ func GetAvailableBackends(sessionPrefix string) []string {
 		available = append(available, "tmux")
 	}
 
+	// Check zellij availability
+	if zellij, err := NewZellijMultiplexer(sessionPrefix); err == nil && zellij.IsAvailable() {
+		available = append(available, "zellij")
+	}
+
+	// Check screen availability
+	if screen, err := NewScreenMultiplexer(sessionPrefix); err == nil && screen.IsAvailable() {
+		available = append(available, "screen")
+	}
+
+	// The native backend needs nothing installed, so it is the last resort
+	if native, err := NewNativeMultiplexer(sessionPrefix); err == nil && native.IsAvailable() {
+		available = append(available, "native")
+	}
+
 	return available
 }
 
@@ -87,17 +162,34 @@ func GetDefaultBackend(sessionPrefix string) string {
 		return "tmux" // fallback default
 	}
 
-	// Currently only tmux is supported
-	return "tmux"
+	return available[0]
 }
 
-// createAutoMultiplexer automatically selects the best available backend
-func createAutoMultiplexer(sessionPrefix string) (interfaces.TerminalMultiplexer, error) {
-	available := GetAvailableBackends(sessionPrefix)
+// envAssignments returns env as KEY=VALUE pairs in a stable order
+func envAssignments(env map[string]string) []string {
+	assignments := make([]string, 0, len(env))
+	for key, value := range env {
+		assignments = append(assignments, key+"="+value)
+	}
+	sort.Strings(assignments)
+	return assignments
+}
+
+// createAutoMultiplexer automatically selects the best available backend.
+// A configured binary path selects the backend it names, e.g. /opt/bin/tmux.
+func createAutoMultiplexer(opts Options) (interfaces.TerminalMultiplexer, error) {
+	if opts.BinaryPath != "" {
+		backend := filepath.Base(opts.BinaryPath)
+		if backend == "native" || !slices.Contains(SupportedBackends(), backend) {
+			return nil, fmt.Errorf("cannot infer backend from backend_path %q, set backend explicitly", opts.BinaryPath)
+		}
+		return CreateMultiplexerWithOptions(backend, opts)
+	}
+
+	available := GetAvailableBackends(opts.SessionPrefix)
 	if len(available) == 0 {
-		return nil, fmt.Errorf("no terminal multiplexer backends available (tmux is required)")
+		return nil, fmt.Errorf("no terminal multiplexer backends available (install one of: %s)", strings.Join(SupportedBackends(), ", "))
 	}
 
-	// Currently only tmux is supported
-	return CreateMultiplexer("tmux", sessionPrefix)
+	return CreateMultiplexerWithOptions(available[0], opts)
 }
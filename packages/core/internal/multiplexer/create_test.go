@@ -0,0 +1,18 @@
+package multiplexer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateMultiplexerMissingBackendPath(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "tmux")
+
+	for _, backend := range []string{"tmux", "auto"} {
+		_, err := CreateMultiplexerWithOptions(backend, Options{BinaryPath: missing})
+		if err == nil || !strings.Contains(err.Error(), missing) {
+			t.Errorf("backend %s: expected error naming %s, got %v", backend, missing, err)
+		}
+	}
+}
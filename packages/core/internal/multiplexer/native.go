@@ -0,0 +1,653 @@
+package multiplexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/shared/interfaces"
+
+	"golang.org/x/term"
+)
+
+const (
+	// nativeDetachKey detaches a client from a native session (Ctrl+\, as in dtach)
+	nativeDetachKey = 0x1c
+
+	// nativeStartTimeout is how long to wait for a new holder to start listening
+	nativeStartTimeout = 5 * time.Second
+)
+
+// nativeSessionSpec is the metadata file shared by a holder and its clients
+type nativeSessionSpec struct {
+	Name        string    `json:"name"`
+	Command     string    `json:"command"`
+	WorkingDir  string    `json:"working_dir"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	PID         int       `json:"pid"` // holder process, set once it is listening
+}
+
+// nativePaths are the files belonging to one native session. The log holds
+// what the holder itself reports, and stays after the session is gone.
+type nativePaths struct {
+	spec   string
+	socket string
+	log    string
+}
+
+// newNativePaths returns the file paths for a session in the state directory
+func newNativePaths(stateDir, fullName string) nativePaths {
+	base := filepath.Join(stateDir, fullName)
+	return nativePaths{
+		spec:   base + ".json",
+		socket: base + ".sock",
+		log:    base + ".log",
+	}
+}
+
+// readNativeSpec loads a session metadata file
+func readNativeSpec(path string) (*nativeSessionSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read native session metadata: %w", err)
+	}
+
+	var spec nativeSessionSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse native session metadata: %w", err)
+	}
+
+	return &spec, nil
+}
+
+// writeNativeSpec atomically stores a session metadata file
+func writeNativeSpec(path string, spec *nativeSessionSpec) error {
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal native session metadata: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write native session metadata: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save native session metadata: %w", err)
+	}
+
+	return nil
+}
+
+// defaultNativeStateDir returns the per-user directory holding session sockets
+func defaultNativeStateDir() string {
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "claude-pilot")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("claude-pilot-%d", os.Getuid()))
+}
+
+// NativeMultiplexer implements the TerminalMultiplexer interface without an
+// external multiplexer. Each session's process runs on a pseudo-terminal owned
+// by a small holder process, and clients attach over a per-session unix socket.
+type NativeMultiplexer struct {
+	sessionPrefix string
+	stateDir      string
+	logger        *logger.Logger
+}
+
+// NativeSession implements the MultiplexerSession interface
+type NativeSession struct {
+	id          string
+	name        string
+	fullName    string
+	status      interfaces.SessionStatus
+	createdAt   time.Time
+	workingDir  string
+	description string
+	isAttached  bool
+	isRunning   bool
+}
+
+// GetID returns the session ID
+func (s *NativeSession) GetID() string {
+	return s.id
+}
+
+// GetName returns the session name
+func (s *NativeSession) GetName() string {
+	return s.name
+}
+
+// GetStatus returns the session status
+func (s *NativeSession) GetStatus() interfaces.SessionStatus {
+	return s.status
+}
+
+// GetCreatedAt returns the creation time
+func (s *NativeSession) GetCreatedAt() time.Time {
+	return s.createdAt
+}
+
+// GetWorkingDir returns the working directory
+func (s *NativeSession) GetWorkingDir() string {
+	return s.workingDir
+}
+
+// GetDescription returns the session description
+func (s *NativeSession) GetDescription() string {
+	return s.description
+}
+
+// IsAttached returns whether a client is attached to the session
+func (s *NativeSession) IsAttached() bool {
+	return s.isAttached
+}
+
+// IsRunning returns whether the session is running
+func (s *NativeSession) IsRunning() bool {
+	return s.isRunning
+}
+
+// NewNativeMultiplexer creates a new native multiplexer instance
+func NewNativeMultiplexer(sessionPrefix string) (*NativeMultiplexer, error) {
+	// Create a disabled logger by default for backward compatibility
+	disabledLogger, _ := logger.Setup.Disabled().Build()
+	return NewNativeMultiplexerWithLogger(sessionPrefix, disabledLogger)
+}
+
+// NewNativeMultiplexerWithLogger creates a new native multiplexer instance with logger
+func NewNativeMultiplexerWithLogger(sessionPrefix string, log *logger.Logger) (*NativeMultiplexer, error) {
+	if sessionPrefix == "" {
+		sessionPrefix = "claude-pilot"
+	}
+
+	stateDir := defaultNativeStateDir()
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create native state directory: %w", err)
+	}
+
+	nm := &NativeMultiplexer{
+		sessionPrefix: sessionPrefix,
+		stateDir:      stateDir,
+		logger:        log,
+	}
+
+	log.Info("Native multiplexer initialized",
+		"session_prefix", sessionPrefix,
+		"state_dir", stateDir)
+
+	return nm, nil
+}
+
+// GetName returns the name of the multiplexer backend
+func (nm *NativeMultiplexer) GetName() string {
+	return "native"
+}
+
+// IsAvailable reports whether sessions can be started. The holder is this
+// binary itself, so it must have enabled the backend with EnableNativeBackend.
+func (nm *NativeMultiplexer) IsAvailable() bool {
+	if !nativeBackendEnabled.Load() {
+		return false
+	}
+	_, err := os.Executable()
+	return err == nil
+}
+
+// CreateSession starts a holder process running the session command
+func (nm *NativeMultiplexer) CreateSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	start := time.Now()
+
+	// Each native session is a single terminal, there is nothing to split
+	if req.AttachTo != "" && req.AttachmentType != interfaces.AttachmentNone {
+		return nil, fmt.Errorf("native backend does not support attaching sessions as a %s", req.AttachmentType)
+	}
+
+	fullName := nm.sessionName(req.Name)
+	paths := newNativePaths(nm.stateDir, fullName)
+
+	nm.logger.Debug("Creating native session",
+		"name", req.Name,
+		"full_name", fullName,
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if !nativeBackendEnabled.Load() {
+		return nil, fmt.Errorf("native backend is not enabled in this binary, which cannot run session holders")
+	}
+	if nm.HasSession(req.Name) {
+		return nil, fmt.Errorf("native session '%s' already exists", req.Name)
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate claude-pilot executable: %w", err)
+	}
+
+	spec := &nativeSessionSpec{
+		Name:        req.Name,
+		Command:     command,
+		WorkingDir:  req.WorkingDir,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+	if err := writeNativeSpec(paths.spec, spec); err != nil {
+		return nil, err
+	}
+
+	logFile, err := os.OpenFile(paths.log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		_ = os.Remove(paths.spec)
+		return nil, fmt.Errorf("failed to create native session log: %w", err)
+	}
+	defer logFile.Close()
+
+	// The holder gets its own session so it survives the terminal that created it
+	cmd := exec.Command(executable, NativeHolderCommand, nm.stateDir, fullName)
+	cmd.Env = append(os.Environ(), envAssignments(req.Env)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	nm.logger.DebugCommand(executable, cmd.Args[1:], req.WorkingDir)
+
+	if err := cmd.Start(); err != nil {
+		_ = os.Remove(paths.spec)
+		return nil, fmt.Errorf("failed to start native session holder: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	if err := nm.waitForHolder(fullName, exited); err != nil {
+		_ = cmd.Process.Kill()
+		nm.logger.Error("Failed to create native session",
+			"name", req.Name,
+			"full_name", fullName,
+			"log", paths.log,
+			"error", err)
+		nm.removeSessionFiles(fullName)
+		return nil, fmt.Errorf("failed to create native session: %w", err)
+	}
+
+	session := &NativeSession{
+		id:          fullName,
+		name:        req.Name,
+		fullName:    fullName,
+		status:      interfaces.StatusActive,
+		createdAt:   spec.CreatedAt,
+		workingDir:  req.WorkingDir,
+		description: req.Description,
+		isAttached:  false,
+		isRunning:   true,
+	}
+
+	nm.logger.Performance("CreateSession", start,
+		slog.String("name", req.Name),
+		slog.String("full_name", fullName))
+
+	nm.logger.Info("Native session created successfully",
+		"name", req.Name,
+		"full_name", fullName,
+		"command", command)
+
+	return session, nil
+}
+
+// waitForHolder waits until a new holder answers status requests
+func (nm *NativeMultiplexer) waitForHolder(fullName string, exited <-chan struct{}) error {
+	deadline := time.Now().Add(nativeStartTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := nm.queryStatus(fullName); err == nil {
+			return nil
+		}
+
+		select {
+		case <-exited:
+			return fmt.Errorf("holder process exited during startup")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("timed out waiting for holder process")
+}
+
+// GetSession retrieves session information by name
+func (nm *NativeMultiplexer) GetSession(name string) (interfaces.MultiplexerSession, error) {
+	fullName := nm.sessionName(name)
+	session, err := nm.loadSession(fullName)
+	if err != nil {
+		return nil, fmt.Errorf("native session '%s' not found", name)
+	}
+	return session, nil
+}
+
+// ListSessions returns all native sessions owned by claude-pilot
+func (nm *NativeMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, error) {
+	matches, err := filepath.Glob(filepath.Join(nm.stateDir, nm.sessionPrefix+"-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list native sessions: %w", err)
+	}
+
+	var sessions []interfaces.MultiplexerSession
+	for _, match := range matches {
+		fullName := strings.TrimSuffix(filepath.Base(match), ".json")
+
+		session, err := nm.loadSession(fullName)
+		if err != nil {
+			nm.logger.Debug("Skipping unreachable native session",
+				"full_name", fullName,
+				"error", err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// loadSession reads a session's metadata and asks its holder for live status.
+// Files left behind by a holder that died are removed.
+func (nm *NativeMultiplexer) loadSession(fullName string) (*NativeSession, error) {
+	paths := newNativePaths(nm.stateDir, fullName)
+	spec, err := readNativeSpec(paths.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := nm.queryStatus(fullName)
+	if err != nil {
+		// A zero PID means the holder is still starting up
+		if spec.PID != 0 && !processAlive(spec.PID) {
+			nm.logger.Warn("Removing stale native session", "full_name", fullName)
+			nm.removeSessionFiles(fullName)
+		}
+		return nil, err
+	}
+
+	return &NativeSession{
+		id:          fullName,
+		name:        strings.TrimPrefix(fullName, nm.sessionPrefix+"-"),
+		fullName:    fullName,
+		status:      interfaces.StatusActive,
+		createdAt:   spec.CreatedAt,
+		workingDir:  spec.WorkingDir,
+		description: spec.Description,
+		isAttached:  status.Clients > 0,
+		isRunning:   true,
+	}, nil
+}
+
+// queryStatus asks a holder for the state of its session
+func (nm *NativeMultiplexer) queryStatus(fullName string) (*nativeStatus, error) {
+	conn, err := nm.dial(fullName)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if err := writeFrame(conn, frameStatus, nil); err != nil {
+		return nil, fmt.Errorf("failed to query native session: %w", err)
+	}
+
+	frame, err := readFrame(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read native session status: %w", err)
+	}
+
+	var status nativeStatus
+	if err := json.Unmarshal(frame.payload, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse native session status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// dial connects to a session's holder
+func (nm *NativeMultiplexer) dial(fullName string) (net.Conn, error) {
+	paths := newNativePaths(nm.stateDir, fullName)
+	return net.DialTimeout("unix", paths.socket, time.Second)
+}
+
+// AttachToSession connects the current terminal to a session until the user
+// detaches with Ctrl+\ or the session's command exits
+func (nm *NativeMultiplexer) AttachToSession(name string) error {
+	fullName := nm.sessionName(name)
+	if _, err := nm.loadSession(fullName); err != nil {
+		return fmt.Errorf("native session '%s' not found", name)
+	}
+
+	conn, err := nm.dial(fullName)
+	if err != nil {
+		return fmt.Errorf("failed to connect to native session: %w", err)
+	}
+	defer conn.Close()
+
+	if err := writeFrame(conn, frameAttach, terminalWinsize()); err != nil {
+		return fmt.Errorf("failed to attach to native session: %w", err)
+	}
+
+	stdinFd := int(os.Stdin.Fd())
+	if term.IsTerminal(stdinFd) {
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(stdinFd, state) }()
+	}
+
+	// Start from a clean screen; the scrollback replay repaints it
+	_, _ = os.Stdout.WriteString("\x1b[H\x1b[2J")
+
+	input, err := newInterruptibleStdin()
+	if err != nil {
+		return err
+	}
+	defer input.close()
+
+	done := make(chan error, 2)
+
+	// Session output
+	go func() {
+		for {
+			frame, err := readFrame(conn)
+			if err != nil {
+				done <- nil // holder went away
+				return
+			}
+
+			switch frame.kind {
+			case frameOutput:
+				_, _ = os.Stdout.Write(frame.payload)
+			case frameExit:
+				if len(frame.payload) == 4 {
+					nm.logger.Debug("Native session exited",
+						"name", name,
+						"exit_code", int32(binary.BigEndian.Uint32(frame.payload)))
+				}
+				done <- nil
+				return
+			}
+		}
+	}()
+
+	// Keyboard input
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := input.file.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				detach := false
+				if idx := bytes.IndexByte(data, nativeDetachKey); idx >= 0 {
+					data, detach = data[:idx], true
+				}
+				if len(data) > 0 {
+					if err := writeFrame(conn, frameInput, data); err != nil {
+						done <- nil
+						return
+					}
+				}
+				if detach {
+					done <- nil
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	for {
+		select {
+		case <-winch:
+			_ = writeFrame(conn, frameResize, terminalWinsize())
+		case err := <-done:
+			// Reset attributes and make sure the cursor is visible again
+			_, _ = os.Stdout.WriteString("\x1b[0m\x1b[?25h\r\n")
+			return err
+		}
+	}
+}
+
+// KillSession terminates a native session
+func (nm *NativeMultiplexer) KillSession(name string) error {
+	fullName := nm.sessionName(name)
+	if _, err := nm.loadSession(fullName); err != nil {
+		return fmt.Errorf("native session '%s' not found", name)
+	}
+
+	conn, err := nm.dial(fullName)
+	if err != nil {
+		return fmt.Errorf("failed to kill native session: %w", err)
+	}
+	defer conn.Close()
+
+	if err := writeFrame(conn, frameKill, nil); err != nil {
+		return fmt.Errorf("failed to kill native session: %w", err)
+	}
+
+	// Wait for the holder to clean up so the session is gone when we return
+	deadline := time.Now().Add(nativeKillTimeout + 2*time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(newNativePaths(nm.stateDir, fullName).socket); errors.Is(err, os.ErrNotExist) {
+			nm.removeSessionFiles(fullName)
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return fmt.Errorf("timed out waiting for native session '%s' to exit", name)
+}
+
+// IsSessionRunning checks if a session is currently running
+func (nm *NativeMultiplexer) IsSessionRunning(name string) bool {
+	session, err := nm.GetSession(name)
+	if err != nil {
+		return false
+	}
+	return session.IsRunning()
+}
+
+// HasSession checks if a session exists
+func (nm *NativeMultiplexer) HasSession(name string) bool {
+	_, err := nm.GetSession(name)
+	return err == nil
+}
+
+// GetSessionPaneCount returns the number of panes in a session, which is
+// always one for native sessions
+func (nm *NativeMultiplexer) GetSessionPaneCount(name string) (int, error) {
+	if !nm.HasSession(name) {
+		return 0, fmt.Errorf("session '%s' not found", name)
+	}
+	return 1, nil
+}
+
+// sessionName returns the full native session name for a claude-pilot session
+func (nm *NativeMultiplexer) sessionName(name string) string {
+	return fmt.Sprintf("%s-%s", nm.sessionPrefix, name)
+}
+
+// removeSessionFiles deletes the socket and metadata of a session, keeping
+// its log
+func (nm *NativeMultiplexer) removeSessionFiles(fullName string) {
+	paths := newNativePaths(nm.stateDir, fullName)
+	_ = os.Remove(paths.socket)
+	_ = os.Remove(paths.spec)
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminalWinsize returns the current terminal size as a frame payload
+func terminalWinsize() []byte {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || cols <= 0 || rows <= 0 {
+		cols, rows = 80, 24
+	}
+	return encodeWinsize(uint16(cols), uint16(rows))
+}
+
+// interruptibleStdin is a non-blocking duplicate of stdin whose pending read
+// can be cancelled, so that detaching does not leave a reader that swallows
+// the next keystroke meant for the caller
+type interruptibleStdin struct {
+	file *os.File
+	fd   int
+}
+
+// newInterruptibleStdin duplicates stdin in non-blocking mode
+func newInterruptibleStdin() (*interruptibleStdin, error) {
+	fd, err := syscall.Dup(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to duplicate stdin: %w", err)
+	}
+
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("failed to configure stdin: %w", err)
+	}
+
+	return &interruptibleStdin{file: os.NewFile(uintptr(fd), "stdin"), fd: fd}, nil
+}
+
+// close cancels any pending read and restores blocking mode, which is shared
+// with the original stdin
+func (s *interruptibleStdin) close() {
+	_ = s.file.SetReadDeadline(time.Now())
+	_ = syscall.SetNonblock(s.fd, false)
+	_ = s.file.Close()
+}
@@ -0,0 +1,320 @@
+package multiplexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// NativeHolderCommand is the hidden subcommand that runs a native session holder.
+// Binaries that use the native backend must dispatch it to RunNativeHolder and
+// call EnableNativeBackend.
+const NativeHolderCommand = "__native-holder"
+
+// nativeBackendEnabled is set by binaries that dispatch NativeHolderCommand
+var nativeBackendEnabled atomic.Bool
+
+// EnableNativeBackend declares that the running binary dispatches
+// NativeHolderCommand to RunNativeHolder. The native backend is unavailable
+// until it is called, since its holders are copies of this binary.
+func EnableNativeBackend() {
+	nativeBackendEnabled.Store(true)
+}
+
+const (
+	// nativeScrollbackSize is how much output is replayed to a new client
+	nativeScrollbackSize = 1 << 20
+
+	// nativeClientQueue is how many frames may be pending for a client before
+	// it is considered stuck and disconnected
+	nativeClientQueue = 1024
+
+	// nativeKillTimeout is how long the process gets to exit after SIGHUP
+	nativeKillTimeout = 3 * time.Second
+)
+
+// nativeStatus is the reply to a status request
+type nativeStatus struct {
+	PID     int `json:"pid"` // Holder process, as in the session's metadata
+	Clients int `json:"clients"`
+}
+
+// nativeHolder owns the pseudo-terminal of one native session and serves clients
+type nativeHolder struct {
+	paths      nativePaths
+	cmd        *exec.Cmd
+	ptmx       *os.File
+	listener   net.Listener
+	mu         sync.Mutex
+	scrollback *scrollbackBuffer
+	clients    map[*nativeHolderClient]struct{}
+}
+
+// nativeHolderClient is a client attached to the holder
+type nativeHolderClient struct {
+	conn   net.Conn
+	frames chan nativeFrame
+	once   sync.Once
+}
+
+// close stops delivering frames to the client
+func (c *nativeHolderClient) close() {
+	c.once.Do(func() { close(c.frames) })
+}
+
+// RunNativeHolder runs the holder process for a native session until its
+// command exits or the session is killed. args are the state directory and
+// the full session name.
+func RunNativeHolder(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s <state-dir> <session>", NativeHolderCommand)
+	}
+
+	paths := newNativePaths(args[0], args[1])
+	spec, err := readNativeSpec(paths.spec)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", spec.Command)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = os.Environ()
+	if os.Getenv("TERM") == "" {
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	}
+
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: 80, Rows: 24})
+	if err != nil {
+		return fmt.Errorf("failed to start session command: %w", err)
+	}
+
+	_ = os.Remove(paths.socket)
+	listener, err := net.Listen("unix", paths.socket)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("failed to listen on session socket: %w", err)
+	}
+	_ = os.Chmod(paths.socket, 0600)
+
+	spec.PID = os.Getpid()
+	if err := writeNativeSpec(paths.spec, spec); err != nil {
+		_ = cmd.Process.Kill()
+		_ = listener.Close()
+		return err
+	}
+
+	h := &nativeHolder{
+		paths:      paths,
+		cmd:        cmd,
+		ptmx:       ptmx,
+		listener:   listener,
+		scrollback: newScrollbackBuffer(nativeScrollbackSize),
+		clients:    make(map[*nativeHolderClient]struct{}),
+	}
+
+	return h.run()
+}
+
+// run serves clients until the session command exits
+func (h *nativeHolder) run() error {
+	go h.acceptLoop()
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		h.pumpOutput()
+	}()
+
+	exitCode := 0
+	if err := h.cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+
+	// Give the reader a moment to flush the last output; background
+	// processes may keep the terminal open indefinitely
+	select {
+	case <-outputDone:
+	case <-time.After(time.Second):
+	}
+
+	_ = h.listener.Close()
+	_ = os.Remove(h.paths.socket)
+	_ = os.Remove(h.paths.spec)
+
+	h.mu.Lock()
+	code := binary.BigEndian.AppendUint32(nil, uint32(exitCode))
+	for client := range h.clients {
+		select {
+		case client.frames <- nativeFrame{kind: frameExit, payload: code}:
+		default:
+		}
+		client.close()
+	}
+	h.clients = nil
+	h.mu.Unlock()
+
+	_ = h.ptmx.Close()
+
+	// Let client writers deliver the exit frame
+	time.Sleep(100 * time.Millisecond)
+
+	return nil
+}
+
+// pumpOutput copies terminal output into the scrollback and to all clients
+func (h *nativeHolder) pumpOutput() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := h.ptmx.Read(buf)
+		if n > 0 {
+			data := append([]byte(nil), buf[:n]...)
+
+			h.mu.Lock()
+			_, _ = h.scrollback.Write(data)
+			for client := range h.clients {
+				select {
+				case client.frames <- nativeFrame{kind: frameOutput, payload: data}:
+				default:
+					// The client stopped reading; drop it rather than stall the session
+					client.close()
+					delete(h.clients, client)
+				}
+			}
+			h.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// acceptLoop accepts client connections until the listener is closed
+func (h *nativeHolder) acceptLoop() {
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			return
+		}
+		go h.handleConn(conn)
+	}
+}
+
+// handleConn serves a single client connection
+func (h *nativeHolder) handleConn(conn net.Conn) {
+	frame, err := readFrame(conn)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	switch frame.kind {
+	case frameStatus:
+		h.mu.Lock()
+		status := nativeStatus{PID: os.Getpid(), Clients: len(h.clients)}
+		h.mu.Unlock()
+
+		payload, _ := json.Marshal(status)
+		_ = writeFrame(conn, frameStatus, payload)
+		_ = conn.Close()
+	case frameKill:
+		h.terminate()
+		_ = conn.Close()
+	case frameAttach:
+		h.attach(conn, frame.payload)
+	default:
+		_ = conn.Close()
+	}
+}
+
+// attach streams the session to a client until it disconnects
+func (h *nativeHolder) attach(conn net.Conn, winsize []byte) {
+	client := &nativeHolderClient{
+		conn:   conn,
+		frames: make(chan nativeFrame, nativeClientQueue),
+	}
+
+	h.mu.Lock()
+	if h.clients == nil {
+		// The session is shutting down
+		h.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	if replay := h.scrollback.Bytes(); len(replay) > 0 {
+		client.frames <- nativeFrame{kind: frameOutput, payload: replay}
+	}
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+
+	h.resize(winsize)
+
+	// Writer: deliver queued frames in order
+	go func() {
+		for frame := range client.frames {
+			if err := writeFrame(conn, frame.kind, frame.payload); err != nil {
+				break
+			}
+		}
+		_ = conn.Close()
+	}()
+
+	// Reader: forward input and resizes until the client goes away
+	for {
+		frame, err := readFrame(conn)
+		if err != nil {
+			break
+		}
+
+		switch frame.kind {
+		case frameInput:
+			_, _ = h.ptmx.Write(frame.payload)
+		case frameResize:
+			h.resize(frame.payload)
+		}
+	}
+
+	h.mu.Lock()
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		client.close()
+	}
+	h.mu.Unlock()
+}
+
+// resize applies a client's window size and asks the program to redraw
+func (h *nativeHolder) resize(payload []byte) {
+	cols, rows, ok := decodeWinsize(payload)
+	if !ok || cols == 0 || rows == 0 {
+		return
+	}
+
+	_ = pty.Setsize(h.ptmx, &pty.Winsize{Cols: cols, Rows: rows})
+
+	// Setsize only signals on an actual change; a reattaching client at the
+	// same size still needs a redraw
+	_ = syscall.Kill(-h.cmd.Process.Pid, syscall.SIGWINCH)
+}
+
+// terminate hangs up the session's process group, killing it if it lingers
+func (h *nativeHolder) terminate() {
+	pgid := h.cmd.Process.Pid
+	_ = syscall.Kill(-pgid, syscall.SIGHUP)
+
+	go func() {
+		time.Sleep(nativeKillTimeout)
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	}()
+}
@@ -0,0 +1,99 @@
+package multiplexer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Frame types exchanged between the native holder process and its clients.
+// Every frame is a type byte, a big-endian uint32 payload length and the payload.
+const (
+	frameAttach byte = 'a' // client -> holder: start streaming, payload is the window size
+	frameInput  byte = 'i' // client -> holder: keyboard input
+	frameResize byte = 'r' // client -> holder: window size changed
+	frameStatus byte = 's' // client -> holder: status request; holder -> client: JSON status
+	frameKill   byte = 'k' // client -> holder: terminate the session
+	frameOutput byte = 'o' // holder -> client: terminal output
+	frameExit   byte = 'x' // holder -> client: the process exited, payload is the exit code
+)
+
+// maxFramePayload guards against reading garbage as a huge length
+const maxFramePayload = 4 << 20
+
+// nativeFrame is a single protocol message
+type nativeFrame struct {
+	kind    byte
+	payload []byte
+}
+
+// writeFrame writes a single frame to w
+func writeFrame(w io.Writer, kind byte, payload []byte) error {
+	header := make([]byte, 5, 5+len(payload))
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// readFrame reads a single frame from r
+func readFrame(r io.Reader) (nativeFrame, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nativeFrame{}, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFramePayload {
+		return nativeFrame{}, fmt.Errorf("frame payload too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nativeFrame{}, err
+	}
+
+	return nativeFrame{kind: header[0], payload: payload}, nil
+}
+
+// encodeWinsize encodes a terminal size as a frame payload
+func encodeWinsize(cols, rows uint16) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload[0:], cols)
+	binary.BigEndian.PutUint16(payload[2:], rows)
+	return payload
+}
+
+// decodeWinsize decodes a terminal size frame payload
+func decodeWinsize(payload []byte) (cols, rows uint16, ok bool) {
+	if len(payload) != 4 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint16(payload[0:]), binary.BigEndian.Uint16(payload[2:]), true
+}
+
+// scrollbackBuffer keeps the most recent terminal output for replay on attach.
+// It is not safe for concurrent use; the holder guards it with its own lock.
+type scrollbackBuffer struct {
+	data []byte
+	size int
+}
+
+// newScrollbackBuffer creates a buffer holding at most size bytes
+func newScrollbackBuffer(size int) *scrollbackBuffer {
+	return &scrollbackBuffer{size: size}
+}
+
+// Write appends output, discarding the oldest bytes once the buffer is full
+func (b *scrollbackBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if overflow := len(b.data) - b.size; overflow > 0 {
+		b.data = append(b.data[:0], b.data[overflow:]...)
+	}
+	return len(p), nil
+}
+
+// Bytes returns a copy of the buffered output
+func (b *scrollbackBuffer) Bytes() []byte {
+	return append([]byte(nil), b.data...)
+}
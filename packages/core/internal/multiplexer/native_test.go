@@ -0,0 +1,189 @@
+package multiplexer
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// newTestNativeMultiplexer returns a native multiplexer keeping its state in
+// a temporary directory
+func newTestNativeMultiplexer(t *testing.T) *NativeMultiplexer {
+	t.Helper()
+
+	// Unix socket paths are short, so the directory is too
+	dir, err := os.MkdirTemp("", "cpn")
+	if err != nil {
+		t.Fatalf("failed to create state directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	nm, err := NewNativeMultiplexer(contractPrefix)
+	if err != nil {
+		t.Fatalf("failed to create native multiplexer: %v", err)
+	}
+	return nm
+}
+
+// attachNative connects to a session as a client, the way AttachToSession
+// does without a terminal
+func attachNative(t *testing.T, nm *NativeMultiplexer, name string) net.Conn {
+	t.Helper()
+
+	conn, err := nm.dial(nm.sessionName(name))
+	if err != nil {
+		t.Fatalf("failed to connect to session: %v", err)
+	}
+	if err := writeFrame(conn, frameAttach, encodeWinsize(80, 24)); err != nil {
+		t.Fatalf("failed to attach: %v", err)
+	}
+	return conn
+}
+
+// readOutputUntil reads output frames until the output so far contains want
+func readOutputUntil(t *testing.T, conn net.Conn, want string) string {
+	t.Helper()
+
+	var output bytes.Buffer
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for !strings.Contains(output.String(), want) {
+		frame, err := readFrame(conn)
+		if err != nil {
+			t.Fatalf("expected output containing %q, got %q: %v", want, output.String(), err)
+		}
+		if frame.kind == frameOutput {
+			output.Write(frame.payload)
+		}
+	}
+	return output.String()
+}
+
+// waitForClients waits until the holder reports the number of clients
+func waitForClients(t *testing.T, nm *NativeMultiplexer, name string, clients int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, err := nm.queryStatus(nm.sessionName(name))
+		if err == nil && status.Clients == clients {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d clients, last status %+v, error %v", clients, status, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestNativeAttachDetachReplay(t *testing.T) {
+	nm := newTestNativeMultiplexer(t)
+
+	if _, err := nm.CreateSession(interfaces.CreateSessionRequest{
+		Name:    "replay",
+		Command: "echo started; cat",
+	}); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	t.Cleanup(func() { _ = nm.KillSession("replay") })
+
+	// The output printed before anyone attached is replayed
+	conn := attachNative(t, nm, "replay")
+	readOutputUntil(t, conn, "started")
+	waitForClients(t, nm, "replay", 1)
+
+	session, err := nm.GetSession("replay")
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+	if !session.IsAttached() {
+		t.Error("expected session to be attached")
+	}
+
+	if err := writeFrame(conn, frameInput, []byte("ping\n")); err != nil {
+		t.Fatalf("failed to send input: %v", err)
+	}
+	readOutputUntil(t, conn, "ping")
+
+	// Detaching leaves the session running
+	_ = conn.Close()
+	waitForClients(t, nm, "replay", 0)
+	if !nm.IsSessionRunning("replay") {
+		t.Fatal("expected session to keep running after detach")
+	}
+
+	// A new client gets everything printed so far, input echoes included
+	conn = attachNative(t, nm, "replay")
+	defer conn.Close()
+	output := readOutputUntil(t, conn, "ping")
+	if !strings.Contains(output, "started") {
+		t.Errorf("expected replay to start with the first output, got %q", output)
+	}
+}
+
+func TestNativeStatusReportsHolderPID(t *testing.T) {
+	nm := newTestNativeMultiplexer(t)
+
+	if _, err := nm.CreateSession(interfaces.CreateSessionRequest{Name: "pid", Command: "cat"}); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	t.Cleanup(func() { _ = nm.KillSession("pid") })
+
+	paths := newNativePaths(nm.stateDir, nm.sessionName("pid"))
+	spec, err := readNativeSpec(paths.spec)
+	if err != nil {
+		t.Fatalf("failed to read session metadata: %v", err)
+	}
+	status, err := nm.queryStatus(nm.sessionName("pid"))
+	if err != nil {
+		t.Fatalf("failed to query status: %v", err)
+	}
+	if status.PID == 0 || status.PID != spec.PID {
+		t.Errorf("expected status PID %d to be the holder's, as in the metadata (%d)", status.PID, spec.PID)
+	}
+
+	// The holder's log outlives the session
+	if err := nm.KillSession("pid"); err != nil {
+		t.Fatalf("failed to kill session: %v", err)
+	}
+	if _, err := os.Stat(paths.log); err != nil {
+		t.Errorf("expected log to be kept: %v", err)
+	}
+	if _, err := os.Stat(paths.spec); !os.IsNotExist(err) {
+		t.Errorf("expected metadata to be removed, got %v", err)
+	}
+}
+
+func TestNativeBackendOptIn(t *testing.T) {
+	nm := newTestNativeMultiplexer(t)
+
+	nativeBackendEnabled.Store(false)
+	defer EnableNativeBackend()
+
+	if nm.IsAvailable() {
+		t.Error("expected native backend to be unavailable until enabled")
+	}
+	if _, err := nm.CreateSession(interfaces.CreateSessionRequest{Name: "disabled"}); err == nil {
+		t.Error("expected creating a session to fail until the backend is enabled")
+	}
+
+	EnableNativeBackend()
+	if !nm.IsAvailable() {
+		t.Error("expected native backend to be available once enabled")
+	}
+}
+
+func TestScrollbackBufferKeepsNewestOutput(t *testing.T) {
+	buf := newScrollbackBuffer(8)
+	_, _ = buf.Write([]byte("hello "))
+	_, _ = buf.Write([]byte("world"))
+
+	if got := string(buf.Bytes()); got != "lo world" {
+		t.Errorf("expected the newest 8 bytes, got %q", got)
+	}
+}
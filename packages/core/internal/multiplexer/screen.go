@@ -0,0 +1,478 @@
+package multiplexer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/core/internal/utils"
+	"claude-pilot/shared/interfaces"
+)
+
+var (
+	// screenSessionPattern matches a session line of `screen -ls`, e.g.
+	// "	12345.claude--api	(10/16/2025 10:00:00 AM)	(Detached)"
+	screenSessionPattern = regexp.MustCompile(`^\s*(\d+)\.(\S+)\s+(.*)$`)
+
+	// screenWindowPattern matches the start of each entry of `screen -Q windows`,
+	// e.g. "0$ sh  1*$ claude"
+	screenWindowPattern = regexp.MustCompile(`(?:^|\s{2})\d+\S*\s`)
+
+	// screenTimeLayouts are the date formats screen uses in `screen -ls`
+	screenTimeLayouts = []string{
+		"01/02/2006 03:04:05 PM",
+		"01/02/2006 15:04:05",
+		"01/02/06 15:04:05",
+		"02.01.2006 15:04:05",
+		"2006-01-02 15:04:05",
+	}
+)
+
+// ScreenMultiplexer implements the TerminalMultiplexer interface for GNU screen
+type ScreenMultiplexer struct {
+	sessionPrefix string
+	screenPath    string
+	logger        *logger.Logger
+}
+
+// ScreenSession implements the MultiplexerSession interface
+type ScreenSession struct {
+	id          string
+	name        string
+	screenName  string
+	status      interfaces.SessionStatus
+	createdAt   time.Time
+	workingDir  string
+	description string
+	isAttached  bool
+	isRunning   bool
+}
+
+// GetID returns the session ID
+func (s *ScreenSession) GetID() string {
+	return s.id
+}
+
+// GetName returns the session name
+func (s *ScreenSession) GetName() string {
+	return s.name
+}
+
+// GetStatus returns the session status
+func (s *ScreenSession) GetStatus() interfaces.SessionStatus {
+	return s.status
+}
+
+// GetCreatedAt returns the creation time
+func (s *ScreenSession) GetCreatedAt() time.Time {
+	return s.createdAt
+}
+
+// GetWorkingDir returns the working directory
+func (s *ScreenSession) GetWorkingDir() string {
+	return s.workingDir
+}
+
+// GetDescription returns the session description
+func (s *ScreenSession) GetDescription() string {
+	return s.description
+}
+
+// IsAttached returns whether someone is attached to the session
+func (s *ScreenSession) IsAttached() bool {
+	return s.isAttached
+}
+
+// IsRunning returns whether the session is running
+func (s *ScreenSession) IsRunning() bool {
+	return s.isRunning
+}
+
+// NewScreenMultiplexer creates a new screen multiplexer instance
+func NewScreenMultiplexer(sessionPrefix string) (*ScreenMultiplexer, error) {
+	// Create a disabled logger by default for backward compatibility
+	disabledLogger, _ := logger.Setup.Disabled().Build()
+	return NewScreenMultiplexerWithLogger(sessionPrefix, disabledLogger)
+}
+
+// NewScreenMultiplexerWithLogger creates a new screen multiplexer instance with logger
+func NewScreenMultiplexerWithLogger(sessionPrefix string, log *logger.Logger) (*ScreenMultiplexer, error) {
+	return NewScreenMultiplexerWithOptions(Options{SessionPrefix: sessionPrefix, Logger: log})
+}
+
+// NewScreenMultiplexerWithOptions creates a new screen multiplexer instance from options
+func NewScreenMultiplexerWithOptions(opts Options) (*ScreenMultiplexer, error) {
+	sessionPrefix := opts.sessionPrefix()
+	log := opts.logger()
+
+	log.Debug("Initializing screen multiplexer", "session_prefix", sessionPrefix)
+
+	screenPath, found, err := resolveBinary("screen", opts.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		log.Debug("Found screen binary", "path", screenPath)
+	}
+
+	sm := &ScreenMultiplexer{
+		sessionPrefix: sessionPrefix,
+		screenPath:    screenPath,
+		logger:        log,
+	}
+
+	log.Info("Screen multiplexer initialized",
+		"session_prefix", sessionPrefix,
+		"screen_path", screenPath)
+
+	return sm, nil
+}
+
+// GetName returns the name of the multiplexer backend
+func (sm *ScreenMultiplexer) GetName() string {
+	return "screen"
+}
+
+// IsAvailable checks if screen is available on the system
+func (sm *ScreenMultiplexer) IsAvailable() bool {
+	_, err := exec.LookPath(sm.screenPath)
+	return err == nil
+}
+
+// CreateSession creates a new screen session, or adds a window to an existing one
+func (sm *ScreenMultiplexer) CreateSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	start := time.Now()
+
+	// Handle attachment to existing session
+	if req.AttachTo != "" && req.AttachmentType != interfaces.AttachmentNone {
+		return sm.createAttachedSession(req)
+	}
+
+	return sm.createStandaloneSession(req, start)
+}
+
+// createStandaloneSession creates a new detached screen session
+func (sm *ScreenMultiplexer) createStandaloneSession(req interfaces.CreateSessionRequest, start time.Time) (interfaces.MultiplexerSession, error) {
+	screenName := sm.sessionName(req.Name)
+
+	sm.logger.Debug("Creating screen session",
+		"name", req.Name,
+		"screen_name", screenName,
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if sm.HasSession(req.Name) {
+		return nil, fmt.Errorf("screen session '%s' already exists", req.Name)
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+
+	cmd := exec.Command(sm.screenPath, "-dmS", screenName, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), envAssignments(req.Env)...)
+	if req.WorkingDir != "" {
+		cmd.Dir = req.WorkingDir
+	}
+
+	sm.logger.DebugCommand(sm.screenPath, cmd.Args[1:], req.WorkingDir)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		sm.logger.Error("Failed to create screen session",
+			"name", req.Name,
+			"screen_name", screenName,
+			"command", strings.Join(cmd.Args, " "),
+			"output", strings.TrimSpace(string(output)),
+			"error", err)
+		return nil, fmt.Errorf("failed to create screen session: %w", err)
+	}
+
+	session := &ScreenSession{
+		id:          screenName,
+		name:        req.Name,
+		screenName:  screenName,
+		status:      interfaces.StatusActive,
+		createdAt:   time.Now(),
+		workingDir:  req.WorkingDir,
+		description: req.Description,
+		isAttached:  false,
+		isRunning:   true,
+	}
+
+	sm.logger.Performance("CreateSession", start,
+		slog.String("name", req.Name),
+		slog.String("screen_name", screenName))
+
+	sm.logger.Info("Screen session created successfully",
+		"name", req.Name,
+		"screen_name", screenName,
+		"command", command)
+
+	return session, nil
+}
+
+// createAttachedSession creates a new window in an existing screen session.
+// Screen regions only exist on an attached display, so pane splits are not supported.
+func (sm *ScreenMultiplexer) createAttachedSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	targetScreenName := sm.sessionName(req.AttachTo)
+
+	sm.logger.Debug("Creating attached session",
+		"name", req.Name,
+		"attach_to", req.AttachTo,
+		"target_screen_name", targetScreenName,
+		"attachment_type", req.AttachmentType,
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if req.AttachmentType != interfaces.AttachmentWindow {
+		return nil, fmt.Errorf("screen backend does not support attachment type '%s', use a window instead", req.AttachmentType)
+	}
+
+	if !sm.HasSession(req.AttachTo) {
+		return nil, fmt.Errorf("target session '%s' does not exist", req.AttachTo)
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+
+	cmd := sm.buildNewWindowCommand(targetScreenName, req.WorkingDir, req.Name, req.Env, command)
+
+	sm.logger.DebugCommand(sm.screenPath, cmd.Args[1:], req.WorkingDir)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		sm.logger.Error("Failed to create attached session",
+			"name", req.Name,
+			"attach_to", req.AttachTo,
+			"attachment_type", req.AttachmentType,
+			"command", strings.Join(cmd.Args, " "),
+			"output", strings.TrimSpace(string(output)),
+			"error", err)
+		return nil, fmt.Errorf("failed to create attached session: %w", err)
+	}
+
+	// Create a virtual session object representing the window
+	attachedSession := &ScreenSession{
+		id:          fmt.Sprintf("%s-%s-attached", targetScreenName, req.Name),
+		name:        fmt.Sprintf("%s-attached-to-%s", req.Name, req.AttachTo),
+		screenName:  targetScreenName, // Points to the parent session
+		status:      interfaces.StatusActive,
+		createdAt:   time.Now(),
+		workingDir:  req.WorkingDir,
+		description: fmt.Sprintf("%s (attached as %s to %s)", req.Description, req.AttachmentType, req.AttachTo),
+		isAttached:  false,
+		isRunning:   true,
+	}
+
+	sm.logger.Info("Attached session created successfully",
+		"name", req.Name,
+		"attach_to", req.AttachTo,
+		"attachment_type", req.AttachmentType,
+		"command", command)
+
+	return attachedSession, nil
+}
+
+// buildNewWindowCommand builds the screen command for opening a new window
+func (sm *ScreenMultiplexer) buildNewWindowCommand(targetSession, workingDir, windowName string, env map[string]string, command string) *exec.Cmd {
+	args := []string{"-S", targetSession, "-X", "screen"}
+
+	if windowName != "" {
+		args = append(args, "-t", windowName)
+	}
+
+	// New windows start in the directory of the screen server, so change
+	// directory inside the window's shell instead
+	if workingDir != "" {
+		command = fmt.Sprintf("cd %s && %s", utils.ShellQuote(workingDir), command)
+	}
+
+	// The window is spawned by the screen server rather than by this
+	// process, so its environment is set through env
+	if assignments := envAssignments(env); len(assignments) > 0 {
+		args = append(args, "env")
+		args = append(args, assignments...)
+	}
+
+	args = append(args, "sh", "-c", command)
+
+	return exec.Command(sm.screenPath, args...)
+}
+
+// GetSession retrieves session information by name
+func (sm *ScreenMultiplexer) GetSession(name string) (interfaces.MultiplexerSession, error) {
+	sessions, err := sm.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.GetName() == name {
+			return session, nil
+		}
+	}
+
+	return nil, fmt.Errorf("screen session '%s' not found", name)
+}
+
+// ListSessions returns all screen sessions owned by claude-pilot
+func (sm *ScreenMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, error) {
+	// screen -ls exits non-zero even when sessions exist, so only the
+	// output is inspected
+	cmd := exec.Command(sm.screenPath, "-ls")
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("failed to list screen sessions: %w", err)
+	}
+
+	return sm.parseSessionList(string(output)), nil
+}
+
+// parseSessionList parses the output of `screen -ls`
+func (sm *ScreenMultiplexer) parseSessionList(output string) []interfaces.MultiplexerSession {
+	var sessions []interfaces.MultiplexerSession
+
+	for _, line := range strings.Split(output, "\n") {
+		match := screenSessionPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		screenName := match[2]
+
+		// Only include our claude-pilot sessions
+		if !strings.HasPrefix(screenName, sm.sessionPrefix+"-") {
+			continue
+		}
+
+		// Dead sessions are left behind after a crash until `screen -wipe`
+		details := match[3]
+		if strings.Contains(details, "(Dead") {
+			continue
+		}
+
+		createdAt := time.Now() // fallback when screen omits the date
+		for _, field := range strings.Split(details, "\t") {
+			field = strings.Trim(strings.TrimSpace(field), "()")
+			if parsed, ok := parseScreenTime(field); ok {
+				createdAt = parsed
+				break
+			}
+		}
+
+		sessions = append(sessions, &ScreenSession{
+			id:         match[1] + "." + screenName,
+			name:       strings.TrimPrefix(screenName, sm.sessionPrefix+"-"),
+			screenName: screenName,
+			status:     interfaces.StatusActive,
+			createdAt:  createdAt,
+			isAttached: strings.Contains(strings.ToLower(details), "attached"),
+			isRunning:  true,
+		})
+	}
+
+	return sessions
+}
+
+// parseScreenTime parses the creation date shown by `screen -ls`
+func parseScreenTime(value string) (time.Time, bool) {
+	for _, layout := range screenTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// AttachToSession attaches to an existing screen session
+func (sm *ScreenMultiplexer) AttachToSession(name string) error {
+	session, err := sm.GetSession(name)
+	if err != nil {
+		return err
+	}
+
+	// -x allows attaching even when another display is attached, like tmux
+	cmd := exec.Command(sm.screenPath, "-x", session.GetID())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// KillSession terminates a screen session
+func (sm *ScreenMultiplexer) KillSession(name string) error {
+	session, err := sm.GetSession(name)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(sm.screenPath, "-S", session.GetID(), "-X", "quit")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to kill screen session: %w", err)
+	}
+
+	return nil
+}
+
+// IsSessionRunning checks if a session is currently running
+func (sm *ScreenMultiplexer) IsSessionRunning(name string) bool {
+	session, err := sm.GetSession(name)
+	if err != nil {
+		return false
+	}
+	return session.IsRunning()
+}
+
+// HasSession checks if a session exists
+func (sm *ScreenMultiplexer) HasSession(name string) bool {
+	_, err := sm.GetSession(name)
+	return err == nil
+}
+
+// GetSessionPaneCount returns the number of windows in a screen session
+func (sm *ScreenMultiplexer) GetSessionPaneCount(name string) (int, error) {
+	session, err := sm.GetSession(name)
+	if err != nil {
+		return 0, fmt.Errorf("session '%s' not found", name)
+	}
+
+	cmd := exec.Command(sm.screenPath, "-S", session.GetID(), "-Q", "windows")
+	output, err := cmd.Output()
+	if err != nil {
+		// -Q needs screen 4.1 or newer; a running session has at least one window
+		sm.logger.Warn("Failed to query screen windows, assuming one",
+			"name", name,
+			"error", err)
+		return 1, nil
+	}
+
+	windowCount := countScreenWindows(string(output))
+	sm.logger.Debug("Retrieved window count for session",
+		"name", name,
+		"screen_name", session.GetID(),
+		"window_count", windowCount)
+
+	return windowCount, nil
+}
+
+// sessionName returns the screen session name for a claude-pilot session
+func (sm *ScreenMultiplexer) sessionName(name string) string {
+	return fmt.Sprintf("%s-%s", sm.sessionPrefix, name)
+}
+
+// countScreenWindows counts the entries of `screen -Q windows` output
+func countScreenWindows(output string) int {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return 0
+	}
+	return len(screenWindowPattern.FindAllString(output+" ", -1))
+}
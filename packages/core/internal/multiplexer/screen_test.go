@@ -0,0 +1,70 @@
+package multiplexer
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScreenParseSessionList(t *testing.T) {
+	sm := &ScreenMultiplexer{sessionPrefix: "claude-"}
+
+	output := "There are screens on:\n" +
+		"\t4242.claude--api\t(01/02/2025 03:04:05 PM)\t(Attached)\n" +
+		"\t4343.claude--web\t(Detached)\n" +
+		"\t4444.claude--old\t(Dead ???)\n" +
+		"\t4545.personal\t(Detached)\n" +
+		"4 Sockets in /run/screen/S-user.\n"
+
+	sessions := sm.parseSessionList(output)
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	api := sessions[0]
+	if api.GetName() != "api" || api.GetID() != "4242.claude--api" {
+		t.Errorf("unexpected session identity: name=%s id=%s", api.GetName(), api.GetID())
+	}
+	if !api.IsAttached() {
+		t.Error("expected api session to be attached")
+	}
+	if want := time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local); !api.GetCreatedAt().Equal(want) {
+		t.Errorf("expected created at %v, got %v", want, api.GetCreatedAt())
+	}
+
+	if sessions[1].IsAttached() {
+		t.Error("expected web session to be detached")
+	}
+}
+
+func TestCountScreenWindows(t *testing.T) {
+	tests := map[string]int{
+		"":                         0,
+		"0$ sh":                    1,
+		"0-$ sh  1*$ claude code":  2,
+		"0 bash  1 logs  2* vim  ": 3,
+	}
+
+	for output, want := range tests {
+		if got := countScreenWindows(output); got != want {
+			t.Errorf("countScreenWindows(%q) = %d, want %d", output, got, want)
+		}
+	}
+}
+
+func TestScreenNewWindowCommand(t *testing.T) {
+	sm := &ScreenMultiplexer{screenPath: "screen"}
+
+	cmd := sm.buildNewWindowCommand("claude-api", "/src/api", "tests", map[string]string{"B": "2", "A": "1 2"}, "go test ./...")
+	want := []string{"screen", "-S", "claude-api", "-X", "screen", "-t", "tests",
+		"env", "A=1 2", "B=2", "sh", "-c", "cd /src/api && go test ./..."}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("args = %q, want %q", cmd.Args, want)
+	}
+
+	cmd = sm.buildNewWindowCommand("claude-api", "", "", nil, "claude")
+	want = []string{"screen", "-S", "claude-api", "-X", "screen", "sh", "-c", "claude"}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("args without env = %q, want %q", cmd.Args, want)
+	}
+}
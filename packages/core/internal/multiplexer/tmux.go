@@ -5,11 +5,14 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"claude-pilot/core/internal/logger"
+	"claude-pilot/core/internal/utils"
 	"claude-pilot/shared/interfaces"
 )
 
@@ -17,9 +20,20 @@ import (
 type TmuxMultiplexer struct {
 	sessionPrefix string
 	tmuxPath      string
+	socketName    string       // -L: named socket in tmux's default socket directory
+	socketPath    string       // -S: full socket path, takes precedence over socketName
+	layout        string       // select-layout of new sessions, none when empty
+	control       *tmuxControl // nil unless control mode is enabled
 	logger        *logger.Logger
+
+	envFlagOnce sync.Once
+	envFlag     bool // Whether tmux takes -e to set the environment of new panes
 }
 
+// tmuxSessionFormat is the list-sessions format parsed by parseSessions. The
+// path of the session's active pane is last because it may contain commas.
+const tmuxSessionFormat = "#{session_name},#{session_created},#{session_attached},#{pane_current_path}"
+
 // TmuxSession implements the MultiplexerSession interface
 type TmuxSession struct {
 	id          string
@@ -82,46 +96,91 @@ func NewTmuxMultiplexer(sessionPrefix string) (*TmuxMultiplexer, error) {
 
 // NewTmuxMultiplexerWithLogger creates a new tmux multiplexer instance with logger
 func NewTmuxMultiplexerWithLogger(sessionPrefix string, log *logger.Logger) (*TmuxMultiplexer, error) {
-	if sessionPrefix == "" {
-		sessionPrefix = "claude-pilot"
-	}
+	return NewTmuxMultiplexerWithOptions(Options{SessionPrefix: sessionPrefix, Logger: log})
+}
+
+// NewTmuxMultiplexerWithOptions creates a new tmux multiplexer instance from options
+func NewTmuxMultiplexerWithOptions(opts Options) (*TmuxMultiplexer, error) {
+	sessionPrefix := opts.sessionPrefix()
+	log := opts.logger()
 
 	log.Debug("Initializing tmux multiplexer", "session_prefix", sessionPrefix)
 
-	// Try to find tmux binary
-	tmuxPath := "tmux" // default
-	if _, err := exec.LookPath("tmux"); err != nil {
-		log.Debug("tmux not found in PATH, trying common locations")
-		// Try common locations
-		paths := []string{
-			"/opt/homebrew/bin/tmux",
-			"/usr/local/bin/tmux",
-			"/usr/bin/tmux",
-		}
-		for _, path := range paths {
-			if _, err := os.Stat(path); err == nil {
-				tmuxPath = path
-				log.Debug("Found tmux binary", "path", path)
-				break
-			}
-		}
-	} else {
-		log.Debug("Found tmux in PATH")
+	// Try to find tmux binary, preferring a configured backend_path
+	tmuxPath, found, err := resolveBinary("tmux", opts.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		log.Debug("Found tmux binary", "path", tmuxPath)
 	}
 
 	tm := &TmuxMultiplexer{
 		sessionPrefix: sessionPrefix,
 		tmuxPath:      tmuxPath,
+		socketName:    opts.TmuxSocketName,
+		socketPath:    opts.TmuxSocketPath,
+		layout:        opts.TmuxLayout,
 		logger:        log,
 	}
+	if opts.TmuxControlMode {
+		tm.control = newTmuxControl(tm)
+	}
 
 	log.Info("Tmux multiplexer initialized",
 		"session_prefix", sessionPrefix,
-		"tmux_path", tmuxPath)
+		"tmux_path", tmuxPath,
+		"socket_name", tm.socketName,
+		"socket_path", tm.socketPath,
+		"control_mode", opts.TmuxControlMode)
 
 	return tm, nil
 }
 
+// command builds a tmux command that talks to the configured server socket
+func (tm *TmuxMultiplexer) command(args ...string) *exec.Cmd {
+	var socketArgs []string
+	switch {
+	case tm.socketPath != "":
+		socketArgs = []string{"-S", tm.socketPath}
+	case tm.socketName != "":
+		socketArgs = []string{"-L", tm.socketName}
+	}
+
+	return exec.Command(tm.tmuxPath, append(socketArgs, args...)...)
+}
+
+// cachedState returns session state from the control client, if it is
+// enabled and reachable. Callers fall back to running tmux commands.
+func (tm *TmuxMultiplexer) cachedState() (*tmuxSnapshot, bool) {
+	if tm.control == nil {
+		return nil, false
+	}
+
+	snapshot, err := tm.control.state()
+	if err != nil {
+		tm.logger.Debug("Tmux control state unavailable, running tmux directly", "error", err)
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// invalidateState drops cached state after this process changed it
+func (tm *TmuxMultiplexer) invalidateState() {
+	if tm.control != nil {
+		tm.control.invalidate()
+	}
+}
+
+// SubscribeSessionEvents streams tmux control-mode notifications. The channel
+// is nil when control mode is disabled.
+func (tm *TmuxMultiplexer) SubscribeSessionEvents() (<-chan interfaces.SessionEvent, func()) {
+	if tm.control == nil {
+		return nil, func() {}
+	}
+	return tm.control.subscribe()
+}
+
 // GetName returns the name of the multiplexer backend
 func (tm *TmuxMultiplexer) GetName() string {
 	return "tmux"
@@ -129,22 +188,8 @@ func (tm *TmuxMultiplexer) GetName() string {
 
 // IsAvailable checks if tmux is available on the system
 func (tm *TmuxMultiplexer) IsAvailable() bool {
-	_, err := exec.LookPath("tmux")
-	if err != nil {
-		// Check common paths
-		paths := []string{
-			"/opt/homebrew/bin/tmux",
-			"/usr/local/bin/tmux",
-			"/usr/bin/tmux",
-		}
-		for _, path := range paths {
-			if _, err := os.Stat(path); err == nil {
-				return true
-			}
-		}
-		return false
-	}
-	return true
+	_, err := exec.LookPath(tm.tmuxPath)
+	return err == nil
 }
 
 // CreateSession creates a new tmux session, or attaches to existing session as pane/window
@@ -185,17 +230,24 @@ func (tm *TmuxMultiplexer) createStandaloneSession(req interfaces.CreateSessionR
 	}
 
 	// Create tmux session with specified command
-	var cmd *exec.Cmd
+	args := []string{"new-session", "-d", "-s", tmuxName}
 	if req.WorkingDir != "" {
 		// Create session in specific directory
-		cmd = exec.Command(tm.tmuxPath, "new-session", "-d", "-s", tmuxName, "-c", req.WorkingDir, command)
-	} else {
-		// Create session in current directory
-		cmd = exec.Command(tm.tmuxPath, "new-session", "-d", "-s", tmuxName, command)
+		args = append(args, "-c", req.WorkingDir)
+	}
+	// The tmux server may be older than this process, so the environment
+	// is passed explicitly instead of inherited
+	args = append(args, tm.commandArgs(req.Env, command)...)
+	if req.OutputPipe != "" {
+		// In the same command list, the pipe is in place before tmux reads
+		// anything the command prints
+		args = append(args, ";", "pipe-pane", req.OutputPipe)
 	}
+	cmd := tm.command(args...)
 
 	tm.logger.DebugCommand(tm.tmuxPath, cmd.Args[1:], req.WorkingDir)
 
+	defer tm.invalidateState()
 	if err := cmd.Run(); err != nil {
 		tm.logger.Error("Failed to create tmux session",
 			"name", req.Name,
@@ -258,9 +310,9 @@ func (tm *TmuxMultiplexer) createAttachedSession(req interfaces.CreateSessionReq
 
 	switch req.AttachmentType {
 	case interfaces.AttachmentPane:
-		cmd, err = tm.buildSplitPaneCommand(targetTmuxName, req.WorkingDir, req.SplitDirection, command)
+		cmd, err = tm.buildSplitPaneCommand(targetTmuxName, req.WorkingDir, req.SplitDirection, req.Env, command)
 	case interfaces.AttachmentWindow:
-		cmd, err = tm.buildNewWindowCommand(targetTmuxName, req.WorkingDir, req.Name, command)
+		cmd, err = tm.buildNewWindowCommand(targetTmuxName, req.WorkingDir, req.Name, req.Env, command)
 	default:
 		return nil, fmt.Errorf("unsupported attachment type: %s", req.AttachmentType)
 	}
@@ -271,7 +323,9 @@ func (tm *TmuxMultiplexer) createAttachedSession(req interfaces.CreateSessionReq
 
 	tm.logger.DebugCommand(tm.tmuxPath, cmd.Args[1:], req.WorkingDir)
 
-	if err := cmd.Run(); err != nil {
+	defer tm.invalidateState()
+	output, err := cmd.Output()
+	if err != nil {
 		tm.logger.Error("Failed to create attached session",
 			"name", req.Name,
 			"attach_to", req.AttachTo,
@@ -280,11 +334,15 @@ func (tm *TmuxMultiplexer) createAttachedSession(req interfaces.CreateSessionReq
 			"error", err)
 		return nil, fmt.Errorf("failed to create attached session: %w", err)
 	}
+	target := strings.TrimSpace(string(output)) // Pane or window ID, the target of the attachment
+	if req.AttachmentType == interfaces.AttachmentPane {
+		tm.selectLayout(target)
+	}
 
 	// Create a virtual session object representing the attachment
 	// Note: This doesn't create a separate tmux session, but represents the pane/window
 	attachedSession := &TmuxSession{
-		id:          fmt.Sprintf("%s-%s-attached", targetTmuxName, req.Name),
+		id:          target,
 		name:        fmt.Sprintf("%s-attached-to-%s", req.Name, req.AttachTo),
 		tmuxName:    targetTmuxName, // Points to the parent session
 		status:      interfaces.StatusActive,
@@ -304,31 +362,26 @@ func (tm *TmuxMultiplexer) createAttachedSession(req interfaces.CreateSessionReq
 	return attachedSession, nil
 }
 
-// buildSplitPaneCommand builds the tmux command for creating a new pane
-func (tm *TmuxMultiplexer) buildSplitPaneCommand(targetSession, workingDir string, splitDir interfaces.SplitDirection, command string) (*exec.Cmd, error) {
-	args := []string{"split-window", "-t", targetSession}
-
-	// Add split direction
-        if splitDir == interfaces.SplitHorizontal {
-                args = append(args, "-v") // tmux -v means horizontal split (left/right)
-        } else {
-                args = append(args, "-h") // tmux -h means vertical split (top/bottom)
-        }
+// buildSplitPaneCommand builds the tmux command for creating a new pane,
+// which prints the ID of the pane
+func (tm *TmuxMultiplexer) buildSplitPaneCommand(targetSession, workingDir string, splitDir interfaces.SplitDirection, env map[string]string, command string) (*exec.Cmd, error) {
+	args := []string{"split-window", "-P", "-F", "#{pane_id}", "-t", targetSession, splitFlag(splitDir)}
 
 	// Add working directory if specified
 	if workingDir != "" {
 		args = append(args, "-c", workingDir)
 	}
 
-	// Add command
-	args = append(args, command)
+	// Add environment and command
+	args = append(args, tm.commandArgs(env, command)...)
 
-	return exec.Command(tm.tmuxPath, args...), nil
+	return tm.command(args...), nil
 }
 
-// buildNewWindowCommand builds the tmux command for creating a new window
-func (tm *TmuxMultiplexer) buildNewWindowCommand(targetSession, workingDir, windowName, command string) (*exec.Cmd, error) {
-	args := []string{"new-window", "-t", targetSession}
+// buildNewWindowCommand builds the tmux command for creating a new window,
+// which prints the ID of the window
+func (tm *TmuxMultiplexer) buildNewWindowCommand(targetSession, workingDir, windowName string, env map[string]string, command string) (*exec.Cmd, error) {
+	args := []string{"new-window", "-P", "-F", "#{window_id}", "-t", targetSession}
 
 	// Add window name if provided
 	if windowName != "" {
@@ -340,10 +393,160 @@ func (tm *TmuxMultiplexer) buildNewWindowCommand(targetSession, workingDir, wind
 		args = append(args, "-c", workingDir)
 	}
 
-	// Add command
-	args = append(args, command)
+	// Add environment and command
+	args = append(args, tm.commandArgs(env, command)...)
+
+	return tm.command(args...), nil
+}
+
+// commandArgs returns the trailing arguments of a tmux command that starts
+// command, the default shell when empty, with env set. tmux takes -e for
+// new-session, new-window and split-window since 3.2; with older versions
+// the command runs through env(1), and a default shell gets no variables.
+func (tm *TmuxMultiplexer) commandArgs(env map[string]string, command string) []string {
+	assignments := envAssignments(env)
+
+	var args []string
+	switch {
+	case len(assignments) == 0 || tm.hasEnvFlag():
+		for _, assignment := range assignments {
+			args = append(args, "-e", assignment)
+		}
+	case command != "":
+		quoted := make([]string, len(assignments))
+		for i, assignment := range assignments {
+			quoted[i] = utils.ShellQuote(assignment)
+		}
+		command = fmt.Sprintf("env %s sh -c %s", strings.Join(quoted, " "), utils.ShellQuote(command))
+	default:
+		tm.logger.Debug("Tmux before 3.2 cannot set the environment of a shell", "env", assignments)
+	}
+
+	if command != "" {
+		args = append(args, command)
+	}
+	return args
+}
+
+// hasEnvFlag reports whether the tmux binary is 3.2 or newer, which takes -e
+// when creating sessions, windows and panes. Versions that cannot be told,
+// such as builds from master, are assumed to be recent.
+func (tm *TmuxMultiplexer) hasEnvFlag() bool {
+	tm.envFlagOnce.Do(func() {
+		tm.envFlag = true
+
+		output, err := exec.Command(tm.tmuxPath, "-V").Output()
+		if err != nil {
+			return
+		}
+		if major, minor, ok := parseTmuxVersion(string(output)); ok {
+			tm.envFlag = major > 3 || (major == 3 && minor >= 2)
+		}
+	})
+	return tm.envFlag
+}
+
+// parseTmuxVersion parses the major and minor version from the output of
+// tmux -V, e.g. "tmux 3.2a" or "tmux next-3.4"
+func parseTmuxVersion(output string) (int, int, bool) {
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(output), "tmux"))
+	version = strings.TrimPrefix(version, "next-")
+
+	majorText, rest, found := strings.Cut(version, ".")
+	if !found {
+		return 0, 0, false
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		rest = rest[:end]
+	}
+
+	major, err := strconv.Atoi(majorText)
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
 
-	return exec.Command(tm.tmuxPath, args...), nil
+// splitFlag returns the split-window flag for a split direction
+func splitFlag(splitDir interfaces.SplitDirection) string {
+	if splitDir == interfaces.SplitHorizontal {
+		return "-v" // tmux -v means horizontal split (left/right)
+	}
+	return "-h" // tmux -h means vertical split (top/bottom)
+}
+
+// SplitPanes splits the session's window into the panes of a blueprint, each
+// splitting the pane created before it, and applies the configured layout
+// unless the blueprint arranges its panes itself. Claude's pane keeps the
+// focus.
+func (tm *TmuxMultiplexer) SplitPanes(name string, panes []interfaces.BlueprintPane, env map[string]string) error {
+	first, err := tm.paneTarget(name, "")
+	if err != nil {
+		return err
+	}
+	defer tm.invalidateState()
+
+	target := first
+	for i, pane := range panes {
+		args := []string{"split-window", "-P", "-F", "#{pane_id}", "-t", target, splitFlag(pane.SplitDirection)}
+		if pane.Size > 0 {
+			args = append(args, "-l", fmt.Sprintf("%d%%", pane.Size))
+		}
+		if pane.WorkingDir != "" {
+			args = append(args, "-c", pane.WorkingDir)
+		}
+		args = append(args, tm.commandArgs(env, pane.Command)...)
+		cmd := tm.command(args...)
+		tm.logger.DebugCommand(tm.tmuxPath, cmd.Args[1:], pane.WorkingDir)
+
+		output, err := cmd.Output()
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return fmt.Errorf("failed to create pane %d of session '%s': %w", i+1, name, err)
+		}
+		target = strings.TrimSpace(string(output))
+	}
+
+	if !arrangesPanes(panes) {
+		tm.selectLayout(first)
+	}
+	if output, err := tm.command("select-pane", "-t", first).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to select pane of session '%s': %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// arrangesPanes reports whether a blueprint gives its panes a split or a
+// size, which the configured layout would undo
+func arrangesPanes(panes []interfaces.BlueprintPane) bool {
+	for _, pane := range panes {
+		if pane.SplitDirection != "" || pane.Size > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// selectLayout applies the configured layout to the window of target. A
+// layout tmux rejects is only logged, since the session works without it.
+func (tm *TmuxMultiplexer) selectLayout(target string) {
+	if tm.layout == "" {
+		return
+	}
+	if output, err := tm.command("select-layout", "-t", target, tm.layout).CombinedOutput(); err != nil {
+		tm.logger.Warn("Failed to apply tmux layout",
+			"target", target,
+			"layout", tm.layout,
+			"error", err,
+			"output", strings.TrimSpace(string(output)))
+	}
 }
 
 // GetSession retrieves session information by name
@@ -364,34 +567,51 @@ func (tm *TmuxMultiplexer) GetSession(name string) (interfaces.MultiplexerSessio
 
 // ListSessions returns all available tmux sessions
 func (tm *TmuxMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, error) {
+	if snapshot, ok := tm.cachedState(); ok {
+		return slices.Clone(snapshot.sessions), nil
+	}
+
 	// Get list of tmux sessions
-	cmd := exec.Command(tm.tmuxPath, "list-sessions", "-F", "#{session_name},#{session_created},#{session_attached}")
+	cmd := tm.command("list-sessions", "-F", tmuxSessionFormat)
 	output, err := cmd.Output()
 	if err != nil {
-		// If no sessions exist, tmux returns exit code 1
-		if strings.Contains(err.Error(), "no server running") || strings.Contains(err.Error(), "no sessions") {
-			return []interfaces.MultiplexerSession{}, nil
+		// If no sessions exist, tmux returns exit code 1. A dedicated server
+		// exits with its last session, leaving nothing to connect to.
+		var stderr string
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		for _, msg := range []string{"no server running", "no sessions", "error connecting to"} {
+			if strings.Contains(stderr, msg) {
+				return []interfaces.MultiplexerSession{}, nil
+			}
 		}
 		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
 	}
 
+	return tm.parseSessions(strings.Split(strings.TrimSpace(string(output)), "\n")), nil
+}
+
+// parseSessions parses list-sessions output in tmuxSessionFormat, keeping
+// only claude-pilot sessions
+func (tm *TmuxMultiplexer) parseSessions(lines []string) []interfaces.MultiplexerSession {
 	var sessions []interfaces.MultiplexerSession
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 
 	for _, line := range lines {
 		if line == "" {
 			continue
 		}
 
-		parts := strings.Split(line, ",")
+		parts := strings.SplitN(line, ",", 4)
 		if len(parts) < 3 {
 			continue
 		}
 
 		sessionName := parts[0]
 
-		// Only include our claude-pilot sessions
-		if !strings.HasPrefix(sessionName, tm.sessionPrefix+"-") {
+		// Only include our claude-pilot sessions, without any control
+		// client's session, whose name may start with another prefix
+		if !strings.HasPrefix(sessionName, tm.sessionPrefix+"-") || strings.HasPrefix(sessionName, tmuxControlSessionPrefix) {
 			continue
 		}
 
@@ -411,12 +631,18 @@ func (tm *TmuxMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, erro
 		// Parse attached status
 		isAttached := parts[2] == "1"
 
+		var workingDir string
+		if len(parts) > 3 {
+			workingDir = parts[3]
+		}
+
 		session := &TmuxSession{
 			id:         sessionName,
 			name:       name,
 			tmuxName:   sessionName,
 			status:     interfaces.StatusActive,
 			createdAt:  createdAt,
+			workingDir: workingDir,
 			isAttached: isAttached,
 			isRunning:  true,
 		}
@@ -424,7 +650,7 @@ func (tm *TmuxMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, erro
 		sessions = append(sessions, session)
 	}
 
-	return sessions, nil
+	return sessions
 }
 
 // AttachToSession attaches to an existing tmux session
@@ -437,7 +663,7 @@ func (tm *TmuxMultiplexer) AttachToSession(name string) error {
 	tmuxSession := session.(*TmuxSession)
 
 	// Attach to the tmux session
-	cmd := exec.Command(tm.tmuxPath, "attach-session", "-t", tmuxSession.tmuxName)
+	cmd := tm.command("attach-session", "-t", tmuxSession.tmuxName)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -455,7 +681,8 @@ func (tm *TmuxMultiplexer) KillSession(name string) error {
 	tmuxSession := session.(*TmuxSession)
 
 	// Kill the tmux session
-	cmd := exec.Command(tm.tmuxPath, "kill-session", "-t", tmuxSession.tmuxName)
+	cmd := tm.command("kill-session", "-t", tmuxSession.tmuxName)
+	defer tm.invalidateState()
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to kill tmux session: %w", err)
 	}
@@ -475,10 +702,201 @@ func (tm *TmuxMultiplexer) IsSessionRunning(name string) bool {
 // HasSession checks if a session exists
 func (tm *TmuxMultiplexer) HasSession(name string) bool {
 	tmuxName := fmt.Sprintf("%s-%s", tm.sessionPrefix, name)
-	cmd := exec.Command(tm.tmuxPath, "has-session", "-t", tmuxName)
+
+	// Every session has at least one pane, so the pane counts list all sessions
+	if snapshot, ok := tm.cachedState(); ok {
+		_, found := snapshot.panes[tmuxName]
+		return found
+	}
+
+	cmd := tm.command("has-session", "-t", tmuxName)
 	return cmd.Run() == nil
 }
 
+// CapturePane returns the text of a pane, by default the visible screen of
+// the session's first pane. Panes attached to the session later are split
+// off it, so the first pane is the one the session was created with.
+func (tm *TmuxMultiplexer) CapturePane(name string, opts interfaces.CaptureOptions) (string, error) {
+	pane, err := tm.paneTarget(name, opts.Target)
+	if err != nil {
+		return "", err
+	}
+
+	// -J joins wrapped lines so that patterns do not break at the pane width
+	args := []string{"capture-pane", "-p", "-J", "-t", pane}
+	switch {
+	case opts.Lines < 0:
+		args = append(args, "-S", "-")
+	case opts.Lines > 0:
+		args = append(args, "-S", strconv.Itoa(-opts.Lines))
+	}
+	if opts.ANSI {
+		args = append(args, "-e")
+	}
+
+	output, err := tm.command(args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane of session '%s': %w", name, err)
+	}
+
+	return string(output), nil
+}
+
+// SendText pastes text into a pane of a session through a tmux buffer of
+// its own, leaving the user's paste buffers alone
+func (tm *TmuxMultiplexer) SendText(name, target, text string) error {
+	pane, err := tm.paneTarget(name, target)
+	if err != nil {
+		return err
+	}
+
+	buffer := fmt.Sprintf("claude-pilot-%d-%d", os.Getpid(), time.Now().UnixNano())
+	load := tm.command("load-buffer", "-b", buffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if output, err := load.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load tmux buffer: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	// -p pastes in bracketed paste mode where the program supports it, so
+	// that newlines in the text do not submit it; -r keeps them newlines and
+	// -d deletes the buffer afterwards
+	output, err := tm.command("paste-buffer", "-p", "-r", "-d", "-b", buffer, "-t", pane).CombinedOutput()
+	if err != nil {
+		_ = tm.command("delete-buffer", "-b", buffer).Run()
+		return fmt.Errorf("failed to paste into session '%s': %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SendKeys presses keys, given by tmux key names, in a pane of a session
+func (tm *TmuxMultiplexer) SendKeys(name, target string, keys ...string) error {
+	pane, err := tm.paneTarget(name, target)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"send-keys", "-t", pane}, keys...)
+	if output, err := tm.command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to send keys to session '%s': %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PipeOutput pipes the output of a session's first pane into a shell command
+// with pipe-pane, which replaces any pipe the pane already had
+func (tm *TmuxMultiplexer) PipeOutput(name, command string) error {
+	pane, err := tm.paneTarget(name, "")
+	if err != nil {
+		return err
+	}
+
+	if output, err := tm.command("pipe-pane", "-t", pane, command).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pipe output of session '%s': %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// paneTarget returns the tmux target for a pane or window of a session, e.g.
+// "1", "1.2" or a pane or window ID such as %3. Without a target it is the
+// session's first pane, where Claude runs.
+func (tm *TmuxMultiplexer) paneTarget(name, target string) (string, error) {
+	tmuxName := fmt.Sprintf("%s-%s", tm.sessionPrefix, name)
+	if strings.HasPrefix(target, "%") || strings.HasPrefix(target, "@") {
+		// IDs are unique on the server, and tmux does not resolve them
+		// after a session name
+		return target, nil
+	}
+	if target != "" {
+		return tmuxName + ":" + target, nil
+	}
+
+	output, err := tm.command("list-panes", "-s", "-t", tmuxName, "-F", "#{pane_id}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list panes of session '%s': %w", name, err)
+	}
+	paneID, _, _ := strings.Cut(string(output), "\n")
+	return paneID, nil
+}
+
+// SessionForPane returns the name of the session containing a tmux pane,
+// given its ID as in TMUX_PANE
+func (tm *TmuxMultiplexer) SessionForPane(pane string) (string, error) {
+	output, err := tm.command("display-message", "-p", "-t", pane, "#{session_name}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find session of pane '%s': %w", pane, err)
+	}
+
+	tmuxName := strings.TrimSpace(string(output))
+	name, ok := strings.CutPrefix(tmuxName, tm.sessionPrefix+"-")
+	if !ok {
+		return "", fmt.Errorf("pane '%s' is in tmux session '%s', which is not a claude-pilot session", pane, tmuxName)
+	}
+	return name, nil
+}
+
+// HasTarget reports whether a pane or window, given by its ID, is in a session
+func (tm *TmuxMultiplexer) HasTarget(name, target string) bool {
+	tmuxName := fmt.Sprintf("%s-%s", tm.sessionPrefix, name)
+	output, err := tm.command("list-panes", "-s", "-t", tmuxName, "-F", "#{pane_id} #{window_id}").Output()
+	if err != nil {
+		return false
+	}
+	for line := range strings.Lines(string(output)) {
+		if slices.Contains(strings.Fields(line), target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CloseTarget kills a pane or window of a session, given by its ID. IDs
+// start over when the tmux server restarts, so the target must still be in
+// the session.
+func (tm *TmuxMultiplexer) CloseTarget(name, target string) error {
+	if !tm.HasTarget(name, target) {
+		return fmt.Errorf("'%s' is not a pane or window of session '%s'", target, name)
+	}
+
+	command := "kill-pane"
+	if strings.HasPrefix(target, "@") {
+		command = "kill-window"
+	}
+	defer tm.invalidateState()
+	if output, err := tm.command(command, "-t", target).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to close '%s' of session '%s': %w: %s", target, name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DisplayMessage shows a message in the status line of every client attached
+// to the tmux server
+func (tm *TmuxMultiplexer) DisplayMessage(message string) error {
+	output, err := tm.command("list-clients", "-F", "#{client_name} #{client_control_mode}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list tmux clients: %w", err)
+	}
+
+	var errors []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		client, controlMode, _ := strings.Cut(line, " ")
+		// Control-mode clients, like our own, have no status line
+		if client == "" || controlMode == "1" {
+			continue
+		}
+
+		// Messages are tmux formats; doubling # keeps them literal
+		cmd := tm.command("display-message", "-c", client, strings.ReplaceAll(message, "#", "##"))
+		if err := cmd.Run(); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", client, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to display message: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
 // GetTmuxSessionInfo gets detailed info about a tmux session (legacy compatibility)
 func (tm *TmuxMultiplexer) GetTmuxSessionInfo(name string) (map[string]string, error) {
 	session, err := tm.GetSession(name)
@@ -489,7 +907,7 @@ func (tm *TmuxMultiplexer) GetTmuxSessionInfo(name string) (map[string]string, e
 	tmuxSession := session.(*TmuxSession)
 
 	// Get detailed session info
-	cmd := exec.Command(tm.tmuxPath, "display-message", "-t", tmuxSession.tmuxName, "-p",
+	cmd := tm.command("display-message", "-t", tmuxSession.tmuxName, "-p",
 		"#{session_name},#{session_created},#{session_attached},#{session_windows},#{session_activity}")
 	output, err := cmd.Output()
 	if err != nil {
@@ -537,13 +955,21 @@ func (tm *TmuxMultiplexer) KillAllSessions() error {
 func (tm *TmuxMultiplexer) GetSessionPaneCount(name string) (int, error) {
 	tmuxName := fmt.Sprintf("%s-%s", tm.sessionPrefix, name)
 
+	if snapshot, ok := tm.cachedState(); ok {
+		count, found := snapshot.panes[tmuxName]
+		if !found {
+			return 0, fmt.Errorf("session '%s' not found", name)
+		}
+		return count, nil
+	}
+
 	// Check if session exists first
 	if !tm.HasSession(name) {
 		return 0, fmt.Errorf("session '%s' not found", name)
 	}
 
 	// Get pane count using tmux list-panes command
-	cmd := exec.Command(tm.tmuxPath, "list-panes", "-t", tmuxName, "-F", "#{pane_id}")
+	cmd := tm.command("list-panes", "-t", tmuxName, "-F", "#{pane_id}")
 	output, err := cmd.Output()
 	if err != nil {
 		tm.logger.Error("Failed to get pane count for session",
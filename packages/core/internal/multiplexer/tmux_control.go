@@ -0,0 +1,388 @@
+package multiplexer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+const (
+	// tmuxControlTimeout bounds how long a control-mode command may take
+	tmuxControlTimeout = 5 * time.Second
+
+	// tmuxControlRetry is how long to fall back to plain commands after the
+	// control client failed before starting a new one
+	tmuxControlRetry = 5 * time.Second
+
+	// tmuxSnapshotTTL bounds how stale cached state can get. tmux does not
+	// notify control clients about every change, e.g. other clients attaching.
+	tmuxSnapshotTTL = 5 * time.Second
+)
+
+// tmuxControlSessionPrefix starts the name of the session control clients
+// attach to, which is never listed as a claude-pilot session
+const tmuxControlSessionPrefix = "_claude-pilot-control_"
+
+// errTmuxControlUnavailable is returned while the control client is down
+var errTmuxControlUnavailable = errors.New("tmux control client unavailable")
+
+// tmuxReply is the output of one command sent over the control connection
+type tmuxReply struct {
+	lines []string
+	err   error
+}
+
+// tmuxSnapshot is the session state read over the control connection
+type tmuxSnapshot struct {
+	takenAt  time.Time
+	sessions []interfaces.MultiplexerSession
+	panes    map[string]int // pane count by tmux session name
+}
+
+// tmuxControl keeps a long-lived tmux control-mode client (tmux -C) while
+// anyone subscribes to its events, as the TUI does. Session state is then
+// cached and only re-read when tmux reports a change. One-shot commands never
+// start the client and run tmux directly.
+type tmuxControl struct {
+	tm *TmuxMultiplexer
+
+	mu          sync.Mutex
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	running     bool
+	failedAt    time.Time
+	pending     []chan tmuxReply
+	snapshot    *tmuxSnapshot
+	generation  uint64
+	subscribers map[chan interfaces.SessionEvent]struct{}
+}
+
+// newTmuxControl creates a control client for tm; it is started on first use
+func newTmuxControl(tm *TmuxMultiplexer) *tmuxControl {
+	return &tmuxControl{
+		tm:          tm,
+		subscribers: make(map[chan interfaces.SessionEvent]struct{}),
+	}
+}
+
+// sessionName returns the session the control client attaches to
+func (tc *tmuxControl) sessionName() string {
+	return tmuxControlSessionPrefix + tc.tm.sessionPrefix
+}
+
+// ensureRunning starts the control client if it is not running. Callers must hold tc.mu.
+func (tc *tmuxControl) ensureRunning() error {
+	if tc.running {
+		return nil
+	}
+	if time.Since(tc.failedAt) < tmuxControlRetry {
+		return errTmuxControlUnavailable
+	}
+
+	// The control session is destroyed once no control client is attached
+	cmd := tc.tm.command("-C", "new-session", "-A", "-s", tc.sessionName(),
+		";", "set-option", "-t", tc.sessionName(), "destroy-unattached", "on")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		tc.failedAt = time.Now()
+		return fmt.Errorf("failed to open tmux control stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		tc.failedAt = time.Now()
+		return fmt.Errorf("failed to open tmux control stdout: %w", err)
+	}
+
+	tc.tm.logger.DebugCommand(tc.tm.tmuxPath, cmd.Args[1:], "")
+
+	if err := cmd.Start(); err != nil {
+		tc.failedAt = time.Now()
+		return fmt.Errorf("failed to start tmux control client: %w", err)
+	}
+
+	tc.cmd = cmd
+	tc.stdin = stdin
+	tc.running = true
+	tc.snapshot = nil
+	go tc.read(cmd, stdout)
+
+	// Pane output is not needed; older tmux versions reject the flag, which is harmless
+	tc.send("refresh-client -f no-output")
+
+	tc.tm.logger.Info("Tmux control client started", "session", tc.sessionName())
+	return nil
+}
+
+// send writes a command to the control client and returns the channel its
+// reply is delivered on. Callers must hold tc.mu.
+func (tc *tmuxControl) send(command string) chan tmuxReply {
+	reply := make(chan tmuxReply, 1)
+	if _, err := io.WriteString(tc.stdin, command+"\n"); err != nil {
+		reply <- tmuxReply{err: fmt.Errorf("failed to write tmux control command: %w", err)}
+		return reply
+	}
+
+	// tmux answers commands in the order they were sent
+	tc.pending = append(tc.pending, reply)
+	return reply
+}
+
+// query runs a command over the control connection and returns its output
+// lines. It fails while nobody is subscribed.
+func (tc *tmuxControl) query(command string) ([]string, error) {
+	tc.mu.Lock()
+	if len(tc.subscribers) == 0 {
+		tc.mu.Unlock()
+		return nil, errTmuxControlUnavailable
+	}
+	if err := tc.ensureRunning(); err != nil {
+		tc.mu.Unlock()
+		return nil, err
+	}
+	reply := tc.send(command)
+	tc.mu.Unlock()
+
+	select {
+	case r := <-reply:
+		return r.lines, r.err
+	case <-time.After(tmuxControlTimeout):
+		tc.mu.Lock()
+		cmd := tc.cmd
+		tc.mu.Unlock()
+		tc.stop(cmd, fmt.Errorf("tmux control command timed out: %s", command))
+		return nil, fmt.Errorf("tmux control command timed out: %s", command)
+	}
+}
+
+// read parses the control client's output until it exits. Command output is
+// framed by %begin and %end (or %error) lines; every other line starting
+// with % is a notification.
+func (tc *tmuxControl) read(cmd *exec.Cmd, stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var block []string
+	inBlock, ours := false, false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if inBlock {
+			if strings.HasPrefix(line, "%end ") || strings.HasPrefix(line, "%error ") {
+				inBlock = false
+				if ours {
+					tc.deliver(cmd, block, strings.HasPrefix(line, "%error "))
+				}
+				continue
+			}
+			block = append(block, line)
+			continue
+		}
+
+		if strings.HasPrefix(line, "%begin ") {
+			// The last field is 1 for commands sent by this client, as
+			// opposed to the startup commands and hooks
+			fields := strings.Fields(line)
+			inBlock, ours, block = true, fields[len(fields)-1] == "1", nil
+			continue
+		}
+
+		if event, ok := parseTmuxNotification(line); ok {
+			tc.notify(event)
+		}
+	}
+
+	_ = cmd.Wait()
+	tc.stop(cmd, errTmuxControlUnavailable)
+}
+
+// deliver hands a finished command reply to the oldest waiting caller
+func (tc *tmuxControl) deliver(cmd *exec.Cmd, lines []string, failed bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.cmd != cmd || len(tc.pending) == 0 {
+		return
+	}
+	reply := tc.pending[0]
+	tc.pending = tc.pending[1:]
+
+	if failed {
+		reply <- tmuxReply{err: fmt.Errorf("tmux: %s", strings.Join(lines, "; "))}
+		return
+	}
+	reply <- tmuxReply{lines: lines}
+}
+
+// notify invalidates cached state and forwards the event to subscribers
+func (tc *tmuxControl) notify(event interfaces.SessionEvent) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.snapshot = nil
+	tc.generation++
+
+	for ch := range tc.subscribers {
+		// Subscribers only need to know that something changed, so a full
+		// buffer already carries this event
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// stop marks the control client cmd as failed, releasing waiting callers.
+// Plain tmux commands are used until it is restarted.
+func (tc *tmuxControl) stop(cmd *exec.Cmd, reason error) {
+	tc.mu.Lock()
+	if !tc.running || tc.cmd != cmd {
+		tc.mu.Unlock()
+		return
+	}
+
+	tc.running = false
+	tc.failedAt = time.Now()
+	tc.snapshot = nil
+	tc.generation++
+	_ = tc.stdin.Close()
+	if tc.cmd.Process != nil {
+		_ = tc.cmd.Process.Kill()
+	}
+	for _, reply := range tc.pending {
+		reply <- tmuxReply{err: reason}
+	}
+	tc.pending = nil
+	tc.mu.Unlock()
+
+	tc.tm.logger.Warn("Tmux control client stopped", "reason", reason)
+	tc.notify(interfaces.SessionEvent{Type: "exit"})
+
+	// Subscribers rely on events rather than queries, so reconnect for them
+	time.AfterFunc(tmuxControlRetry, tc.restartForSubscribers)
+}
+
+// restartForSubscribers restarts a stopped control client while anyone is subscribed
+func (tc *tmuxControl) restartForSubscribers() {
+	tc.mu.Lock()
+	if tc.running || len(tc.subscribers) == 0 {
+		tc.mu.Unlock()
+		return
+	}
+	err := tc.ensureRunning()
+	tc.mu.Unlock()
+
+	if err != nil {
+		tc.tm.logger.Warn("Failed to restart tmux control client", "error", err)
+		time.AfterFunc(tmuxControlRetry, tc.restartForSubscribers)
+		return
+	}
+	tc.notify(interfaces.SessionEvent{Type: "restart"})
+}
+
+// invalidate drops cached state after this process changed it, so reads do
+// not race the notification tmux sends for the change
+func (tc *tmuxControl) invalidate() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.snapshot = nil
+	tc.generation++
+}
+
+// state returns the cached session state, reading it over the control
+// connection when a notification or the TTL invalidated it
+func (tc *tmuxControl) state() (*tmuxSnapshot, error) {
+	tc.mu.Lock()
+	if tc.snapshot != nil && time.Since(tc.snapshot.takenAt) < tmuxSnapshotTTL {
+		snapshot := tc.snapshot
+		tc.mu.Unlock()
+		return snapshot, nil
+	}
+	generation := tc.generation
+	tc.mu.Unlock()
+
+	sessionLines, err := tc.query("list-sessions -F '" + tmuxSessionFormat + "'")
+	if err != nil {
+		return nil, err
+	}
+	// Like list-panes -t, only panes of each session's current window count
+	paneLines, err := tc.query("list-panes -a -F '#{window_active} #{session_name}'")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &tmuxSnapshot{
+		takenAt:  time.Now(),
+		sessions: tc.tm.parseSessions(sessionLines),
+		panes:    make(map[string]int),
+	}
+	for _, line := range paneLines {
+		if sessionName, ok := strings.CutPrefix(line, "1 "); ok && sessionName != "" {
+			snapshot.panes[sessionName]++
+		}
+	}
+
+	// Only cache the result if nothing changed while it was being read
+	tc.mu.Lock()
+	if tc.generation == generation {
+		tc.snapshot = snapshot
+	}
+	tc.mu.Unlock()
+
+	return snapshot, nil
+}
+
+// subscribe registers a subscriber and starts the control client so that
+// events flow without waiting for the first query
+func (tc *tmuxControl) subscribe() (<-chan interfaces.SessionEvent, func()) {
+	ch := make(chan interfaces.SessionEvent, 1)
+
+	tc.mu.Lock()
+	tc.subscribers[ch] = struct{}{}
+	if err := tc.ensureRunning(); err != nil {
+		tc.tm.logger.Warn("Failed to start tmux control client", "error", err)
+	}
+	tc.mu.Unlock()
+
+	// The client is stopped with the last subscription
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			tc.mu.Lock()
+			delete(tc.subscribers, ch)
+			cmd, last := tc.cmd, len(tc.subscribers) == 0 && tc.running
+			tc.mu.Unlock()
+			close(ch)
+
+			if last {
+				tc.stop(cmd, errTmuxControlUnavailable)
+			}
+		})
+	}
+	return ch, cancel
+}
+
+// parseTmuxNotification parses a control-mode notification line such as
+// "%sessions-changed" or "%window-add @3". Pane output is not reported.
+func parseTmuxNotification(line string) (interfaces.SessionEvent, bool) {
+	if !strings.HasPrefix(line, "%") {
+		return interfaces.SessionEvent{}, false
+	}
+
+	name, args, _ := strings.Cut(strings.TrimPrefix(line, "%"), " ")
+	switch name {
+	case "", "output", "extended-output", "begin", "end", "error":
+		return interfaces.SessionEvent{}, false
+	}
+
+	return interfaces.SessionEvent{Type: name, Args: args}, true
+}
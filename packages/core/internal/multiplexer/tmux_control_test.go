@@ -0,0 +1,144 @@
+package multiplexer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestParseTmuxNotification(t *testing.T) {
+	tests := []struct {
+		line  string
+		event interfaces.SessionEvent
+		ok    bool
+	}{
+		{"%sessions-changed", interfaces.SessionEvent{Type: "sessions-changed"}, true},
+		{"%window-add @3", interfaces.SessionEvent{Type: "window-add", Args: "@3"}, true},
+		{"%session-changed $1 claude--api", interfaces.SessionEvent{Type: "session-changed", Args: "$1 claude--api"}, true},
+		{"%output %1 hello", interfaces.SessionEvent{}, false},
+		{"%begin 1792151710 266 1", interfaces.SessionEvent{}, false},
+		{"claude--api,1792151710,0", interfaces.SessionEvent{}, false},
+	}
+
+	for _, tt := range tests {
+		event, ok := parseTmuxNotification(tt.line)
+		if ok != tt.ok || event != tt.event {
+			t.Errorf("parseTmuxNotification(%q) = %+v, %v; want %+v, %v", tt.line, event, ok, tt.event, tt.ok)
+		}
+	}
+}
+
+func TestTmuxControlModeEvents(t *testing.T) {
+	if _, found := lookupBinary("tmux"); !found {
+		t.Skip("tmux is not installed")
+	}
+
+	opts := Options{SessionPrefix: contractPrefix, TmuxSocketName: contractPrefix}
+	plain, err := NewTmuxMultiplexerWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to create tmux multiplexer: %v", err)
+	}
+	opts.TmuxControlMode = true
+	controlled, err := NewTmuxMultiplexerWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to create tmux multiplexer: %v", err)
+	}
+
+	events, stop := controlled.SubscribeSessionEvents()
+	defer stop()
+	if events == nil {
+		t.Fatal("SubscribeSessionEvents returned a nil channel with control mode enabled")
+	}
+
+	// Warm the cache so that the change below must come from a notification
+	if controlled.HasSession("events") {
+		t.Fatal("session exists before it was created")
+	}
+	drain(events)
+
+	// A change made by another process is only visible through tmux's notifications
+	if _, err := plain.CreateSession(interfaces.CreateSessionRequest{Name: "events", Command: "sleep 600"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	defer func() { _ = plain.KillSession("events") }()
+
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received after creating a session")
+	}
+
+	if !controlled.HasSession("events") {
+		t.Error("HasSession = false after a session was created elsewhere")
+	}
+}
+
+func TestTmuxControlModeOnlyForSubscribers(t *testing.T) {
+	if _, found := lookupBinary("tmux"); !found {
+		t.Skip("tmux is not installed")
+	}
+
+	tm, err := NewTmuxMultiplexerWithOptions(Options{
+		SessionPrefix:   contractPrefix,
+		TmuxSocketName:  contractPrefix,
+		TmuxControlMode: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create tmux multiplexer: %v", err)
+	}
+
+	// One-shot queries run tmux directly
+	tm.HasSession("oneshot")
+	if tm.control.running {
+		t.Fatal("control client started without a subscriber")
+	}
+
+	_, stop := tm.SubscribeSessionEvents()
+	if !tm.control.running {
+		t.Fatal("control client not started for a subscriber")
+	}
+
+	// Its session is not one of ours, even where its name matches the prefix
+	sessions, err := tm.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	for _, session := range sessions {
+		if strings.Contains(session.GetName(), "control") {
+			t.Errorf("control session listed as %q", session.GetName())
+		}
+	}
+
+	stop()
+	if tm.control.running {
+		t.Error("control client still running after the last subscription ended")
+	}
+}
+
+func TestTmuxParseSessionsSkipsControlSession(t *testing.T) {
+	// Control sessions of this and of other prefixes sharing the server
+	tm := &TmuxMultiplexer{sessionPrefix: "claude"}
+	lines := []string{
+		"claude-api,1792151710,0,/src/api",
+		tmuxControlSessionPrefix + "claude,1792151710,1,/",
+		tmuxControlSessionPrefix + "claude-pilot,1792151710,1,/",
+	}
+
+	sessions := tm.parseSessions(lines)
+	if len(sessions) != 1 || sessions[0].GetName() != "api" {
+		t.Errorf("expected only session 'api', got %d sessions", len(sessions))
+	}
+}
+
+// drain discards events already queued on ch
+func drain(ch <-chan interfaces.SessionEvent) {
+	for {
+		select {
+		case <-ch:
+		case <-time.After(200 * time.Millisecond):
+			return
+		}
+	}
+}
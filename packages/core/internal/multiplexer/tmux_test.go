@@ -0,0 +1,129 @@
+package multiplexer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestParseTmuxVersion(t *testing.T) {
+	tests := []struct {
+		output       string
+		major, minor int
+		ok           bool
+	}{
+		{"tmux 3.2a\n", 3, 2, true},
+		{"tmux 3.1c", 3, 1, true},
+		{"tmux 2.9", 2, 9, true},
+		{"tmux next-3.5", 3, 5, true},
+		{"tmux master", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		major, minor, ok := parseTmuxVersion(tt.output)
+		if major != tt.major || minor != tt.minor || ok != tt.ok {
+			t.Errorf("parseTmuxVersion(%q) = %d, %d, %v, want %d, %d, %v", tt.output, major, minor, ok, tt.major, tt.minor, tt.ok)
+		}
+	}
+}
+
+func TestTmuxCommandArgs(t *testing.T) {
+	env := map[string]string{"B": "two words", "A": "1"}
+
+	tm := newTestTmux(t, true)
+	if got, want := tm.commandArgs(env, "claude"), []string{"-e", "A=1", "-e", "B=two words", "claude"}; !slices.Equal(got, want) {
+		t.Errorf("with -e: got %q, want %q", got, want)
+	}
+
+	// tmux before 3.2 runs the command through env
+	tm = newTestTmux(t, false)
+	if got, want := tm.commandArgs(env, "echo hi; cat"), []string{`env A=1 'B=two words' sh -c 'echo hi; cat'`}; !slices.Equal(got, want) {
+		t.Errorf("without -e: got %q, want %q", got, want)
+	}
+	if got := tm.commandArgs(env, ""); len(got) != 0 {
+		t.Errorf("without -e and command: got %q, want nothing", got)
+	}
+}
+
+// newTestTmux returns a tmux multiplexer that takes tmux to support -e or not
+func newTestTmux(t *testing.T, envFlag bool) *TmuxMultiplexer {
+	t.Helper()
+
+	tm, err := NewTmuxMultiplexer(contractPrefix)
+	if err != nil {
+		t.Fatalf("failed to create tmux multiplexer: %v", err)
+	}
+	tm.envFlagOnce.Do(func() { tm.envFlag = envFlag })
+	return tm
+}
+
+func TestTmuxLayout(t *testing.T) {
+	if _, found := lookupBinary("tmux"); !found {
+		t.Skip("tmux is not installed")
+	}
+
+	tm, err := NewTmuxMultiplexerWithOptions(Options{
+		SessionPrefix:  contractPrefix,
+		TmuxSocketName: contractPrefix,
+		TmuxLayout:     "even-vertical",
+	})
+	if err != nil {
+		t.Fatalf("failed to create tmux multiplexer: %v", err)
+	}
+	create := func(name string) {
+		t.Helper()
+		if _, err := tm.CreateSession(interfaces.CreateSessionRequest{Name: name, Command: "cat"}); err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		t.Cleanup(func() { _ = tm.KillSession(name) })
+	}
+	// stacked reports whether the panes of a session are laid out one
+	// above the other, as even-vertical does
+	stacked := func(name string) bool {
+		t.Helper()
+		output, err := tm.command("list-panes", "-t", contractPrefix+"-"+name, "-F", "#{pane_left}").Output()
+		if err != nil {
+			t.Fatalf("failed to list panes: %v", err)
+		}
+		lefts := strings.Fields(string(output))
+		if len(lefts) != 2 {
+			t.Fatalf("session %s has panes at %q, want 2", name, lefts)
+		}
+		return lefts[0] == "0" && lefts[1] == "0"
+	}
+
+	// Panes without a split of their own are laid out
+	create("layout-plain")
+	if err := tm.SplitPanes("layout-plain", []interfaces.BlueprintPane{{Command: "cat"}}, nil); err != nil {
+		t.Fatalf("SplitPanes failed: %v", err)
+	}
+	if !stacked("layout-plain") {
+		t.Error("blueprint panes without a split are not laid out")
+	}
+
+	// A blueprint split wins over the layout
+	create("layout-split")
+	if err := tm.SplitPanes("layout-split", []interfaces.BlueprintPane{{Command: "cat", SplitDirection: interfaces.SplitVertical}}, nil); err != nil {
+		t.Fatalf("SplitPanes failed: %v", err)
+	}
+	if stacked("layout-split") {
+		t.Error("layout replaced the blueprint's split")
+	}
+
+	// So are panes attached later
+	create("layout-attached")
+	if _, err := tm.CreateSession(interfaces.CreateSessionRequest{
+		Name:           "layout-pane",
+		Command:        "cat",
+		AttachTo:       "layout-attached",
+		AttachmentType: interfaces.AttachmentPane,
+		SplitDirection: interfaces.SplitVertical,
+	}); err != nil {
+		t.Fatalf("failed to attach pane: %v", err)
+	}
+	if !stacked("layout-attached") {
+		t.Error("attached pane is not laid out")
+	}
+}
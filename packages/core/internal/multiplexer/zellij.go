@@ -0,0 +1,664 @@
+package multiplexer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/shared/interfaces"
+)
+
+// zellijCreatedPattern matches the duration tokens in "[Created 1h 2m 3s ago]"
+var zellijCreatedPattern = regexp.MustCompile(`(\d+)\s*(days?|h|m|s)`)
+
+// ZellijMultiplexer implements the TerminalMultiplexer interface for zellij
+type ZellijMultiplexer struct {
+	sessionPrefix string
+	zellijPath    string
+	logger        *logger.Logger
+}
+
+// ZellijSession implements the MultiplexerSession interface
+type ZellijSession struct {
+	id          string
+	name        string
+	zellijName  string
+	status      interfaces.SessionStatus
+	createdAt   time.Time
+	workingDir  string
+	description string
+	isAttached  bool
+	isRunning   bool
+}
+
+// GetID returns the session ID
+func (s *ZellijSession) GetID() string {
+	return s.id
+}
+
+// GetName returns the session name
+func (s *ZellijSession) GetName() string {
+	return s.name
+}
+
+// GetStatus returns the session status
+func (s *ZellijSession) GetStatus() interfaces.SessionStatus {
+	return s.status
+}
+
+// GetCreatedAt returns the creation time
+func (s *ZellijSession) GetCreatedAt() time.Time {
+	return s.createdAt
+}
+
+// GetWorkingDir returns the working directory
+func (s *ZellijSession) GetWorkingDir() string {
+	return s.workingDir
+}
+
+// GetDescription returns the session description
+func (s *ZellijSession) GetDescription() string {
+	return s.description
+}
+
+// IsAttached returns whether someone is attached to the session
+func (s *ZellijSession) IsAttached() bool {
+	return s.isAttached
+}
+
+// IsRunning returns whether the session is running
+func (s *ZellijSession) IsRunning() bool {
+	return s.isRunning
+}
+
+// NewZellijMultiplexer creates a new zellij multiplexer instance
+func NewZellijMultiplexer(sessionPrefix string) (*ZellijMultiplexer, error) {
+	// Create a disabled logger by default for backward compatibility
+	disabledLogger, _ := logger.Setup.Disabled().Build()
+	return NewZellijMultiplexerWithLogger(sessionPrefix, disabledLogger)
+}
+
+// NewZellijMultiplexerWithLogger creates a new zellij multiplexer instance with logger
+func NewZellijMultiplexerWithLogger(sessionPrefix string, log *logger.Logger) (*ZellijMultiplexer, error) {
+	return NewZellijMultiplexerWithOptions(Options{SessionPrefix: sessionPrefix, Logger: log})
+}
+
+// NewZellijMultiplexerWithOptions creates a new zellij multiplexer instance from options
+func NewZellijMultiplexerWithOptions(opts Options) (*ZellijMultiplexer, error) {
+	sessionPrefix := opts.sessionPrefix()
+	log := opts.logger()
+
+	log.Debug("Initializing zellij multiplexer", "session_prefix", sessionPrefix)
+
+	zellijPath, found, err := resolveBinary("zellij", opts.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		log.Debug("Found zellij binary", "path", zellijPath)
+	}
+
+	zm := &ZellijMultiplexer{
+		sessionPrefix: sessionPrefix,
+		zellijPath:    zellijPath,
+		logger:        log,
+	}
+
+	log.Info("Zellij multiplexer initialized",
+		"session_prefix", sessionPrefix,
+		"zellij_path", zellijPath)
+
+	return zm, nil
+}
+
+// GetName returns the name of the multiplexer backend
+func (zm *ZellijMultiplexer) GetName() string {
+	return "zellij"
+}
+
+// IsAvailable checks if zellij is available on the system
+func (zm *ZellijMultiplexer) IsAvailable() bool {
+	_, err := exec.LookPath(zm.zellijPath)
+	return err == nil
+}
+
+// CreateSession creates a new zellij session, or attaches to an existing session as pane/tab
+func (zm *ZellijMultiplexer) CreateSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	start := time.Now()
+
+	// Handle attachment to existing session
+	if req.AttachTo != "" && req.AttachmentType != interfaces.AttachmentNone {
+		return zm.createAttachedSession(req)
+	}
+
+	return zm.createStandaloneSession(req, start)
+}
+
+// createStandaloneSession creates a new detached zellij session running the requested command
+func (zm *ZellijMultiplexer) createStandaloneSession(req interfaces.CreateSessionRequest, start time.Time) (interfaces.MultiplexerSession, error) {
+	zellijName := zm.sessionName(req.Name)
+
+	zm.logger.Debug("Creating zellij session",
+		"name", req.Name,
+		"zellij_name", zellijName,
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if zm.HasSession(req.Name) {
+		return nil, fmt.Errorf("zellij session '%s' already exists", req.Name)
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+
+	// Zellij has no "new-session -d <command>" equivalent, so the command is
+	// described in a layout file that the background session is started with
+	layoutFile, err := writeZellijLayout(zellijSessionLayout(command, req.WorkingDir))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(layoutFile)
+
+	// Remove any exited (resurrectable) session with the same name first,
+	// otherwise zellij would resurrect it instead of using our layout
+	_ = exec.Command(zm.zellijPath, "delete-session", zellijName).Run()
+
+	cmd := exec.Command(zm.zellijPath, "--layout", layoutFile, "attach", "--create-background", zellijName)
+	cmd.Env = append(os.Environ(), envAssignments(req.Env)...)
+	if req.WorkingDir != "" {
+		cmd.Dir = req.WorkingDir
+	}
+
+	zm.logger.DebugCommand(zm.zellijPath, cmd.Args[1:], req.WorkingDir)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		zm.logger.Error("Failed to create zellij session",
+			"name", req.Name,
+			"zellij_name", zellijName,
+			"command", strings.Join(cmd.Args, " "),
+			"output", strings.TrimSpace(string(output)),
+			"error", err)
+		return nil, fmt.Errorf("failed to create zellij session: %w", err)
+	}
+
+	session := &ZellijSession{
+		id:          zellijName,
+		name:        req.Name,
+		zellijName:  zellijName,
+		status:      interfaces.StatusActive,
+		createdAt:   time.Now(),
+		workingDir:  req.WorkingDir,
+		description: req.Description,
+		isAttached:  false,
+		isRunning:   true,
+	}
+
+	zm.logger.Performance("CreateSession", start,
+		slog.String("name", req.Name),
+		slog.String("zellij_name", zellijName))
+
+	zm.logger.Info("Zellij session created successfully",
+		"name", req.Name,
+		"zellij_name", zellijName,
+		"command", command)
+
+	return session, nil
+}
+
+// createAttachedSession creates a new pane or tab in an existing zellij session
+func (zm *ZellijMultiplexer) createAttachedSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	targetZellijName := zm.sessionName(req.AttachTo)
+
+	zm.logger.Debug("Creating attached session",
+		"name", req.Name,
+		"attach_to", req.AttachTo,
+		"target_zellij_name", targetZellijName,
+		"attachment_type", req.AttachmentType,
+		"split_direction", req.SplitDirection,
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if !zm.HasSession(req.AttachTo) {
+		return nil, fmt.Errorf("target session '%s' does not exist", req.AttachTo)
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+
+	var cmd *exec.Cmd
+
+	switch req.AttachmentType {
+	case interfaces.AttachmentPane:
+		cmd = zm.buildNewPaneCommand(targetZellijName, req.WorkingDir, req.Name, req.SplitDirection, command)
+	case interfaces.AttachmentWindow:
+		layoutFile, err := writeZellijLayout(zellijTabLayout(command, req.WorkingDir))
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(layoutFile)
+		cmd = zm.buildNewTabCommand(targetZellijName, req.WorkingDir, req.Name, layoutFile)
+	default:
+		return nil, fmt.Errorf("unsupported attachment type: %s", req.AttachmentType)
+	}
+
+	zm.logger.DebugCommand(zm.zellijPath, cmd.Args[1:], req.WorkingDir)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		zm.logger.Error("Failed to create attached session",
+			"name", req.Name,
+			"attach_to", req.AttachTo,
+			"attachment_type", req.AttachmentType,
+			"command", strings.Join(cmd.Args, " "),
+			"output", strings.TrimSpace(string(output)),
+			"error", err)
+		return nil, fmt.Errorf("failed to create attached session: %w", err)
+	}
+
+	// Create a virtual session object representing the pane or tab
+	attachedSession := &ZellijSession{
+		id:          fmt.Sprintf("%s-%s-attached", targetZellijName, req.Name),
+		name:        fmt.Sprintf("%s-attached-to-%s", req.Name, req.AttachTo),
+		zellijName:  targetZellijName, // Points to the parent session
+		status:      interfaces.StatusActive,
+		createdAt:   time.Now(),
+		workingDir:  req.WorkingDir,
+		description: fmt.Sprintf("%s (attached as %s to %s)", req.Description, req.AttachmentType, req.AttachTo),
+		isAttached:  false,
+		isRunning:   true,
+	}
+
+	zm.logger.Info("Attached session created successfully",
+		"name", req.Name,
+		"attach_to", req.AttachTo,
+		"attachment_type", req.AttachmentType,
+		"command", command)
+
+	return attachedSession, nil
+}
+
+// buildNewPaneCommand builds the zellij command for running a command in a new pane
+func (zm *ZellijMultiplexer) buildNewPaneCommand(targetSession, workingDir, paneName string, splitDir interfaces.SplitDirection, command string) *exec.Cmd {
+	args := []string{"--session", targetSession, "run"}
+
+	// Match the tmux backend: the default split places panes side by side
+	if splitDir == interfaces.SplitHorizontal {
+		args = append(args, "--direction", "down")
+	} else {
+		args = append(args, "--direction", "right")
+	}
+
+	if workingDir != "" {
+		args = append(args, "--cwd", workingDir)
+	}
+
+	if paneName != "" {
+		args = append(args, "--name", paneName)
+	}
+
+	args = append(args, "--", "sh", "-c", command)
+
+	return exec.Command(zm.zellijPath, args...)
+}
+
+// buildNewTabCommand builds the zellij command for opening a new tab from a layout
+func (zm *ZellijMultiplexer) buildNewTabCommand(targetSession, workingDir, tabName, layoutFile string) *exec.Cmd {
+	args := []string{"--session", targetSession, "action", "new-tab", "--layout", layoutFile}
+
+	if tabName != "" {
+		args = append(args, "--name", tabName)
+	}
+
+	if workingDir != "" {
+		args = append(args, "--cwd", workingDir)
+	}
+
+	return exec.Command(zm.zellijPath, args...)
+}
+
+// GetSession retrieves session information by name
+func (zm *ZellijMultiplexer) GetSession(name string) (interfaces.MultiplexerSession, error) {
+	sessions, err := zm.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.GetName() == name {
+			return session, nil
+		}
+	}
+
+	return nil, fmt.Errorf("zellij session '%s' not found", name)
+}
+
+// ListSessions returns all running zellij sessions owned by claude-pilot
+func (zm *ZellijMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, error) {
+	cmd := exec.Command(zm.zellijPath, "list-sessions", "--no-formatting")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// Zellij exits non-zero when there are no sessions at all
+		if strings.Contains(string(output), "No active zellij sessions") {
+			return []interfaces.MultiplexerSession{}, nil
+		}
+		return nil, fmt.Errorf("failed to list zellij sessions: %w", err)
+	}
+
+	parsed := zm.parseSessionList(string(output), time.Now())
+	sessions := make([]interfaces.MultiplexerSession, 0, len(parsed))
+	for _, session := range parsed {
+		session.isAttached = zm.hasClients(session.zellijName)
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// hasClients reports whether any client is attached to a zellij session.
+// "(current)" in the session list only marks the session the caller runs
+// in, so the clients are listed for each session. Versions of zellij
+// without list-clients report every session as detached.
+func (zm *ZellijMultiplexer) hasClients(zellijName string) bool {
+	output, err := exec.Command(zm.zellijPath, "--session", zellijName, "action", "list-clients").Output()
+	if err != nil {
+		return false
+	}
+	return countZellijClients(string(output)) > 0
+}
+
+// countZellijClients counts the clients in `zellij action list-clients`
+// output, a header line followed by one line per client, e.g.
+// "1          terminal_1   claude"
+func countZellijClients(output string) int {
+	count := 0
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "CLIENT_ID" {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// parseSessionList parses `zellij list-sessions --no-formatting` output, e.g.
+// "claude--api [Created 1h 2m 3s ago] (current)". Whether clients are
+// attached is not part of it.
+func (zm *ZellijMultiplexer) parseSessionList(output string, now time.Time) []*ZellijSession {
+	var sessions []*ZellijSession
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sessionName, rest, _ := strings.Cut(line, " ")
+
+		// Only include our claude-pilot sessions
+		if !strings.HasPrefix(sessionName, zm.sessionPrefix+"-") {
+			continue
+		}
+
+		// Exited sessions can be resurrected but are not running
+		if strings.Contains(rest, "EXITED") {
+			continue
+		}
+
+		createdAt := now
+		if open := strings.Index(rest, "[Created"); open >= 0 {
+			if closing := strings.Index(rest[open:], "]"); closing > 0 {
+				createdAt = now.Add(-parseZellijAge(rest[open : open+closing]))
+			}
+		}
+
+		sessions = append(sessions, &ZellijSession{
+			id:         sessionName,
+			name:       strings.TrimPrefix(sessionName, zm.sessionPrefix+"-"),
+			zellijName: sessionName,
+			status:     interfaces.StatusActive,
+			createdAt:  createdAt,
+			isRunning:  true,
+		})
+	}
+
+	return sessions
+}
+
+// parseZellijAge converts "Created 1h 2m 3s ago" into a duration
+func parseZellijAge(text string) time.Duration {
+	var age time.Duration
+	for _, match := range zellijCreatedPattern.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		switch match[2] {
+		case "day", "days":
+			age += time.Duration(value) * 24 * time.Hour
+		case "h":
+			age += time.Duration(value) * time.Hour
+		case "m":
+			age += time.Duration(value) * time.Minute
+		case "s":
+			age += time.Duration(value) * time.Second
+		}
+	}
+	return age
+}
+
+// AttachToSession attaches to an existing zellij session
+func (zm *ZellijMultiplexer) AttachToSession(name string) error {
+	if !zm.HasSession(name) {
+		return fmt.Errorf("zellij session '%s' not found", name)
+	}
+
+	cmd := exec.Command(zm.zellijPath, "attach", zm.sessionName(name))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// KillSession terminates a zellij session
+func (zm *ZellijMultiplexer) KillSession(name string) error {
+	if !zm.HasSession(name) {
+		return fmt.Errorf("zellij session '%s' not found", name)
+	}
+
+	zellijName := zm.sessionName(name)
+	cmd := exec.Command(zm.zellijPath, "kill-session", zellijName)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to kill zellij session: %w", err)
+	}
+
+	// Drop the resurrectable copy so the name can be reused
+	_ = exec.Command(zm.zellijPath, "delete-session", zellijName).Run()
+
+	return nil
+}
+
+// IsSessionRunning checks if a session is currently running
+func (zm *ZellijMultiplexer) IsSessionRunning(name string) bool {
+	session, err := zm.GetSession(name)
+	if err != nil {
+		return false
+	}
+	return session.IsRunning()
+}
+
+// HasSession checks if a session exists
+func (zm *ZellijMultiplexer) HasSession(name string) bool {
+	_, err := zm.GetSession(name)
+	return err == nil
+}
+
+// GetSessionPaneCount returns the number of terminal panes in a zellij session
+func (zm *ZellijMultiplexer) GetSessionPaneCount(name string) (int, error) {
+	if !zm.HasSession(name) {
+		return 0, fmt.Errorf("session '%s' not found", name)
+	}
+
+	zellijName := zm.sessionName(name)
+	cmd := exec.Command(zm.zellijPath, "--session", zellijName, "action", "dump-layout")
+	output, err := cmd.Output()
+	if err != nil {
+		zm.logger.Error("Failed to get pane count for session",
+			"name", name,
+			"zellij_name", zellijName,
+			"error", err)
+		return 0, fmt.Errorf("failed to get pane count: %w", err)
+	}
+
+	paneCount := countZellijPanes(string(output))
+	zm.logger.Debug("Retrieved pane count for session",
+		"name", name,
+		"zellij_name", zellijName,
+		"pane_count", paneCount)
+
+	return paneCount, nil
+}
+
+// sessionName returns the zellij session name for a claude-pilot session
+func (zm *ZellijMultiplexer) sessionName(name string) string {
+	return fmt.Sprintf("%s-%s", zm.sessionPrefix, name)
+}
+
+// countZellijPanes counts the terminal panes inside the tabs of a dumped layout.
+// Split containers, plugin panes (tab/status bars) and the tab templates that
+// zellij appends to the dump are not counted.
+func countZellijPanes(layout string) int {
+	lines := strings.Split(layout, "\n")
+	count := 0
+	depth := 0
+	tabDepth := -1 // brace depth at which the current tab block was opened
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		opens := strings.HasSuffix(line, "{")
+
+		switch {
+		case tabDepth < 0 && strings.HasPrefix(line, "tab") && opens:
+			tabDepth = depth
+		case tabDepth >= 0 && strings.HasPrefix(line, "pane") && !strings.Contains(line, "split_direction"):
+			if !opens || !nextLineIsPlugin(lines[i+1:]) {
+				count++
+			}
+		}
+
+		if opens {
+			depth++
+		}
+		if line == "}" {
+			depth--
+			if depth == tabDepth {
+				tabDepth = -1
+			}
+		}
+	}
+
+	return count
+}
+
+// nextLineIsPlugin reports whether the first non-empty line is a plugin declaration
+func nextLineIsPlugin(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		return strings.HasPrefix(line, "plugin")
+	}
+	return false
+}
+
+// zellijSessionLayout returns a KDL layout with the standard tab and status
+// bars around a single pane running command
+func zellijSessionLayout(command, workingDir string) string {
+	return fmt.Sprintf(`layout {
+    pane size=1 borderless=true {
+        plugin location="zellij:tab-bar"
+    }
+    %s
+    pane size=2 borderless=true {
+        plugin location="zellij:status-bar"
+    }
+}
+`, zellijCommandPane(command, workingDir))
+}
+
+// zellijTabLayout returns a KDL layout for a new tab running command
+func zellijTabLayout(command, workingDir string) string {
+	return fmt.Sprintf("layout {\n    %s\n}\n", zellijCommandPane(command, workingDir))
+}
+
+// zellijCommandPane returns a KDL pane node running command through sh
+func zellijCommandPane(command, workingDir string) string {
+	pane := `pane command="sh"`
+	if workingDir != "" {
+		pane += fmt.Sprintf(" cwd=%s", kdlQuote(workingDir))
+	}
+	return fmt.Sprintf("%s {\n        args \"-c\" %s\n    }", pane, kdlQuote(command))
+}
+
+// kdlQuote quotes a string for use in a KDL document. KDL has its own
+// escapes, other characters are written as they are, except for control
+// characters which are written as \u{...}.
+func kdlQuote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// writeZellijLayout writes a layout to a temporary file and returns its path
+func writeZellijLayout(layout string) (string, error) {
+	file, err := os.CreateTemp("", "claude-pilot-*.kdl")
+	if err != nil {
+		return "", fmt.Errorf("failed to create zellij layout file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(layout); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write zellij layout file: %w", err)
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,101 @@
+package multiplexer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZellijParseSessionList(t *testing.T) {
+	zm := &ZellijMultiplexer{sessionPrefix: "claude-"}
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	output := `claude--api [Created 1h 2m 3s ago] (current)
+claude--web [Created 2days 4h ago]
+claude--old [Created 5m ago] (EXITED - attach to resurrect)
+personal [Created 10s ago]
+`
+
+	sessions := zm.parseSessionList(output, now)
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	api := sessions[0]
+	if api.GetName() != "api" {
+		t.Errorf("expected name 'api', got '%s'", api.GetName())
+	}
+	if api.IsAttached() {
+		t.Error("expected the current session not to be reported as attached without clients")
+	}
+	if want := now.Add(-(time.Hour + 2*time.Minute + 3*time.Second)); !api.GetCreatedAt().Equal(want) {
+		t.Errorf("expected created at %v, got %v", want, api.GetCreatedAt())
+	}
+
+	web := sessions[1]
+	if want := now.Add(-(52 * time.Hour)); !web.GetCreatedAt().Equal(want) {
+		t.Errorf("expected created at %v, got %v", want, web.GetCreatedAt())
+	}
+}
+
+func TestCountZellijClients(t *testing.T) {
+	output := `CLIENT_ID ZELLIJ_PANE_ID RUNNING_COMMAND
+1         terminal_1     claude
+3         plugin_2       zellij:session-manager
+`
+	if count := countZellijClients(output); count != 2 {
+		t.Errorf("expected 2 clients, got %d", count)
+	}
+	if count := countZellijClients("CLIENT_ID ZELLIJ_PANE_ID RUNNING_COMMAND\n"); count != 0 {
+		t.Errorf("expected no clients, got %d", count)
+	}
+}
+
+func TestKdlQuote(t *testing.T) {
+	tests := map[string]string{
+		`cd "my dir" && claude`: `"cd \"my dir\" && claude"`,
+		`C:\path`:               `"C:\\path"`,
+		"line\nnext\ttab":       `"line\nnext\ttab"`,
+		"caf\u00e9 ✓":           `"café ✓"`,
+		"bell\a nul\x00":        `"bell\u{7} nul\u{0}"`,
+	}
+	for input, want := range tests {
+		if got := kdlQuote(input); got != want {
+			t.Errorf("kdlQuote(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestCountZellijPanes(t *testing.T) {
+	layout := `layout {
+    cwd "/home/user"
+    tab name="Tab #1" focus=true {
+        pane size=1 borderless=true {
+            plugin location="zellij:tab-bar"
+        }
+        pane split_direction="vertical" {
+            pane command="sh" cwd="project" {
+                args "-c" "claude"
+                start_suspended true
+            }
+            pane command="sh" focus=true
+        }
+        pane size=2 borderless=true {
+            plugin location="zellij:status-bar"
+        }
+    }
+    tab name="logs" {
+        pane
+    }
+    new_tab_template {
+        pane size=1 borderless=true {
+            plugin location="zellij:tab-bar"
+        }
+        pane
+    }
+}
+`
+
+	if count := countZellijPanes(layout); count != 3 {
+		t.Errorf("expected 3 panes, got %d", count)
+	}
+}
@@ -0,0 +1,134 @@
+// Package notify tells users when the Claude agent in one of their sessions
+// needs them, e.g. because it waits for input or for a tool permission.
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"claude-pilot/core/internal/logger"
+	"claude-pilot/shared/interfaces"
+)
+
+// Notification reports that a session entered a state worth notifying about
+type Notification struct {
+	Session *interfaces.Session
+	State   interfaces.AgentState
+	Time    time.Time
+}
+
+// Message returns a one-line description of the notification
+func (n Notification) Message() string {
+	switch n.State {
+	case interfaces.AgentWaitingInput:
+		return fmt.Sprintf("claude-pilot: %s is waiting for input", n.Session.Name)
+	case interfaces.AgentWaitingPermission:
+		return fmt.Sprintf("claude-pilot: %s is asking for permission", n.Session.Name)
+	case interfaces.AgentErrored:
+		return fmt.Sprintf("claude-pilot: %s stopped on an error", n.Session.Name)
+	default:
+		return fmt.Sprintf("claude-pilot: %s is %s", n.Session.Name, n.State)
+	}
+}
+
+// Sink delivers notifications
+type Sink interface {
+	Notify(n Notification) error
+}
+
+// Options configures a Notifier
+type Options struct {
+	// Debounce is how long a session must stay in a state before it notifies
+	Debounce time.Duration
+
+	// States are the agent states that notify
+	States []interfaces.AgentState
+
+	// Sinks receive every notification
+	Sinks []Sink
+
+	Logger *logger.Logger
+}
+
+// tracked is what a Notifier remembers about a session between observations
+type tracked struct {
+	state    interfaces.AgentState
+	since    time.Time
+	notified bool
+}
+
+// Notifier turns observed session states into notifications. A session
+// notifies once per state change, after staying in the new state for the
+// debounce period, unless it is muted.
+type Notifier struct {
+	opts     Options
+	logger   *logger.Logger
+	sessions map[string]*tracked // by session ID
+}
+
+// NewNotifier creates a notifier
+func NewNotifier(opts Options) *Notifier {
+	log := opts.Logger
+	if log == nil {
+		log, _ = logger.Setup.Disabled().Build()
+	}
+
+	return &Notifier{
+		opts:     opts,
+		logger:   log,
+		sessions: make(map[string]*tracked),
+	}
+}
+
+// Observe records the current state of sessions at now, delivers the
+// notifications that are due to every sink, and returns them
+func (n *Notifier) Observe(sessions []*interfaces.Session, now time.Time) []Notification {
+	var notifications []Notification
+	seen := make(map[string]bool, len(sessions))
+
+	for _, session := range sessions {
+		seen[session.ID] = true
+
+		t, ok := n.sessions[session.ID]
+		if !ok || t.state != session.AgentState {
+			t = &tracked{state: session.AgentState, since: now}
+			n.sessions[session.ID] = t
+		}
+
+		if t.notified || session.Muted || !slices.Contains(n.opts.States, t.state) ||
+			now.Sub(t.since) < n.opts.Debounce {
+			continue
+		}
+
+		t.notified = true
+		notification := Notification{Session: session, State: t.state, Time: now}
+		n.deliver(notification)
+		notifications = append(notifications, notification)
+	}
+
+	// Forget deleted sessions
+	for id := range n.sessions {
+		if !seen[id] {
+			delete(n.sessions, id)
+		}
+	}
+
+	return notifications
+}
+
+// deliver sends a notification to every sink. A failing sink does not keep
+// the others from being notified.
+func (n *Notifier) deliver(notification Notification) {
+	sessionLogger := n.logger.WithSession(notification.Session.ID, notification.Session.Name)
+
+	for _, sink := range n.opts.Sinks {
+		if err := sink.Notify(notification); err != nil {
+			sessionLogger.Warn("Failed to deliver notification",
+				"sink", fmt.Sprintf("%T", sink),
+				"error", err)
+		}
+	}
+
+	sessionLogger.Info("Notification sent", "agent_state", notification.State)
+}
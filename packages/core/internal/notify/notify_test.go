@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// recordingSink remembers the notifications it received
+type recordingSink struct {
+	notifications []Notification
+}
+
+func (s *recordingSink) Notify(n Notification) error {
+	s.notifications = append(s.notifications, n)
+	return nil
+}
+
+func TestNotifier(t *testing.T) {
+	sink := &recordingSink{}
+	notifier := NewNotifier(Options{
+		Debounce: 5 * time.Second,
+		States:   []interfaces.AgentState{interfaces.AgentWaitingInput, interfaces.AgentWaitingPermission},
+		Sinks:    []Sink{sink},
+	})
+
+	api := &interfaces.Session{ID: "1", Name: "api", AgentState: interfaces.AgentWorking}
+	web := &interfaces.Session{ID: "2", Name: "web", AgentState: interfaces.AgentWaitingInput, Muted: true}
+	start := time.Now()
+	observe := func(after time.Duration) []Notification {
+		t.Helper()
+		return notifier.Observe([]*interfaces.Session{api, web}, start.Add(after))
+	}
+
+	observe(0)
+	api.AgentState = interfaces.AgentWaitingPermission
+	if got := observe(time.Second); len(got) != 0 {
+		t.Fatalf("notified before the debounce period: %+v", got)
+	}
+
+	// Briefly working again restarts the debounce period
+	api.AgentState = interfaces.AgentWorking
+	observe(2 * time.Second)
+	api.AgentState = interfaces.AgentWaitingPermission
+	if got := observe(6 * time.Second); len(got) != 0 {
+		t.Fatalf("notified although the state changed within the debounce period: %+v", got)
+	}
+
+	got := observe(12 * time.Second)
+	if len(got) != 1 || got[0].Session.Name != "api" || got[0].State != interfaces.AgentWaitingPermission {
+		t.Fatalf("expected a permission notification for api, got %+v", got)
+	}
+	if len(sink.notifications) != 1 {
+		t.Errorf("sink received %d notifications, want 1", len(sink.notifications))
+	}
+
+	// A state notifies once, and muted sessions never do
+	if got := observe(time.Minute); len(got) != 0 {
+		t.Errorf("notified again for the same state: %+v", got)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	sink := NewFileSink(path)
+
+	session := &interfaces.Session{ID: "1", Name: "api"}
+	for _, state := range []interfaces.AgentState{interfaces.AgentWaitingInput, interfaces.AgentErrored} {
+		if err := sink.Notify(Notification{Session: session, State: state, Time: time.Now()}); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "api stopped on an error") {
+		t.Errorf("unexpected notification file:\n%s", data)
+	}
+}
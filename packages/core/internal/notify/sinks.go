@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// commandTimeout bounds how long a notification command may run
+const commandTimeout = 30 * time.Second
+
+// MessageSink shows notifications to the clients attached to the multiplexer
+type MessageSink struct {
+	messenger interfaces.ClientMessenger
+}
+
+// NewMessageSink creates a sink that displays notifications through messenger
+func NewMessageSink(messenger interfaces.ClientMessenger) *MessageSink {
+	return &MessageSink{messenger: messenger}
+}
+
+// Notify displays the notification message
+func (s *MessageSink) Notify(n Notification) error {
+	return s.messenger.DisplayMessage(n.Message())
+}
+
+// BellSink rings the terminal bell
+type BellSink struct {
+	w io.Writer
+}
+
+// NewBellSink creates a sink that writes the bell character to w
+func NewBellSink(w io.Writer) *BellSink {
+	return &BellSink{w: w}
+}
+
+// Notify rings the bell
+func (s *BellSink) Notify(Notification) error {
+	_, err := io.WriteString(s.w, "\a")
+	return err
+}
+
+// CommandSink runs a shell command for every notification, with the session
+// details in CLAUDE_PILOT_* environment variables
+type CommandSink struct {
+	command string
+}
+
+// NewCommandSink creates a sink that runs command with sh -c
+func NewCommandSink(command string) *CommandSink {
+	return &CommandSink{command: command}
+}
+
+// Notify runs the command and waits for it to finish
+func (s *CommandSink) Notify(n Notification) error {
+	cmd := exec.Command("sh", "-c", s.command)
+	cmd.Env = append(os.Environ(),
+		"CLAUDE_PILOT_SESSION_ID="+n.Session.ID,
+		"CLAUDE_PILOT_SESSION_NAME="+n.Session.Name,
+		"CLAUDE_PILOT_PROJECT_PATH="+n.Session.ProjectPath,
+		"CLAUDE_PILOT_AGENT_STATE="+string(n.State),
+		"CLAUDE_PILOT_MESSAGE="+n.Message(),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start notification command: %w", err)
+	}
+
+	// A hanging command must not stall notifications for other sessions
+	timer := time.AfterFunc(commandTimeout, func() { _ = cmd.Process.Kill() })
+	defer timer.Stop()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("notification command failed: %w", err)
+	}
+	return nil
+}
+
+// FileSink appends notifications to a file or FIFO, one line each
+type FileSink struct {
+	path string
+}
+
+// NewFileSink creates a sink that appends to path, creating it if needed
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Notify appends a line with the time, session, state and message
+func (s *FileSink) Notify(n Notification) error {
+	// Without O_NONBLOCK, opening a FIFO that nobody reads would block forever
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|syscall.O_NONBLOCK, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer file.Close()
+
+	line := fmt.Sprintf("%s\t%s\t%s\t%s\n", n.Time.Format(time.RFC3339), n.Session.Name, n.State, n.Message())
+	if _, err := file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
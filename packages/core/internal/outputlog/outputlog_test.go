@@ -0,0 +1,116 @@
+package outputlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterRotatesAndStrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	// Entries take 22 bytes besides their text, so only a few fit in a log
+	w := NewWriter(path, Options{MaxSize: 80, MaxFiles: 2, StripANSI: true})
+	tick := 0
+	w.now = func() time.Time {
+		tick++
+		return start.Add(time.Duration(tick) * time.Minute)
+	}
+
+	output := "\x1b[1mline 1\x1b[0m\r\n" +
+		"\x1b[2K\x1b[1A\n" + // Cursor movement only, skipped
+		"line 2\n" +
+		"50%\r100%\n" +
+		"line 4\n\nline 6\nline 7\n" +
+		"line 8 \x07without newline"
+	if err := w.Copy(strings.NewReader(output)); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	w.Close()
+
+	if got := Files(path); len(got) != 3 || got[0] != path+".2" || got[2] != path {
+		t.Fatalf("Files = %v, want %s.2, %s.1 and %s", got, path, path, path)
+	}
+
+	lines, err := Read(path, time.Time{})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	// The first log was dropped when the log rotated the third time
+	want := "100%|line 4||line 6|line 7|line 8 without newline"
+	if got := strings.Join(texts, "|"); got != want {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	since, err := Read(path, start.Add(7*time.Minute))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(since) != 2 || since[0].Text != "line 7" {
+		t.Errorf("lines since 10:07 = %+v, want line 7 and line 8", since)
+	}
+
+	archive := filepath.Join(filepath.Dir(path), "archive")
+	if err := Archive(path, archive); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if len(Files(path)) != 0 || len(Files(filepath.Join(archive, "session.log"))) != 3 {
+		t.Errorf("logs were not moved to the archive")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	w := NewWriter(path, Options{MaxSize: 70, MaxFiles: 1})
+	defer w.Close()
+	if err := w.WriteLine("before"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lines := make(chan string, 10)
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, time.Time{}, func(line Line) { lines <- line.Text })
+	}()
+
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Errorf("followed %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+
+	expect("before")
+	// The second line rotates the log
+	for _, text := range []string{"after", "rotated"} {
+		if err := w.WriteLine(text); err != nil {
+			t.Fatal(err)
+		}
+		expect(text)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("log was not rotated: %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow returned %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package outputlog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// followInterval is how often Follow checks the log for new lines
+const followInterval = 500 * time.Millisecond
+
+// Line is a line of a log
+type Line struct {
+	Time time.Time // Zero for lines without a readable timestamp
+	Text string
+}
+
+// Files returns the files of the log at path that exist, the rotated ones
+// first, oldest to newest
+func Files(path string) []string {
+	var files []string
+	for n := 1; ; n++ {
+		if _, err := os.Stat(rotatedPath(path, n)); err != nil {
+			break
+		}
+		files = append(files, rotatedPath(path, n))
+	}
+	slices.Reverse(files)
+
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
+
+// Read returns the lines of a log, including its rotated files, written at
+// or after since
+func Read(path string, since time.Time) ([]Line, error) {
+	var lines []Line
+	collect := func(line Line) { lines = append(lines, line) }
+
+	for _, file := range Files(path) {
+		if err := readFile(file, since, collect); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// Follow calls fn with the lines of a log written at or after since, then
+// with new lines as they are written, across rotations, until ctx is done
+func Follow(ctx context.Context, path string, since time.Time, fn func(Line)) error {
+	for _, file := range Files(path) {
+		if file != path {
+			if err := readFile(file, since, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	var current *os.File
+	var reader *bufio.Reader
+	var partial string
+	defer func() {
+		if current != nil {
+			current.Close()
+		}
+	}()
+
+	// drain passes complete lines written since the last call to fn
+	drain := func() {
+		for {
+			chunk, err := reader.ReadString('\n')
+			partial += chunk
+			if err != nil {
+				return
+			}
+			if line := parseLine(strings.TrimSuffix(partial, "\n")); !line.Time.Before(since) {
+				fn(line)
+			}
+			partial = ""
+		}
+	}
+
+	for {
+		if current == nil {
+			if file, err := os.Open(path); err == nil {
+				current, reader, partial = file, bufio.NewReader(file), ""
+			}
+		}
+
+		if current != nil {
+			drain()
+
+			// After a rotation the log is a new file; finish the old one first
+			if rotated(current, path) {
+				drain()
+				current.Close()
+				current = nil
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotated reports whether path no longer names the open file
+func rotated(file *os.File, path string) bool {
+	openInfo, err := file.Stat()
+	if err != nil {
+		return true
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	return !os.SameFile(openInfo, pathInfo)
+}
+
+// readFile passes the lines of one log file written at or after since to fn
+func readFile(file string, since time.Time, fn func(Line)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		text, err := reader.ReadString('\n')
+		if text != "" {
+			if line := parseLine(strings.TrimSuffix(text, "\n")); !line.Time.Before(since) {
+				fn(line)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+	}
+}
+
+// parseLine splits a log line into its timestamp and text
+func parseLine(s string) Line {
+	stamp, text, found := strings.Cut(s, " ")
+	if !found {
+		return Line{Text: s}
+	}
+	t, err := time.Parse(time.RFC3339, stamp)
+	if err != nil {
+		return Line{Text: s}
+	}
+	return Line{Time: t, Text: text}
+}
+
+// Remove deletes a log and its rotated files
+func Remove(path string) error {
+	for _, file := range Files(path) {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Archive moves a log and its rotated files into dir
+func Archive(path, dir string) error {
+	files := Files(path)
+	if len(files) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Rename(file, filepath.Join(dir, filepath.Base(file))); err != nil {
+			return fmt.Errorf("failed to archive log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package outputlog keeps logs of what sessions print. The multiplexer pipes
+// a session's output into a writer process, which timestamps each line and
+// rotates the log by size; readers list or follow the lines.
+package outputlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"claude-pilot/core/internal/ansi"
+	"claude-pilot/core/internal/utils"
+)
+
+// WriterCommand is the hidden subcommand that runs a log writer.
+// Binaries that log session output must dispatch it to RunWriter.
+const WriterCommand = "__log-writer"
+
+// Options controls how a log is written
+type Options struct {
+	MaxSize   int64 // Bytes a log may reach before it is rotated (0 = no rotation)
+	MaxFiles  int   // Rotated logs kept besides the current one
+	StripANSI bool  // Write plain text instead of raw terminal output
+}
+
+// Command returns the shell command that runs a writer for the log at path,
+// for the multiplexer to pipe a session's output into
+func Command(path string, opts Options) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	return strings.Join([]string{
+		utils.ShellQuote(executable),
+		WriterCommand,
+		utils.ShellQuote(path),
+		strconv.FormatInt(opts.MaxSize, 10),
+		strconv.Itoa(opts.MaxFiles),
+		strconv.FormatBool(opts.StripANSI),
+	}, " "), nil
+}
+
+// RunWriter writes stdin to a log until it is closed. args are the log path,
+// the maximum size, the number of rotated logs and whether to strip ANSI
+// escape sequences, as put together by Command.
+func RunWriter(args []string) error {
+	usage := fmt.Errorf("usage: %s <path> <max-size> <max-files> <strip-ansi>", WriterCommand)
+	if len(args) != 4 {
+		return usage
+	}
+
+	var opts Options
+	var err error
+	if opts.MaxSize, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+		return usage
+	}
+	if opts.MaxFiles, err = strconv.Atoi(args[2]); err != nil {
+		return usage
+	}
+	if opts.StripANSI, err = strconv.ParseBool(args[3]); err != nil {
+		return usage
+	}
+
+	w := NewWriter(args[0], opts)
+	defer w.Close()
+	return w.Copy(os.Stdin)
+}
+
+// Writer appends timestamped lines to a log, rotating it when it grows
+// past the maximum size
+type Writer struct {
+	path string
+	opts Options
+	file *os.File
+	size int64
+	now  func() time.Time
+}
+
+// NewWriter creates a writer for the log at path. The file is opened on the
+// first write.
+func NewWriter(path string, opts Options) *Writer {
+	return &Writer{path: path, opts: opts, now: time.Now}
+}
+
+// Copy writes the output read from r line by line until r ends
+func (w *Writer) Copy(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		raw, err := reader.ReadString('\n')
+		if raw != "" {
+			if writeErr := w.writeOutput(raw); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read output: %w", err)
+		}
+	}
+}
+
+// writeOutput writes a line of output, cleaned up if ANSI is stripped.
+// Lines left empty only by the clean-up, such as pure cursor movement, are
+// skipped.
+func (w *Writer) writeOutput(raw string) error {
+	raw = strings.TrimRight(strings.TrimSuffix(raw, "\n"), "\r")
+	if !w.opts.StripANSI {
+		return w.WriteLine(raw)
+	}
+
+	text := cleanLine(raw)
+	if text == "" && raw != "" {
+		return nil
+	}
+	return w.WriteLine(text)
+}
+
+// cleanLine turns a line of terminal output into plain text: escape
+// sequences are removed, a carriage return starts the line over as it does
+// on screen, and other control characters are dropped
+func cleanLine(line string) string {
+	line = ansi.Strip(line)
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, line)
+}
+
+// WriteLine appends a line stamped with the current time
+func (w *Writer) WriteLine(text string) error {
+	entry := w.now().Format(time.RFC3339) + " " + text + "\n"
+
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(entry)) > w.opts.MaxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.WriteString(entry)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	return nil
+}
+
+// open opens the log for appending
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log: %w", err)
+	}
+
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the log to path.1, path.1 to path.2 and so on, dropping the
+// oldest, closing the current file
+func (w *Writer) rotate() error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if w.opts.MaxFiles <= 0 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log: %w", err)
+		}
+		return nil
+	}
+
+	for n := w.opts.MaxFiles - 1; n >= 1; n-- {
+		if err := os.Rename(rotatedPath(w.path, n), rotatedPath(w.path, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log: %w", err)
+		}
+	}
+	if err := os.Rename(w.path, rotatedPath(w.path, 1)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the log file
+func (w *Writer) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file, w.size = nil, 0
+	if err != nil {
+		return fmt.Errorf("failed to close log: %w", err)
+	}
+	return nil
+}
+
+// rotatedPath returns the path of the nth rotated log
+func rotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// parentSession returns the session a pane or window session is attached to
+func (s *SessionService) parentSession(session *interfaces.Session) (*interfaces.Session, error) {
+	parent, err := s.repository.FindByID(session.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("session '%s' is attached to a session that no longer exists", session.Name)
+	}
+	return parent, nil
+}
+
+// childSessions returns the panes and windows attached to a session
+func (s *SessionService) childSessions(id string) ([]*interfaces.Session, error) {
+	sessions, err := s.repository.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var children []*interfaces.Session
+	for _, session := range sessions {
+		if session.ParentID == id {
+			children = append(children, session)
+		}
+	}
+	return children, nil
+}
+
+// paneOf returns the multiplexer session and target a session's pane is
+// addressed by. Targets of panes and windows are relative to their parent,
+// which defaults to the pane or window itself.
+func (s *SessionService) paneOf(session *interfaces.Session, target string) (string, string, error) {
+	if session.ParentID == "" {
+		return session.Name, target, nil
+	}
+
+	parent, err := s.parentSession(session)
+	if err != nil {
+		return "", "", err
+	}
+	if target == "" {
+		if session.Target == "" {
+			return "", "", fmt.Errorf("the %s backend cannot address the panes and windows of session '%s'", s.multiplexer.GetName(), parent.Name)
+		}
+		target = session.Target
+	}
+	return parent.Name, target, nil
+}
+
+// attachmentTarget returns the target of a pane or window just created, for
+// backends that can address them
+func (s *SessionService) attachmentTarget(muxSession interfaces.MultiplexerSession) string {
+	if _, ok := s.multiplexer.(interfaces.TargetCloser); !ok || muxSession == nil {
+		return ""
+	}
+	return muxSession.GetID()
+}
+
+// setAttachedStatus sets the status of a pane or window session from that
+// of its parent's multiplexer session, nil when it is not running, and
+// whether the pane or window is still there. Backends that cannot tell
+// report it as long as the parent runs.
+func (s *SessionService) setAttachedStatus(session *interfaces.Session, parentName string, muxSession interfaces.MultiplexerSession) {
+	session.Status = interfaces.StatusInactive
+	session.Panes = 0
+	if muxSession == nil {
+		return
+	}
+	if closer, ok := s.multiplexer.(interfaces.TargetCloser); ok && session.Target != "" && !closer.HasTarget(parentName, session.Target) {
+		return
+	}
+
+	session.Status = interfaces.StatusActive
+	if muxSession.IsAttached() {
+		session.Status = interfaces.StatusConnected
+	}
+	session.Panes = 1
+}
+
+// updateAttachedStatus looks up the parent of a pane or window session to
+// update its status
+func (s *SessionService) updateAttachedStatus(session *interfaces.Session) {
+	parent, err := s.parentSession(session)
+	if err != nil {
+		s.setAttachedStatus(session, "", nil)
+		return
+	}
+
+	muxSession, err := s.multiplexer.GetSession(parent.Name)
+	if err != nil {
+		muxSession = nil
+	}
+	s.setAttachedStatus(session, parent.Name, muxSession)
+}
+
+// closeAttached kills the pane or window of a session, leaving the session
+// it is attached to
+func (s *SessionService) closeAttached(session *interfaces.Session) error {
+	if session.Status == interfaces.StatusInactive {
+		return nil
+	}
+
+	parent, err := s.parentSession(session)
+	if err != nil {
+		return err
+	}
+	closer, ok := s.multiplexer.(interfaces.TargetCloser)
+	if !ok || session.Target == "" {
+		return fmt.Errorf("the %s backend cannot close panes and windows on their own, kill '%s' instead", s.multiplexer.GetName(), parent.Name)
+	}
+
+	if err := closer.CloseTarget(parent.Name, session.Target); err != nil {
+		return fmt.Errorf("failed to close %s of session '%s': %w", session.Attachment, parent.Name, err)
+	}
+	return nil
+}
+
+// deleteChildren removes the metadata of the panes and windows attached to
+// a deleted session, which went with its multiplexer session
+func (s *SessionService) deleteChildren(session *interfaces.Session) {
+	children, err := s.childSessions(session.ID)
+	if err != nil {
+		s.logger.Warn("Failed to find attached sessions", "session_id", session.ID, "error", err)
+		return
+	}
+
+	for _, child := range children {
+		if err := s.removeSessionData(child); err != nil {
+			s.logger.Warn("Failed to delete attached session metadata",
+				"session_id", child.ID,
+				"name", child.Name,
+				"error", err)
+			continue
+		}
+		s.logger.Info("Attached session deleted with its parent",
+			"session_id", child.ID,
+			"name", child.Name,
+			"parent_id", session.ID)
+	}
+}
+
+// resumeAttachedSession adds the pane or window of an inactive session to
+// its parent again. Claude continues its last conversation, other commands
+// are started again as they were.
+func (s *SessionService) resumeAttachedSession(session *interfaces.Session) (*interfaces.Session, error) {
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	if session.Status != interfaces.StatusInactive {
+		return session, fmt.Errorf("session '%s' is already running", session.Name)
+	}
+	parent, err := s.parentSession(session)
+	if err != nil {
+		return session, err
+	}
+	if !s.multiplexer.IsSessionRunning(parent.Name) {
+		return session, fmt.Errorf("session '%s' is attached to '%s', which is not running", session.Name, parent.Name)
+	}
+
+	req := interfaces.CreateSessionRequest{
+		Name:           session.Name,
+		Description:    session.Description,
+		WorkingDir:     session.ProjectPath,
+		Command:        s.resumeCommand(session),
+		Env:            sessionEnv(nil, session),
+		AttachTo:       parent.Name,
+		AttachmentType: session.Attachment,
+		SplitDirection: session.SplitDirection,
+	}
+
+	muxSession, err := s.multiplexer.CreateSession(req)
+	if err != nil {
+		sessionLogger.Error("Failed to recreate attached session", "error", err)
+		return session, fmt.Errorf("failed to recreate %s of session '%s': %w", session.Attachment, parent.Name, err)
+	}
+
+	session.Target = s.attachmentTarget(muxSession)
+	session.Status = interfaces.StatusActive
+	session.LastActive = time.Now()
+	session.LastHook = nil
+	if err := s.repository.Save(session); err != nil {
+		sessionLogger.Error("Failed to update session status", "error", err)
+		return session, fmt.Errorf("session resumed but failed to update status: %w", err)
+	}
+
+	sessionLogger.Info("Attached session resumed", "parent", parent.Name, "target", session.Target)
+	return session, nil
+}
+
+// resumeChildren resumes the panes and windows of a session that was just
+// resumed. Those that fail are left inactive.
+func (s *SessionService) resumeChildren(session *interfaces.Session) {
+	children, err := s.childSessions(session.ID)
+	if err != nil {
+		s.logger.Warn("Failed to find attached sessions", "session_id", session.ID, "error", err)
+		return
+	}
+
+	for _, child := range children {
+		child.Status = interfaces.StatusInactive
+		if _, err := s.resumeAttachedSession(child); err != nil {
+			s.logger.Warn("Failed to resume attached session",
+				"session_id", child.ID,
+				"name", child.Name,
+				"error", err)
+		}
+	}
+}
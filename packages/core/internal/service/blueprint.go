@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// checkBlueprint returns an error when the multiplexer cannot split sessions
+// into the panes of a blueprint
+func (s *SessionService) checkBlueprint(panes []interfaces.BlueprintPane) error {
+	if len(panes) == 0 {
+		return nil
+	}
+	if _, ok := s.multiplexer.(interfaces.PaneSplitter); !ok {
+		return fmt.Errorf("the %s backend cannot create sessions with several panes", s.multiplexer.GetName())
+	}
+	return nil
+}
+
+// splitPanes adds the panes of a session's blueprint to its new multiplexer
+// session. When a pane fails the multiplexer session is killed, so that it is
+// never left half built.
+func (s *SessionService) splitPanes(session *interfaces.Session, env map[string]string) error {
+	if len(session.Blueprint) == 0 {
+		return nil
+	}
+	if err := s.checkBlueprint(session.Blueprint); err != nil {
+		return err
+	}
+
+	splitter := s.multiplexer.(interfaces.PaneSplitter)
+	if err := splitter.SplitPanes(session.Name, session.Blueprint, env); err != nil {
+		s.logger.WithSession(session.ID, session.Name).Error("Failed to create blueprint panes", "error", err)
+		if killErr := s.multiplexer.KillSession(session.Name); killErr != nil {
+			s.logger.WithSession(session.ID, session.Name).Warn("Failed to kill half built session", "error", killErr)
+		}
+		return fmt.Errorf("failed to create blueprint panes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// Capture returns the screen of a running session's pane, optionally with
+// scrollback and colors, without attaching to it. Blank lines below the
+// last output are dropped.
+func (s *SessionService) Capture(identifier string, opts interfaces.CaptureOptions) (string, error) {
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		return "", err
+	}
+	if session.Backend == interfaces.HeadlessBackend {
+		return "", fmt.Errorf("session '%s' is a headless run and has no screen, see its logs instead", session.Name)
+	}
+
+	capturer, ok := s.multiplexer.(interfaces.PaneCapturer)
+	if !ok {
+		return "", fmt.Errorf("the %s backend cannot capture sessions", s.multiplexer.GetName())
+	}
+	if session.Status == interfaces.StatusInactive {
+		return "", fmt.Errorf("session '%s' is not running", session.Name)
+	}
+
+	name, target, err := s.paneOf(session, opts.Target)
+	if err != nil {
+		return "", err
+	}
+	opts.Target = target
+
+	screen, err := capturer.CapturePane(name, opts)
+	if err != nil {
+		s.logger.Error("Failed to capture session",
+			"session_id", session.ID,
+			"name", session.Name,
+			"target", opts.Target,
+			"error", err)
+		return "", fmt.Errorf("failed to capture session '%s': %w", session.Name, err)
+	}
+
+	screen = strings.TrimRight(screen, " \n")
+	if screen == "" {
+		return "", nil
+	}
+	return screen + "\n", nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/shared/interfaces"
+)
+
+// needsConversation reports whether a running session has no conversation linked yet
+func (s *SessionService) needsConversation(session *interfaces.Session) bool {
+	return session.ConversationID == "" &&
+		session.ProjectPath != "" &&
+		session.Status != interfaces.StatusInactive &&
+		session.Backend != interfaces.HeadlessBackend && // Reported by the run itself
+		runsClaude(session)
+}
+
+// isClaudeCommand reports whether a session command starts Claude
+func (s *SessionService) isClaudeCommand(command string) bool {
+	return command == "" || command == s.claudeCmd
+}
+
+// runsClaude reports whether a session runs Claude. Sessions stored before
+// this was recorded only kept the command when it was not Claude.
+func runsClaude(session *interfaces.Session) bool {
+	return session.Claude || session.Command == ""
+}
+
+// linkConversations records the Claude conversations running in sessions
+// listed together. Claude only writes a transcript once the conversation
+// starts, so this is retried whenever sessions are listed. Each new
+// transcript of a project goes to the session created last before its
+// conversation started, so the session a conversation is linked to does not
+// depend on the order sessions are looked at.
+func (s *SessionService) linkConversations(sessions []*interfaces.Session) {
+	waiting := make(map[string][]*interfaces.Session) // by project path
+	for _, session := range sessions {
+		if s.needsConversation(session) {
+			waiting[session.ProjectPath] = append(waiting[session.ProjectPath], session)
+		}
+	}
+	if len(waiting) == 0 {
+		return
+	}
+
+	claimed := claimedConversations(sessions)
+	for projectPath, candidates := range waiting {
+		s.linkProjectConversations(projectPath, candidates, claimed)
+	}
+}
+
+// linkProjectConversations links the unclaimed transcripts of a project to
+// the sessions in it still waiting for a conversation
+func (s *SessionService) linkProjectConversations(projectPath string, candidates []*interfaces.Session, claimed map[string]bool) {
+	transcripts, err := claude.ListTranscripts(s.claudeDir, projectPath)
+	if err != nil {
+		s.logger.Debug("Failed to list Claude transcripts",
+			"project_path", projectPath,
+			"error", err)
+		return
+	}
+
+	// Transcripts not written since the oldest candidate was created belong
+	// to conversations from before it
+	oldest := slices.MinFunc(candidates, func(a, b *interfaces.Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}).CreatedAt
+
+	type started struct {
+		claude.Transcript
+		at time.Time
+	}
+	var conversations []started
+	for _, transcript := range transcripts {
+		if claimed[transcript.ConversationID] || transcript.ModTime.Before(oldest) {
+			continue
+		}
+		conversations = append(conversations, started{transcript, claude.TranscriptStart(transcript)})
+	}
+
+	// Oldest conversations first, each to the newest session created before it
+	slices.SortFunc(conversations, func(a, b started) int { return a.at.Compare(b.at) })
+	slices.SortFunc(candidates, func(a, b *interfaces.Session) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	for _, conversation := range conversations {
+		i := slices.IndexFunc(candidates, func(session *interfaces.Session) bool {
+			return !conversation.at.Before(session.CreatedAt)
+		})
+		if i < 0 {
+			continue
+		}
+
+		session := candidates[i]
+		candidates = slices.Delete(candidates, i, i+1)
+		claimed[conversation.ConversationID] = true
+		id := conversation.ConversationID
+
+		// The listed session carries status computed for the listing, so
+		// only the link is written to the stored one
+		sessionLogger := s.logger.WithSession(session.ID, session.Name)
+		if _, err := s.updateStored(session, func(stored *interfaces.Session) { stored.ConversationID = id }); err != nil {
+			sessionLogger.Warn("Failed to save linked conversation", "error", err)
+			continue
+		}
+		session.ConversationID = id
+		sessionLogger.Info("Linked Claude conversation", "conversation_id", id)
+	}
+}
+
+// withConversation returns a session with its conversation linked, if one
+// has started since it was last listed
+func (s *SessionService) withConversation(session *interfaces.Session) *interfaces.Session {
+	if !s.needsConversation(session) {
+		return session
+	}
+
+	sessions, err := s.ListSessions()
+	if err != nil {
+		return session
+	}
+	for _, listed := range sessions {
+		if listed.ID == session.ID {
+			return listed
+		}
+	}
+	return session
+}
+
+// claimedConversations returns the conversation IDs already linked to sessions
+func claimedConversations(sessions []*interfaces.Session) map[string]bool {
+	claimed := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		if session.ConversationID != "" {
+			claimed[session.ConversationID] = true
+		}
+	}
+	return claimed
+}
+
+// resumeCommand returns the command that restarts Claude in a recreated
+// session with its launch options: the linked conversation while its
+// transcript exists, otherwise the most recent conversation in the project
+// directory. Sessions running other commands start them again as they were.
+func (s *SessionService) resumeCommand(session *interfaces.Session) string {
+	if !runsClaude(session) {
+		return launchCommand(session.Command, session.Launch, "")
+	}
+	if session.ConversationID != "" && claude.HasTranscript(s.claudeDir, session.ProjectPath, session.ConversationID) {
+		return launchCommand(session.Command, session.Launch, "", "--resume", session.ConversationID)
+	}
+	return launchCommand(session.Command, session.Launch, "", "--continue")
+}
+
+// GetMessages returns the messages of the Claude conversation linked to a
+// session, read from its transcript
+func (s *SessionService) GetMessages(identifier string) ([]interfaces.Message, error) {
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		return nil, err
+	}
+	session = s.withConversation(session)
+
+	if session.ConversationID == "" {
+		return nil, fmt.Errorf("no Claude conversation found for session '%s' yet", session.Name)
+	}
+
+	messages, err := claude.ReadMessages(claude.TranscriptPath(s.claudeDir, session.ProjectPath, session.ConversationID))
+	if err != nil {
+		s.logger.Error("Failed to read conversation transcript",
+			"session_id", session.ID,
+			"conversation_id", session.ConversationID,
+			"error", err)
+		return nil, fmt.Errorf("failed to read conversation of session '%s': %w", session.Name, err)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"claude-pilot/core/internal/worktree"
+	"claude-pilot/shared/interfaces"
+)
+
+// Fanout creates req.Count sessions named <name>-1 to <name>-N, each in a
+// new worktree on a new branch of the same name, started with the same
+// prompt and linked by the group req.Name. Sessions that fail are reported
+// in the error while the others are still created.
+func (s *SessionService) Fanout(req interfaces.FanoutRequest) ([]*interfaces.Session, error) {
+	start := time.Now()
+
+	if req.Count < 1 {
+		return nil, fmt.Errorf("a fanout needs at least one session")
+	}
+	if req.Prompt == "" {
+		return nil, fmt.Errorf("a prompt is required for a fanout")
+	}
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("fanout-%s", time.Now().Format("20060102-150405"))
+	}
+
+	existing, err := s.ListGroupSessions(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("group '%s' already exists", req.Name)
+	}
+
+	// Killing a group keeps its branches; new sessions would silently carry
+	// on from their work rather than start from HEAD
+	workingDir := req.WorkingDir
+	if workingDir == "" {
+		if workingDir, err = os.Getwd(); err != nil {
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+	for i := 1; i <= req.Count; i++ {
+		branch := fmt.Sprintf("%s-%d", req.Name, i)
+		exists, err := worktree.BranchExists(workingDir, branch)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("branch '%s' already exists, delete the branches of group '%s' or choose another name", branch, req.Name)
+		}
+	}
+
+	s.logger.Debug("Starting fanout",
+		"group", req.Name,
+		"count", req.Count,
+		"project_path", req.WorkingDir)
+
+	var sessions []*interfaces.Session
+	var errs []error
+	for i := 1; i <= req.Count; i++ {
+		name := fmt.Sprintf("%s-%d", req.Name, i)
+		session, err := s.CreateSessionAdvanced(interfaces.CreateSessionRequest{
+			Name:        name,
+			Description: req.Description,
+			WorkingDir:  req.WorkingDir,
+			Command:     req.Command,
+			Tags:        req.Tags,
+			Worktree:    true,
+			Branch:      name,
+			Prompt:      req.Prompt,
+			Group:       req.Name,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session '%s': %w", name, err))
+		}
+		if session != nil {
+			sessions = append(sessions, session)
+		}
+	}
+
+	s.logger.Performance("Fanout", start,
+		slog.String("group", req.Name),
+		slog.Int("session_count", len(sessions)))
+
+	return sessions, errors.Join(errs...)
+}
+
+// ListGroupSessions returns the sessions of a group
+func (s *SessionService) ListGroupSessions(group string) ([]*interfaces.Session, error) {
+	sessions, err := s.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*interfaces.Session
+	for _, session := range sessions {
+		if session.Group == group {
+			members = append(members, session)
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"claude-pilot/core/internal/headless"
+	"claude-pilot/shared/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// SetRunsDir sets the directory holding the state and event logs of
+// headless runs; without it headless sessions cannot be started
+func (s *SessionService) SetRunsDir(dir string) {
+	s.runsDir = dir
+}
+
+// RunHeadless starts claude -p with a prompt as a background process,
+// tracked as a session of the headless backend. Its stream-json events are
+// logged to the session's log file, and the result is recorded when it ends.
+func (s *SessionService) RunHeadless(req interfaces.RunRequest) (*interfaces.Session, error) {
+	start := time.Now()
+
+	if req.Prompt == "" {
+		return nil, fmt.Errorf("a prompt is required for a headless run")
+	}
+	if s.runsDir == "" {
+		return nil, fmt.Errorf("headless runs are not configured")
+	}
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("run-%s", time.Now().Format("20060102-150405"))
+	}
+
+	s.logger.Debug("Starting headless run",
+		"name", req.Name,
+		"project_path", req.WorkingDir,
+		"args", req.Args)
+
+	if s.repository.Exists(req.Name) {
+		return nil, fmt.Errorf("session with name '%s' already exists", req.Name)
+	}
+
+	session := &interfaces.Session{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Backend:     interfaces.HeadlessBackend,
+		Status:      interfaces.StatusRunning,
+		CreatedAt:   time.Now(),
+		LastActive:  time.Now(),
+		ProjectPath: req.WorkingDir,
+		Description: req.Description,
+		Tags:        normalizeTags(req.Tags),
+		Run:         &interfaces.HeadlessRun{Prompt: req.Prompt, StartedAt: time.Now()},
+	}
+	session.LogFile = filepath.Join(s.runsDir, session.ID+".log")
+
+	if err := s.repository.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to save session metadata: %w", err)
+	}
+
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	spec := headless.Spec{
+		Command:    "claude",
+		Args:       req.Args,
+		WorkingDir: req.WorkingDir,
+		Env:        sessionEnv(nil, session),
+		LogFile:    session.LogFile,
+		Log:        s.outputLogging.Options,
+	}
+	run, startErr := headless.Start(s.runsDir, session.ID, req.Prompt, spec)
+	if startErr != nil {
+		sessionLogger.Error("Failed to start headless run", "error", startErr)
+		run = session.Run
+		run.FinishedAt = time.Now()
+		run.ExitCode = -1
+		run.Error = startErr.Error()
+	}
+	applyRun(session, run)
+
+	if err := s.repository.Save(session); err != nil {
+		sessionLogger.Error("Failed to update session status", "error", err)
+		return session, fmt.Errorf("run started but failed to update session: %w", err)
+	}
+	if err := s.repository.SaveIndex(); err != nil {
+		// Index save failure is not critical, just log it
+		sessionLogger.Warn("Failed to save name index after starting run", "error", err)
+	}
+
+	if startErr != nil {
+		return session, fmt.Errorf("session created but failed to start run: %w", startErr)
+	}
+
+	s.logger.Performance("RunHeadless", start,
+		slog.String("session_id", session.ID),
+		slog.String("name", session.Name))
+
+	sessionLogger.Info("Headless run started", "pid", run.PID)
+
+	return session, nil
+}
+
+// updateRunStatus reads the state of a headless session's run. The run
+// stored with the session is kept when its state file is gone.
+func (s *SessionService) updateRunStatus(session *interfaces.Session) {
+	run, err := headless.ReadRun(s.runsDir, session.ID)
+	if err != nil {
+		if session.Run == nil {
+			session.Run = &interfaces.HeadlessRun{}
+		}
+		run = session.Run
+	}
+	applyRun(session, run)
+}
+
+// applyRun sets a headless session's status and conversation from its run
+func applyRun(session *interfaces.Session, run *interfaces.HeadlessRun) {
+	session.Run = run
+	session.Panes = 0
+	if run.ConversationID != "" {
+		session.ConversationID = run.ConversationID
+	}
+
+	switch {
+	case !run.Finished():
+		session.Status = interfaces.StatusRunning
+	case run.Succeeded():
+		session.Status = interfaces.StatusSucceeded
+	default:
+		session.Status = interfaces.StatusFailed
+	}
+}
+
+// stopRun stops a headless session's run and removes its state
+func (s *SessionService) stopRun(session *interfaces.Session) error {
+	if err := headless.Stop(s.runsDir, session.ID); err != nil {
+		s.logger.WithSession(session.ID, session.Name).Debug("Failed to stop headless run", "error", err)
+	}
+	return headless.Remove(s.runsDir, session.ID)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// RecordHookEvent records a Claude Code hook event in the session it came
+// from. Unlike pane captures and transcript lookups, hooks know for certain
+// what the agent is doing and which conversation it runs, so the event
+// updates the session's agent state, conversation and last activity.
+func (s *SessionService) RecordHookEvent(event interfaces.HookEvent, origin interfaces.HookOrigin) (*interfaces.Session, error) {
+	session, err := s.findHookSession(event, origin)
+	if err != nil {
+		s.logger.Debug("No session found for hook event",
+			"event", event.Name,
+			"cwd", event.Cwd,
+			"session_id", origin.SessionID,
+			"pane", origin.Pane)
+		return nil, err
+	}
+
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	// Claude runs a hook process for each event, often several at once, so
+	// the event is recorded in the session as stored right now, unless a
+	// later event got there first
+	updated, err := s.updateStored(session, func(stored *interfaces.Session) {
+		if stored.LastHook == nil || !event.Time.Before(stored.LastHook.Time) {
+			stored.LastHook = &event
+		}
+		if event.Time.After(stored.LastActive) {
+			stored.LastActive = event.Time
+		}
+		if event.ConversationID != "" && event.ConversationID != stored.ConversationID {
+			stored.ConversationID = event.ConversationID
+			sessionLogger.Info("Linked Claude conversation from hook", "conversation_id", stored.ConversationID)
+		}
+	})
+	if err != nil {
+		sessionLogger.Error("Failed to save hook event", "event", event.Name, "error", err)
+		return nil, fmt.Errorf("failed to save hook event of session '%s': %w", session.Name, err)
+	}
+	session = updated
+
+	sessionLogger.Debug("Recorded hook event",
+		"event", event.Name,
+		"agent_state", event.AgentState,
+		"tool", event.ToolName)
+
+	return session, nil
+}
+
+// findHookSession finds the session a hook ran in: by the session ID in its
+// environment, by its pane, by its conversation, and last by the running
+// session whose project most closely contains its working directory
+func (s *SessionService) findHookSession(event interfaces.HookEvent, origin interfaces.HookOrigin) (*interfaces.Session, error) {
+	if origin.SessionID != "" {
+		if session, err := s.repository.FindByID(origin.SessionID); err == nil {
+			return session, nil
+		}
+	}
+
+	sessions, err := s.repository.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	if locator, ok := s.multiplexer.(interfaces.PaneLocator); ok && origin.Pane != "" {
+		if name, err := locator.SessionForPane(origin.Pane); err == nil {
+			for _, session := range sessions {
+				if session.Name == name {
+					return session, nil
+				}
+			}
+		}
+	}
+
+	if event.ConversationID != "" {
+		for _, session := range sessions {
+			if session.ConversationID == event.ConversationID {
+				return session, nil
+			}
+		}
+	}
+
+	var match *interfaces.Session
+	ambiguous := false
+	for _, session := range sessions {
+		if session.ProjectPath == "" || !isWithin(event.Cwd, session.ProjectPath) ||
+			!s.multiplexer.IsSessionRunning(session.Name) {
+			continue
+		}
+
+		switch {
+		case match == nil || len(session.ProjectPath) > len(match.ProjectPath):
+			match, ambiguous = session, false
+		case len(session.ProjectPath) == len(match.ProjectPath):
+			ambiguous = true
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no session found for hook in '%s'", event.Cwd)
+	}
+	if ambiguous {
+		return nil, fmt.Errorf("several sessions run in '%s', cannot tell which one the hook belongs to", match.ProjectPath)
+	}
+	return match, nil
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	if path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
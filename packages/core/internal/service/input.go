@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// SendInput types text and keys into a running session without attaching.
+// Keys are pressed first, e.g. Escape to interrupt the agent, then the text
+// is pasted in one piece so that multi-line prompts are not submitted line
+// by line, and Enter submits it if requested.
+func (s *SessionService) SendInput(identifier, text string, opts interfaces.SendInputOptions) error {
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		return err
+	}
+	if session.Backend == interfaces.HeadlessBackend {
+		return fmt.Errorf("session '%s' is a headless run and has no terminal to type into", session.Name)
+	}
+
+	sender, ok := s.multiplexer.(interfaces.InputSender)
+	if !ok {
+		return fmt.Errorf("the %s backend cannot send input to sessions", s.multiplexer.GetName())
+	}
+	if session.Status == interfaces.StatusInactive {
+		return fmt.Errorf("session '%s' is not running", session.Name)
+	}
+
+	name, target, err := s.paneOf(session, opts.Target)
+	if err != nil {
+		return err
+	}
+
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	if len(opts.Keys) > 0 {
+		if err := sender.SendKeys(name, target, opts.Keys...); err != nil {
+			sessionLogger.Error("Failed to send keys", "keys", opts.Keys, "error", err)
+			return fmt.Errorf("failed to send keys to session '%s': %w", session.Name, err)
+		}
+	}
+
+	if text != "" {
+		if err := sender.SendText(name, target, text); err != nil {
+			sessionLogger.Error("Failed to send text", "error", err)
+			return fmt.Errorf("failed to send text to session '%s': %w", session.Name, err)
+		}
+	}
+
+	if opts.Enter {
+		if err := sender.SendKeys(name, target, "Enter"); err != nil {
+			sessionLogger.Error("Failed to send Enter", "error", err)
+			return fmt.Errorf("failed to send Enter to session '%s': %w", session.Name, err)
+		}
+	}
+
+	sessionLogger.Info("Sent input to session",
+		"target", target,
+		"keys", opts.Keys,
+		"text_length", len(text),
+		"enter", opts.Enter)
+
+	return nil
+}
+
+// InputTargets returns the running sessions input can be sent to together,
+// optionally only those with tag. Headless runs have no terminal to type
+// into, and panes and windows attached to a session run commands of their
+// own rather than Claude, so both are left out.
+func (s *SessionService) InputTargets(tag string) ([]*interfaces.Session, error) {
+	sessions, err := s.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*interfaces.Session
+	for _, session := range sessions {
+		if session.Status == interfaces.StatusInactive || session.Backend == interfaces.HeadlessBackend || session.ParentID != "" {
+			continue
+		}
+		if tag != "" && !slices.Contains(session.Tags, tag) {
+			continue
+		}
+		targets = append(targets, session)
+	}
+	return targets, nil
+}
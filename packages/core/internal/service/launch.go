@@ -0,0 +1,31 @@
+package service
+
+import (
+	"strings"
+
+	"claude-pilot/core/internal/utils"
+	"claude-pilot/shared/interfaces"
+)
+
+// launchCommand builds the shell command that starts Claude with a session's
+// launch options, more arguments and an initial prompt. The command itself,
+// "claude" by default, is used as configured; all arguments are quoted.
+func launchCommand(command string, opts *interfaces.LaunchOptions, prompt string, extra ...string) string {
+	if command == "" {
+		command = "claude"
+	}
+
+	args := append(opts.CommandArgs(), extra...)
+	if prompt != "" {
+		// Options such as --allowedTools take several values and would take
+		// the prompt as one of them, and a prompt starting with "-" would be
+		// taken as an option itself
+		args = append(args, "--", prompt)
+	}
+
+	parts := []string{command}
+	for _, arg := range args {
+		parts = append(parts, utils.ShellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
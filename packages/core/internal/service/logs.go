@@ -0,0 +1,72 @@
+package service
+
+import (
+	"path/filepath"
+
+	"claude-pilot/core/internal/outputlog"
+	"claude-pilot/shared/interfaces"
+)
+
+// OutputLogging configures the logs of what sessions print
+type OutputLogging struct {
+	Enabled bool   // Log sessions whose request does not say otherwise
+	Dir     string // Directory of the logs, named after session IDs
+	Archive bool   // Move the logs of deleted sessions to Dir/archive instead of removing them
+	outputlog.Options
+}
+
+// SetOutputLogging sets how session output is logged
+func (s *SessionService) SetOutputLogging(logging OutputLogging) {
+	s.outputLogging = logging
+}
+
+// outputLogFile returns the output log of a new session if it is requested,
+// or by default when the request does not say, and empty otherwise
+func (s *SessionService) outputLogFile(session *interfaces.Session, requested *bool) string {
+	enabled := s.outputLogging.Enabled
+	if requested != nil {
+		enabled = *requested
+	}
+	if !enabled || s.outputLogging.Dir == "" {
+		return ""
+	}
+	return filepath.Join(s.outputLogging.Dir, session.ID+".log")
+}
+
+// outputLogPipe returns the command that writes a session's output to the log
+// at path, for the multiplexer to pipe the output into. It is empty when the
+// backend cannot pipe output.
+func (s *SessionService) outputLogPipe(session *interfaces.Session, path string) string {
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	if _, ok := s.multiplexer.(interfaces.OutputPiper); !ok {
+		sessionLogger.Warn("Backend cannot log session output", "backend", s.multiplexer.GetName())
+		return ""
+	}
+
+	command, err := outputlog.Command(path, s.outputLogging.Options)
+	if err != nil {
+		sessionLogger.Warn("Failed to build output log command", "error", err)
+		return ""
+	}
+	return command
+}
+
+// removeOutputLog archives or removes the log of a deleted session
+func (s *SessionService) removeOutputLog(session *interfaces.Session) {
+	if session.LogFile == "" {
+		return
+	}
+
+	var err error
+	if s.outputLogging.Archive {
+		err = outputlog.Archive(session.LogFile, filepath.Join(filepath.Dir(session.LogFile), "archive"))
+	} else {
+		err = outputlog.Remove(session.LogFile)
+	}
+	if err != nil {
+		s.logger.WithSession(session.ID, session.Name).Warn("Failed to clean up session output log",
+			"log_file", session.LogFile,
+			"error", err)
+	}
+}
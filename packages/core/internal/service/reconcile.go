@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// Reconcile brings session metadata in line with the multiplexer. Multiplexer
+// sessions without metadata, e.g. created by hand or left behind by deleted
+// metadata files, are adopted. Metadata whose multiplexer session, or pane or
+// window, is gone is reported as stale and deleted when opts.Prune is set,
+// along with its output log; its worktree is kept.
+func (s *SessionService) Reconcile(opts interfaces.ReconcileOptions) (*interfaces.ReconcileResult, error) {
+	start := time.Now()
+
+	s.logger.Debug("Reconciling sessions", "dry_run", opts.DryRun, "prune", opts.Prune)
+
+	// Without an accurate view of the multiplexer every session would look stale
+	muxSessions, err := s.multiplexer.ListSessions()
+	if err != nil {
+		s.logger.Error("Failed to list multiplexer sessions", "error", err)
+		return nil, fmt.Errorf("failed to list multiplexer sessions: %w", err)
+	}
+
+	sessions, err := s.repository.List()
+	if err != nil {
+		s.logger.Error("Failed to list sessions from repository", "error", err)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	known := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		known[session.Name] = true
+	}
+	running := make(map[string]bool, len(muxSessions))
+	for _, muxSession := range muxSessions {
+		running[muxSession.GetName()] = true
+	}
+
+	result := &interfaces.ReconcileResult{DryRun: opts.DryRun}
+
+	for _, muxSession := range muxSessions {
+		if known[muxSession.GetName()] {
+			continue
+		}
+
+		session := s.adoptedSession(muxSession)
+		result.Adopted = append(result.Adopted, session)
+
+		if opts.DryRun {
+			continue
+		}
+		if err := s.repository.Save(session); err != nil {
+			s.logger.Error("Failed to save adopted session",
+				"name", session.Name,
+				"error", err)
+			return result, fmt.Errorf("failed to save adopted session '%s': %w", session.Name, err)
+		}
+		s.logger.Info("Adopted multiplexer session",
+			"session_id", session.ID,
+			"name", session.Name,
+			"project_path", session.ProjectPath)
+	}
+
+	for _, session := range sessions {
+		// Headless runs have no multiplexer session to lose, and panes and
+		// windows are lost along with their own
+		if session.ParentID != "" {
+			if s.updateSessionStatus(session).Status != interfaces.StatusInactive {
+				continue
+			}
+		} else if running[session.Name] || session.Backend == interfaces.HeadlessBackend {
+			continue
+		}
+
+		session.Status = interfaces.StatusInactive
+		session.Panes = 0
+		result.Stale = append(result.Stale, session)
+
+		if !opts.Prune || opts.DryRun {
+			continue
+		}
+		if err := s.removeSessionData(session); err != nil {
+			s.logger.Error("Failed to prune session metadata",
+				"session_id", session.ID,
+				"name", session.Name,
+				"error", err)
+			return result, fmt.Errorf("failed to prune session '%s': %w", session.Name, err)
+		}
+		result.Pruned = append(result.Pruned, session)
+		s.logger.Info("Pruned stale session metadata",
+			"session_id", session.ID,
+			"name", session.Name)
+		if session.Worktree != nil {
+			s.logger.Info("Kept worktree of pruned session",
+				"session_id", session.ID,
+				"worktree", session.Worktree.Path,
+				"branch", session.Worktree.Branch)
+		}
+	}
+
+	if len(result.Adopted) > 0 || len(result.Pruned) > 0 {
+		if err := s.repository.SaveIndex(); err != nil {
+			// Index save failure is not critical, just log it
+			s.logger.Warn("Failed to save name index after reconciling", "error", err)
+		}
+	}
+
+	s.logger.Performance("Reconcile", start,
+		slog.Int("adopted", len(result.Adopted)),
+		slog.Int("stale", len(result.Stale)),
+		slog.Int("pruned", len(result.Pruned)))
+
+	return result, nil
+}
+
+// adoptedSession creates metadata for a multiplexer session that has none
+func (s *SessionService) adoptedSession(muxSession interfaces.MultiplexerSession) *interfaces.Session {
+	createdAt := muxSession.GetCreatedAt()
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	status := interfaces.StatusActive
+	if muxSession.IsAttached() {
+		status = interfaces.StatusConnected
+	}
+
+	session := &interfaces.Session{
+		ID:          uuid.New().String(),
+		Name:        muxSession.GetName(),
+		Backend:     s.multiplexer.GetName(),
+		Status:      status,
+		CreatedAt:   createdAt,
+		LastActive:  time.Now(),
+		ProjectPath: muxSession.GetWorkingDir(),
+		Description: muxSession.GetDescription(),
+	}
+
+	if panes, err := s.multiplexer.GetSessionPaneCount(session.Name); err == nil {
+		session.Panes = panes
+	}
+
+	return session
+}
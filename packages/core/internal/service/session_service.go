@@ -2,9 +2,15 @@ package service
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"claude-pilot/core/internal/claude"
 	"claude-pilot/core/internal/logger"
+	"claude-pilot/core/internal/outputlog"
 	"claude-pilot/shared/interfaces"
 
 	"log/slog"
@@ -17,6 +23,17 @@ type SessionService struct {
 	repository  interfaces.SessionRepository
 	multiplexer interfaces.TerminalMultiplexer
 	logger      *logger.Logger
+	claudeDir   string // Claude Code's config directory, holding conversation transcripts
+	claudeCmd   string // Command that starts Claude
+
+	usageMu       sync.Mutex
+	prices        claude.PriceTable
+	contextWindow int64
+	usageCache    map[string]usageCacheEntry // by transcript path
+
+	outputLogging OutputLogging
+	runsDir       string // State and event logs of headless runs
+	worktreesDir  string // Git worktrees created for sessions
 }
 
 // NewSessionService creates a new session service
@@ -27,6 +44,11 @@ func NewSessionService(repository interfaces.SessionRepository, multiplexer inte
 		repository:  repository,
 		multiplexer: multiplexer,
 		logger:      disabledLogger,
+		claudeDir:   claude.DefaultDir(),
+		claudeCmd:   "claude",
+
+		contextWindow: claude.DefaultContextWindow,
+		usageCache:    make(map[string]usageCacheEntry),
 	}
 }
 
@@ -36,9 +58,26 @@ func NewSessionServiceWithLogger(repository interfaces.SessionRepository, multip
 		repository:  repository,
 		multiplexer: multiplexer,
 		logger:      log,
+		claudeDir:   claude.DefaultDir(),
+		claudeCmd:   "claude",
+
+		contextWindow: claude.DefaultContextWindow,
+		usageCache:    make(map[string]usageCacheEntry),
 	}
 }
 
+// SetClaudeDir changes where Claude Code's conversation transcripts are looked up
+func (s *SessionService) SetClaudeDir(dir string) {
+	s.claudeDir = dir
+}
+
+// SetClaudeCommand sets the command that starts Claude, default_shell from
+// the configuration. Sessions running it continue their conversation when
+// resumed, others are started again as they were.
+func (s *SessionService) SetClaudeCommand(command string) {
+	s.claudeCmd = command
+}
+
 // CreateSession creates a new session with both metadata and multiplexer session
 func (s *SessionService) CreateSession(name, description, projectPath string) (*interfaces.Session, error) {
 	// Use the advanced method with default parameters
@@ -46,7 +85,7 @@ func (s *SessionService) CreateSession(name, description, projectPath string) (*
 		Name:           name,
 		Description:    description,
 		WorkingDir:     projectPath,
-		Command:        "claude",
+		Command:        s.claudeCmd,
 		AttachTo:       "",
 		AttachmentType: interfaces.AttachmentNone,
 		SplitDirection: interfaces.SplitVertical,
@@ -70,8 +109,14 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 		"attachment_type", req.AttachmentType,
 		"split_direction", req.SplitDirection)
 
-	// For attached sessions, we don't create separate metadata since they are part of existing sessions
+	// Attached sessions are panes or windows of an existing session
 	if req.AttachTo != "" && req.AttachmentType != interfaces.AttachmentNone {
+		if req.Worktree {
+			return nil, fmt.Errorf("worktrees can only be created for standalone sessions")
+		}
+		if len(req.Blueprint) > 0 {
+			return nil, fmt.Errorf("blueprints can only be used for standalone sessions")
+		}
 		return s.createAttachedSession(req, start)
 	}
 
@@ -81,6 +126,20 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 			"name", req.Name)
 		return nil, fmt.Errorf("session with name '%s' already exists", req.Name)
 	}
+	if err := s.checkBlueprint(req.Blueprint); err != nil {
+		return nil, err
+	}
+
+	// The session runs in its own worktree. It stays with a session that
+	// fails to start, and goes with one that is rolled back unless it has
+	// changes already.
+	var wt *interfaces.Worktree
+	if req.Worktree {
+		var err error
+		if wt, req.WorkingDir, err = s.createWorktree(req); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create session metadata
 	session := &interfaces.Session{
@@ -92,6 +151,14 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 		LastActive:  time.Now(),
 		ProjectPath: req.WorkingDir,
 		Description: req.Description,
+		Tags:        normalizeTags(req.Tags),
+		Worktree:    wt,
+		Group:       req.Group,
+		Command:     req.Command,
+		Claude:      s.isClaudeCommand(req.Command),
+		Launch:      req.Launch,
+		Workspace:   req.Workspace,
+		Blueprint:   req.Blueprint,
 	}
 
 	// Save session metadata first
@@ -100,12 +167,18 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 			"session_id", session.ID,
 			"name", req.Name,
 			"error", err)
+		if wt != nil {
+			_ = s.RemoveWorktree(wt, false)
+		}
 		return nil, fmt.Errorf("failed to save session metadata: %w", err)
 	}
 
-	// Set default command if not provided
-	if req.Command == "" {
-		req.Command = "claude"
+	// Claude is resumed later with the same options
+	req.Command = launchCommand(req.Command, req.Launch, req.Prompt)
+	req.Env = sessionEnv(req.Env, session)
+	logFile := s.outputLogFile(session, req.LogOutput)
+	if logFile != "" {
+		req.OutputPipe = s.outputLogPipe(session, logFile)
 	}
 
 	s.logger.Debug("Creating multiplexer session",
@@ -133,6 +206,32 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 		return session, fmt.Errorf("session created but failed to create multiplexer session: %w", err)
 	}
 
+	// A session without all of its panes is rolled back entirely
+	if err := s.splitPanes(session, req.Env); err != nil {
+		if err := s.repository.Delete(session.ID); err != nil {
+			s.logger.Error("Failed to delete session metadata",
+				"session_id", session.ID,
+				"name", req.Name,
+				"error", err)
+		}
+		if wt != nil {
+			_ = s.RemoveWorktree(wt, false)
+		}
+		if logFile != "" {
+			if err := outputlog.Remove(logFile); err != nil {
+				s.logger.Warn("Failed to remove session output log",
+					"session_id", session.ID,
+					"log_file", logFile,
+					"error", err)
+			}
+		}
+		return nil, err
+	}
+
+	if req.OutputPipe != "" {
+		session.LogFile = logFile
+	}
+
 	// Update session status
 	session.Status = interfaces.StatusActive
 	if err := s.repository.Save(session); err != nil {
@@ -165,13 +264,21 @@ func (s *SessionService) CreateSessionAdvanced(req interfaces.CreateSessionReque
 	return session, nil
 }
 
-// createAttachedSession creates a session attached to an existing session as pane or window
+// createAttachedSession creates a session attached to an existing session as
+// pane or window. Its metadata points to the session it is attached to and,
+// on backends that can address them, to its pane or window.
 func (s *SessionService) createAttachedSession(req interfaces.CreateSessionRequest, start time.Time) (*interfaces.Session, error) {
 	s.logger.Debug("Creating attached session",
 		"name", req.Name,
 		"attach_to", req.AttachTo,
 		"attachment_type", req.AttachmentType)
 
+	if s.repository.Exists(req.Name) {
+		s.logger.Warn("Session creation failed: name already exists",
+			"name", req.Name)
+		return nil, fmt.Errorf("session with name '%s' already exists", req.Name)
+	}
+
 	// Verify target session exists
 	targetSession, err := s.GetSession(req.AttachTo)
 	if err != nil {
@@ -180,14 +287,40 @@ func (s *SessionService) createAttachedSession(req interfaces.CreateSessionReque
 			"error", err)
 		return nil, fmt.Errorf("target session '%s' not found: %w", req.AttachTo, err)
 	}
+	if targetSession.ParentID != "" {
+		return nil, fmt.Errorf("target session '%s' is itself a %s of another session", req.AttachTo, targetSession.Attachment)
+	}
 
-	// Set default command if not provided
-	if req.Command == "" {
-		req.Command = "claude"
+	session := &interfaces.Session{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Backend:     s.multiplexer.GetName(),
+		Status:      interfaces.StatusActive,
+		CreatedAt:   time.Now(),
+		LastActive:  time.Now(),
+		ProjectPath: req.WorkingDir,
+		Description: req.Description,
+		Tags:        normalizeTags(req.Tags),
+		Group:       req.Group,
+		Command:     req.Command,
+		Claude:      s.isClaudeCommand(req.Command),
+		Launch:      req.Launch,
+		Workspace:   req.Workspace,
+		ParentID:    targetSession.ID,
+		Attachment:  req.AttachmentType,
+		Panes:       1,
 	}
+	if req.AttachmentType == interfaces.AttachmentPane {
+		session.SplitDirection = req.SplitDirection
+	}
+
+	// The pane or window is created in the target's multiplexer session
+	req.AttachTo = targetSession.Name
+	req.Command = launchCommand(req.Command, req.Launch, req.Prompt)
+	req.Env = sessionEnv(req.Env, session)
 
 	// Create the attached multiplexer session (pane or window)
-	_, err = s.multiplexer.CreateSession(req)
+	muxSession, err := s.multiplexer.CreateSession(req)
 	if err != nil {
 		s.logger.Error("Failed to create attached session",
 			"name", req.Name,
@@ -196,18 +329,22 @@ func (s *SessionService) createAttachedSession(req interfaces.CreateSessionReque
 			"error", err)
 		return nil, fmt.Errorf("failed to create attached session: %w", err)
 	}
+	session.Target = s.attachmentTarget(muxSession)
 
-	// Create a virtual session object representing the attachment
-	// This doesn't get saved to repository since it's part of the target session
-	attachedSession := &interfaces.Session{
-		ID:          uuid.New().String(),
-		Name:        fmt.Sprintf("%s-attached-to-%s", req.Name, req.AttachTo),
-		Backend:     s.multiplexer.GetName(),
-		Status:      interfaces.StatusActive,
-		CreatedAt:   time.Now(),
-		LastActive:  time.Now(),
-		ProjectPath: req.WorkingDir,
-		Description: fmt.Sprintf("%s (attached as %s to %s)", req.Description, req.AttachmentType, req.AttachTo),
+	if err := s.repository.Save(session); err != nil {
+		s.logger.Error("Failed to save attached session metadata",
+			"session_id", session.ID,
+			"name", req.Name,
+			"error", err)
+		return session, fmt.Errorf("attached session created but failed to save its metadata: %w", err)
+	}
+
+	if err := s.repository.SaveIndex(); err != nil {
+		// Index save failure is not critical, just log it
+		s.logger.Warn("Failed to save name index after session creation",
+			"session_id", session.ID,
+			"name", req.Name,
+			"error", err)
 	}
 
 	s.logger.Performance("CreateAttachedSession", start,
@@ -216,27 +353,70 @@ func (s *SessionService) createAttachedSession(req interfaces.CreateSessionReque
 		slog.String("attachment_type", string(req.AttachmentType)))
 
 	s.logger.Info("Attached session created successfully",
+		"session_id", session.ID,
 		"name", req.Name,
 		"attach_to", req.AttachTo,
 		"attachment_type", req.AttachmentType,
+		"target", session.Target,
 		"target_session_id", targetSession.ID)
 
-	return attachedSession, nil
+	return session, nil
 }
 
 // GetSession retrieves a session by ID or name
 func (s *SessionService) GetSession(identifier string) (*interfaces.Session, error) {
-	// Try by ID first
-	if session, err := s.repository.FindByID(identifier); err == nil {
-		return s.updateSessionStatus(session), nil
+	session, err := s.findStored(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	session = s.updateSessionStatus(session)
+	s.loadUsage(session)
+
+	return session, nil
+}
+
+// findStored loads the stored metadata of a session by ID or name, without
+// anything GetSession computes from the multiplexer and transcripts, for
+// changes that are saved again
+func (s *SessionService) findStored(identifier string) (*interfaces.Session, error) {
+	// Try by ID first, then by name
+	session, err := s.repository.FindByID(identifier)
+	if err != nil {
+		session, err = s.repository.FindByName(identifier)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("session '%s' not found", identifier)
+	}
+	return session, nil
+}
 
-	// Try by name
-	if session, err := s.repository.FindByName(identifier); err == nil {
-		return s.updateSessionStatus(session), nil
+// updateStored applies update to the stored metadata of a session and saves
+// it, atomically where the repository supports it, since hooks and other
+// commands change sessions from processes of their own
+func (s *SessionService) updateStored(session *interfaces.Session, update func(*interfaces.Session)) (*interfaces.Session, error) {
+	if updater, ok := s.repository.(interfaces.SessionUpdater); ok {
+		return updater.Update(session.ID, update)
 	}
 
-	return nil, fmt.Errorf("session '%s' not found", identifier)
+	update(session)
+	if err := s.repository.Save(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSessionDetails retrieves a session like GetSession, also linking the
+// Claude conversation running in it and detecting its agent state
+func (s *SessionService) GetSessionDetails(identifier string) (*interfaces.Session, error) {
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	session = s.withConversation(session)
+	s.detectAgentState(session)
+	return session, nil
 }
 
 // ListSessions returns all sessions with their current status
@@ -330,8 +510,19 @@ func (s *SessionService) DeleteSession(identifier string) error {
 
 	sessionLogger := s.logger.WithSession(session.ID, session.Name)
 
-	// Kill the multiplexer session if it's running
-	if s.multiplexer.IsSessionRunning(session.Name) {
+	// Stop the run of a headless session, close the pane or window of an
+	// attached one, or kill the multiplexer session if it's running
+	if session.Backend == interfaces.HeadlessBackend {
+		if err := s.stopRun(session); err != nil {
+			sessionLogger.Warn("Failed to remove headless run state", "error", err)
+		}
+	} else if session.ParentID != "" {
+		sessionLogger.Debug("Closing attached pane or window", "target", session.Target)
+		if err := s.closeAttached(session); err != nil {
+			sessionLogger.Error("Failed to close attached session", "error", err)
+			return err
+		}
+	} else if s.multiplexer.IsSessionRunning(session.Name) {
 		sessionLogger.Debug("Killing running multiplexer session")
 		if err := s.multiplexer.KillSession(session.Name); err != nil {
 			sessionLogger.Error("Failed to kill multiplexer session", "error", err)
@@ -340,11 +531,13 @@ func (s *SessionService) DeleteSession(identifier string) error {
 	}
 
 	// Remove session metadata
-	if err := s.repository.Delete(session.ID); err != nil {
+	if err := s.removeSessionData(session); err != nil {
 		sessionLogger.Error("Failed to delete session metadata", "error", err)
 		return fmt.Errorf("failed to delete session metadata: %w", err)
 	}
 
+	s.deleteChildren(session)
+
 	// Save index after deletion (important operations)
 	if err := s.repository.SaveIndex(); err != nil {
 		// Index save failure is not critical, just log it
@@ -360,6 +553,17 @@ func (s *SessionService) DeleteSession(identifier string) error {
 	return nil
 }
 
+// removeSessionData deletes the metadata of a session that is no longer
+// running and cleans up its output log. Its worktree is left to the caller.
+func (s *SessionService) removeSessionData(session *interfaces.Session) error {
+	if err := s.repository.Delete(session.ID); err != nil {
+		return err
+	}
+
+	s.removeOutputLog(session)
+	return nil
+}
+
 // AttachToSession connects to an existing session
 func (s *SessionService) AttachToSession(identifier string) error {
 	start := time.Now()
@@ -376,6 +580,20 @@ func (s *SessionService) AttachToSession(identifier string) error {
 
 	sessionLogger := s.logger.WithSession(session.ID, session.Name)
 
+	if session.Backend == interfaces.HeadlessBackend {
+		return fmt.Errorf("session '%s' is a headless run and has no terminal to attach to", session.Name)
+	}
+
+	// Panes and windows are reached through the session they are attached to
+	muxName := session.Name
+	if session.ParentID != "" {
+		parent, err := s.parentSession(session)
+		if err != nil {
+			return err
+		}
+		muxName = parent.Name
+	}
+
 	// Update session status to connected
 	session.Status = interfaces.StatusConnected
 	session.LastActive = time.Now()
@@ -387,7 +605,7 @@ func (s *SessionService) AttachToSession(identifier string) error {
 	sessionLogger.Info("Attaching to multiplexer session")
 
 	// Attach to the multiplexer session
-	err = s.multiplexer.AttachToSession(session.Name)
+	err = s.multiplexer.AttachToSession(muxName)
 	if err != nil {
 		sessionLogger.Error("Failed to attach to multiplexer session", "error", err)
 		return err
@@ -400,17 +618,169 @@ func (s *SessionService) AttachToSession(identifier string) error {
 	return nil
 }
 
+// ResumeSession recreates the multiplexer session of an inactive session in
+// its stored project path, keeping its ID and continuing the last conversation
+func (s *SessionService) ResumeSession(identifier string) (*interfaces.Session, error) {
+	start := time.Now()
+
+	s.logger.Debug("Resuming session", "identifier", identifier)
+
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		s.logger.Error("Failed to find session to resume",
+			"identifier", identifier,
+			"error", err)
+		return nil, err
+	}
+
+	sessionLogger := s.logger.WithSession(session.ID, session.Name)
+
+	if session.Backend == interfaces.HeadlessBackend {
+		return session, fmt.Errorf("session '%s' is a headless run and cannot be resumed", session.Name)
+	}
+	if session.ParentID != "" {
+		return s.resumeAttachedSession(session)
+	}
+	if s.multiplexer.IsSessionRunning(session.Name) {
+		return session, fmt.Errorf("session '%s' is already running", session.Name)
+	}
+
+	if session.ProjectPath != "" {
+		if _, err := os.Stat(session.ProjectPath); err != nil {
+			sessionLogger.Error("Project path of session is unavailable",
+				"project_path", session.ProjectPath,
+				"error", err)
+			return session, fmt.Errorf("project path of session '%s' is unavailable: %w", session.Name, err)
+		}
+	}
+
+	req := interfaces.CreateSessionRequest{
+		Name:        session.Name,
+		Description: session.Description,
+		WorkingDir:  session.ProjectPath,
+		Command:     s.resumeCommand(session),
+		Env:         sessionEnv(nil, session),
+	}
+	if session.LogFile != "" {
+		// Continue the log kept so far
+		req.OutputPipe = s.outputLogPipe(session, session.LogFile)
+	}
+
+	sessionLogger.Debug("Recreating multiplexer session",
+		"command", req.Command,
+		"working_dir", req.WorkingDir)
+
+	if _, err := s.multiplexer.CreateSession(req); err != nil {
+		sessionLogger.Error("Failed to recreate multiplexer session", "error", err)
+		return session, fmt.Errorf("failed to recreate multiplexer session: %w", err)
+	}
+	if err := s.splitPanes(session, req.Env); err != nil {
+		return session, err
+	}
+	s.resumeChildren(session)
+
+	session.Status = interfaces.StatusActive
+	session.Backend = s.multiplexer.GetName()
+	session.LastActive = time.Now()
+	session.LastHook = nil // Reported by the Claude that was stopped
+	if err := s.repository.Save(session); err != nil {
+		sessionLogger.Error("Failed to update session status", "error", err)
+		return session, fmt.Errorf("session resumed but failed to update status: %w", err)
+	}
+
+	s.logger.Performance("ResumeSession", start,
+		slog.String("session_id", session.ID),
+		slog.String("name", session.Name))
+
+	sessionLogger.Info("Session resumed successfully", "project_path", session.ProjectPath)
+
+	return session, nil
+}
+
+// ResumeTargets returns the inactive sessions to resume to bring all of them
+// back. Panes and windows whose session is inactive too are left out, as
+// they are resumed along with it.
+func (s *SessionService) ResumeTargets() ([]*interfaces.Session, error) {
+	sessions, err := s.ListFilteredSessions("inactive")
+	if err != nil {
+		return nil, err
+	}
+
+	inactive := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		inactive[session.ID] = true
+	}
+
+	var targets []*interfaces.Session
+	for _, session := range sessions {
+		if !inactive[session.ParentID] {
+			targets = append(targets, session)
+		}
+	}
+	return targets, nil
+}
+
+// SetMuted turns notifications for a session off or back on
+func (s *SessionService) SetMuted(identifier string, muted bool) (*interfaces.Session, error) {
+	session, err := s.findStored(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.updateStored(session, func(stored *interfaces.Session) {
+		stored.Muted = muted
+	})
+	if err != nil {
+		s.logger.Error("Failed to save session mute state",
+			"session_id", session.ID,
+			"muted", muted,
+			"error", err)
+		return nil, fmt.Errorf("failed to update session '%s': %w", session.Name, err)
+	}
+	session = updated
+
+	s.logger.Info("Session notifications changed", "session_id", session.ID, "muted", muted)
+	return session, nil
+}
+
+// normalizeTags trims tags and drops empty and duplicate ones
+func normalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
 // IsSessionRunning checks if the session's multiplexer is active
 func (s *SessionService) IsSessionRunning(identifier string) bool {
 	session, err := s.GetSession(identifier)
 	if err != nil {
 		return false
 	}
+	if session.Backend == interfaces.HeadlessBackend {
+		return session.Status == interfaces.StatusRunning
+	}
+	if session.ParentID != "" {
+		return session.Status != interfaces.StatusInactive
+	}
 	return s.multiplexer.IsSessionRunning(session.Name)
 }
 
 // updateSessionStatus updates a session's status based on multiplexer state
 func (s *SessionService) updateSessionStatus(session *interfaces.Session) *interfaces.Session {
+	if session.Backend == interfaces.HeadlessBackend {
+		s.updateRunStatus(session)
+		return session
+	}
+	if session.ParentID != "" {
+		s.updateAttachedStatus(session)
+		return session
+	}
+
 	if s.multiplexer.IsSessionRunning(session.Name) {
 		// Check if someone is attached (this is backend-specific and may not be available)
 		if muxSession, err := s.multiplexer.GetSession(session.Name); err == nil {
@@ -429,6 +799,63 @@ func (s *SessionService) updateSessionStatus(session *interfaces.Session) *inter
 	return session
 }
 
+// DetectAgentStates sets the agent state of running sessions. Unless hooks
+// report it, each session's pane is captured, so this is left to the views
+// that show the state rather than done on every lookup.
+func (s *SessionService) DetectAgentStates(sessions []*interfaces.Session) {
+	for _, session := range sessions {
+		s.detectAgentState(session)
+	}
+}
+
+// detectAgentState classifies what the Claude agent in a running session is
+// doing from its pane, for backends that can capture panes
+func (s *SessionService) detectAgentState(session *interfaces.Session) {
+	session.AgentState = interfaces.AgentUnknown
+
+	if session.Status == interfaces.StatusInactive || session.Backend == interfaces.HeadlessBackend {
+		return
+	}
+
+	// Hooks report every change, so the pane is only read for sessions
+	// without them, or once Claude ended and its SessionEnd hook left no state
+	if session.LastHook != nil && session.LastHook.AgentState != interfaces.AgentUnknown {
+		session.AgentState = session.LastHook.AgentState
+		return
+	}
+
+	capturer, ok := s.multiplexer.(interfaces.PaneCapturer)
+	if !ok {
+		return
+	}
+	name, target, err := s.paneOf(session, "")
+	if err != nil {
+		return
+	}
+
+	screen, err := capturer.CapturePane(name, interfaces.CaptureOptions{Target: target})
+	if err != nil {
+		s.logger.Debug("Failed to capture session pane",
+			"session_id", session.ID,
+			"name", session.Name,
+			"error", err)
+		return
+	}
+
+	session.AgentState = claude.DetectAgentState(screen)
+}
+
+// sessionEnv returns env extended with the variables every session's command
+// gets, such as the session ID that Claude Code hooks report back
+func sessionEnv(env map[string]string, session *interfaces.Session) map[string]string {
+	result := make(map[string]string, len(env)+1)
+	for key, value := range env {
+		result[key] = value
+	}
+	result[interfaces.SessionIDEnv] = session.ID
+	return result
+}
+
 // batchUpdateSessionStatus efficiently updates status for multiple sessions
 func (s *SessionService) batchUpdateSession(sessions []*interfaces.Session) {
 	// Get all multiplexer sessions once
@@ -447,9 +874,26 @@ func (s *SessionService) batchUpdateSession(sessions []*interfaces.Session) {
 	for _, muxSession := range muxSessions {
 		muxSessionMap[muxSession.GetName()] = muxSession
 	}
+	sessionMap := make(map[string]*interfaces.Session, len(sessions))
+	for _, session := range sessions {
+		sessionMap[session.ID] = session
+	}
 
 	// Update all sessions using the batch data
 	for _, session := range sessions {
+		if session.Backend == interfaces.HeadlessBackend {
+			s.updateRunStatus(session)
+			continue
+		}
+		if session.ParentID != "" {
+			if parent, ok := sessionMap[session.ParentID]; ok {
+				s.setAttachedStatus(session, parent.Name, muxSessionMap[parent.Name])
+			} else {
+				s.setAttachedStatus(session, "", nil)
+			}
+			continue
+		}
+
 		if muxSession, exists := muxSessionMap[session.Name]; exists {
 			// Session exists in multiplexer
 			if muxSession.IsAttached() {
@@ -469,6 +913,11 @@ func (s *SessionService) batchUpdateSession(sessions []*interfaces.Session) {
 			session.Panes = 0
 		}
 	}
+
+	s.linkConversations(sessions)
+	for _, session := range sessions {
+		s.loadUsage(session)
+	}
 }
 
 // KillAllSessions terminates all sessions
@@ -478,8 +927,17 @@ func (s *SessionService) KillAllSessions() error {
 		return fmt.Errorf("failed to list sessions: %w", err)
 	}
 
+	ids := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		ids[session.ID] = true
+	}
+
 	var errors []string
 	for _, session := range sessions {
+		// Panes and windows go with the session they are attached to
+		if ids[session.ParentID] {
+			continue
+		}
 		if err := s.DeleteSession(session.ID); err != nil {
 			errors = append(errors, fmt.Sprintf("failed to delete session %s: %v", session.Name, err))
 		}
@@ -509,6 +967,11 @@ func (s *SessionService) GetSessionPaneCount(identifier string) (int, error) {
 		return 0, err
 	}
 
+	// A pane or window counts as one pane of the session it is attached to
+	if session.ParentID != "" {
+		return session.Panes, nil
+	}
+
 	// Get pane count from multiplexer
 	return s.multiplexer.GetSessionPaneCount(session.Name)
 }
@@ -0,0 +1,1088 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/core/internal/headless"
+	"claude-pilot/core/internal/outputlog"
+	"claude-pilot/core/internal/storage"
+	"claude-pilot/core/multiplexertest"
+	"claude-pilot/shared/interfaces"
+)
+
+// TestMain lets the test binary act as the runner of headless sessions
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == headless.RunnerCommand {
+		if err := headless.RunRunner(os.Args[2:]); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func newTestService(t *testing.T) (*SessionService, *multiplexertest.FakeMultiplexer) {
+	t.Helper()
+
+	repository, err := storage.NewFileSessionRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	fake := multiplexertest.NewFakeMultiplexer()
+	svc := NewSessionService(repository, fake)
+	svc.SetClaudeDir(t.TempDir())
+	return svc, fake
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	session, err := svc.CreateSession("api", "backend work", "/tmp/api")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if session.Backend != "fake" || session.Status != interfaces.StatusActive {
+		t.Errorf("unexpected session: backend=%s status=%s", session.Backend, session.Status)
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || windows[0].Panes[0].Command != "claude" {
+		t.Errorf("unexpected multiplexer windows: %+v", windows)
+	}
+
+	if _, err := svc.CreateSession("api", "", ""); err == nil {
+		t.Error("creating a duplicate session succeeded")
+	}
+
+	// Attached as a pane, the session gains a pane with metadata of its own
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{
+		Name:           "tests",
+		AttachTo:       "api",
+		AttachmentType: interfaces.AttachmentPane,
+		SplitDirection: interfaces.SplitHorizontal,
+	}); err != nil {
+		t.Fatalf("attaching a pane failed: %v", err)
+	}
+
+	sessions, err := svc.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected the session and its attached pane, got %d sessions", len(sessions))
+	}
+	for _, got := range sessions {
+		if got.Name == "api" && got.Panes != 2 {
+			t.Errorf("session has %d panes, want 2", got.Panes)
+		}
+		if got.Name == "tests" && got.ParentID != session.ID {
+			t.Errorf("attached pane has parent %q, want %q", got.ParentID, session.ID)
+		}
+	}
+
+	// Status follows the multiplexer's attach state
+	fake.OnAttach = func(name string) error {
+		if got, _ := svc.GetSession(name); got.Status != interfaces.StatusConnected {
+			t.Errorf("status while attached = %s, want %s", got.Status, interfaces.StatusConnected)
+		}
+		return nil
+	}
+	if err := svc.AttachToSession(session.ID); err != nil {
+		t.Fatalf("AttachToSession failed: %v", err)
+	}
+	if fake.AttachCount("api") != 1 {
+		t.Errorf("AttachCount = %d, want 1", fake.AttachCount("api"))
+	}
+
+	// A session whose multiplexer session died is reported inactive
+	if err := fake.KillSession("api"); err != nil {
+		t.Fatalf("failed to kill fake session: %v", err)
+	}
+	if got, _ := svc.GetSession("api"); got.Status != interfaces.StatusInactive {
+		t.Errorf("status after external kill = %s, want %s", got.Status, interfaces.StatusInactive)
+	}
+
+	if err := svc.DeleteSession("api"); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if _, err := svc.GetSession("api"); err == nil {
+		t.Error("session still exists after DeleteSession")
+	}
+}
+
+func TestCreateSessionMultiplexerFailure(t *testing.T) {
+	svc, fake := newTestService(t)
+	fake.FailOn("CreateSession", errors.New("no server"))
+
+	session, err := svc.CreateSession("api", "", "")
+	if err == nil {
+		t.Fatal("CreateSession succeeded despite multiplexer failure")
+	}
+	if session == nil || session.Status != interfaces.StatusInactive {
+		t.Errorf("expected metadata to be kept as inactive, got %+v", session)
+	}
+}
+
+func TestDeleteSessionKillsMultiplexerSession(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := svc.DeleteSession("api"); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if fake.HasSession("api") {
+		t.Error("multiplexer session still running after DeleteSession")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("kept", "", "/tmp/kept"); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if _, err := svc.CreateSession("crashed", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	_ = fake.KillSession("crashed")
+	if _, err := fake.CreateSession(interfaces.CreateSessionRequest{Name: "manual", WorkingDir: "/tmp/manual"}); err != nil {
+		t.Fatalf("failed to create fake session: %v", err)
+	}
+
+	// A dry run reports without changing anything
+	result, err := svc.Reconcile(interfaces.ReconcileOptions{DryRun: true, Prune: true})
+	if err != nil {
+		t.Fatalf("Reconcile dry run failed: %v", err)
+	}
+	if len(result.Adopted) != 1 || len(result.Stale) != 1 || len(result.Pruned) != 0 {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+	if _, err := svc.GetSession("manual"); err == nil {
+		t.Error("dry run adopted a session")
+	}
+
+	result, err = svc.Reconcile(interfaces.ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Pruned) != 1 || result.Pruned[0].Name != "crashed" {
+		t.Errorf("unexpected pruned sessions: %+v", result.Pruned)
+	}
+
+	adopted, err := svc.GetSession("manual")
+	if err != nil {
+		t.Fatalf("adopted session not found: %v", err)
+	}
+	if adopted.ProjectPath != "/tmp/manual" || adopted.Backend != "fake" {
+		t.Errorf("unexpected adopted session: %+v", adopted)
+	}
+	if _, err := svc.GetSession("crashed"); err == nil {
+		t.Error("stale session was not pruned")
+	}
+
+	// Reconciling again finds nothing to do
+	result, err = svc.Reconcile(interfaces.ReconcileOptions{Prune: true})
+	if err != nil || len(result.Adopted)+len(result.Stale) != 0 {
+		t.Errorf("second Reconcile = %+v, %v; want no changes", result, err)
+	}
+}
+
+func TestReconcileMultiplexerFailure(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	fake.FailOn("ListSessions", errors.New("no server"))
+
+	// An unreachable multiplexer must not make every session look stale
+	if _, err := svc.Reconcile(interfaces.ReconcileOptions{Prune: true}); err == nil {
+		t.Fatal("Reconcile succeeded despite multiplexer failure")
+	}
+	if _, err := svc.GetSession("api"); err != nil {
+		t.Errorf("session lost after failed Reconcile: %v", err)
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	svc, fake := newTestService(t)
+	projectPath := t.TempDir()
+
+	session, err := svc.CreateSession("api", "backend work", projectPath)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if _, err := svc.ResumeSession("api"); err == nil {
+		t.Error("resuming a running session succeeded")
+	}
+
+	// Simulate a reboot taking the multiplexer session down
+	_ = fake.KillSession("api")
+
+	resumed, err := svc.ResumeSession("api")
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if resumed.ID != session.ID || resumed.Status != interfaces.StatusActive {
+		t.Errorf("unexpected resumed session: %+v", resumed)
+	}
+
+	windows := fake.Windows("api")
+	if len(windows) != 1 || windows[0].Panes[0].Command != "claude --continue" || windows[0].Panes[0].WorkingDir != projectPath {
+		t.Errorf("unexpected multiplexer windows after resume: %+v", windows)
+	}
+}
+
+func TestResumeByCommand(t *testing.T) {
+	svc, fake := newTestService(t)
+	svc.SetClaudeCommand("/opt/bin/claude")
+
+	create := func(name, command, attachTo string) {
+		t.Helper()
+		req := interfaces.CreateSessionRequest{Name: name, Command: command, AttachTo: attachTo}
+		if attachTo != "" {
+			req.AttachmentType = interfaces.AttachmentPane
+		}
+		if _, err := svc.CreateSessionAdvanced(req); err != nil {
+			t.Fatalf("creating %s failed: %v", name, err)
+		}
+	}
+	create("api", "/opt/bin/claude", "")
+	create("review", "/opt/bin/claude", "api")
+	create("tests", "go test ./...", "api")
+	create("dev", "npm run dev", "")
+
+	_ = fake.KillSession("api")
+	_ = fake.KillSession("dev")
+	for _, name := range []string{"api", "dev"} {
+		if _, err := svc.ResumeSession(name); err != nil {
+			t.Fatalf("ResumeSession of %s failed: %v", name, err)
+		}
+	}
+
+	// Only Claude continues its conversation, wherever it runs
+	var commands []string
+	for _, pane := range fake.Windows("api")[0].Panes {
+		commands = append(commands, pane.Command)
+	}
+	slices.Sort(commands)
+	want := []string{"/opt/bin/claude --continue", "/opt/bin/claude --continue", "go test ./..."}
+	if !slices.Equal(commands, want) {
+		t.Errorf("resumed api panes run %q, want %q", commands, want)
+	}
+	if command := fake.Windows("dev")[0].Panes[0].Command; command != "npm run dev" {
+		t.Errorf("resumed dev runs %q, want the command unchanged", command)
+	}
+}
+
+func TestLaunchOptions(t *testing.T) {
+	svc, fake := newTestService(t)
+	svc.SetClaudeCommand("my-claude")
+
+	launch := &interfaces.LaunchOptions{
+		Model:              "opus",
+		AllowedTools:       []string{"Bash(git log:*)", "Edit"},
+		AppendSystemPrompt: "Be brief",
+		Args:               []string{"--verbose"},
+	}
+	_, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{
+		Name:    "api",
+		Command: "my-claude",
+		Launch:  launch,
+		Prompt:  "Fix it",
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionAdvanced failed: %v", err)
+	}
+
+	// The prompt must not be taken as one of the allowed tools
+	want := `my-claude --model opus --allowedTools 'Bash(git log:*)' Edit --append-system-prompt 'Be brief' --verbose -- 'Fix it'`
+	if command := fake.Windows("api")[0].Panes[0].Command; command != want {
+		t.Errorf("session runs %q, want %q", command, want)
+	}
+
+	_ = fake.KillSession("api")
+	if _, err := svc.ResumeSession("api"); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	want = `my-claude --model opus --allowedTools 'Bash(git log:*)' Edit --append-system-prompt 'Be brief' --verbose --continue`
+	if command := fake.Windows("api")[0].Panes[0].Command; command != want {
+		t.Errorf("resumed session runs %q, want %q", command, want)
+	}
+
+	// Nor an option, without launch options either
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "web", Prompt: "--help me"}); err != nil {
+		t.Fatalf("CreateSessionAdvanced failed: %v", err)
+	}
+	if command, want := fake.Windows("web")[0].Panes[0].Command, `claude -- '--help me'`; command != want {
+		t.Errorf("session runs %q, want %q", command, want)
+	}
+}
+
+func TestBlueprint(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	blueprint := []interfaces.BlueprintPane{
+		{},
+		{Command: "go test ./...", SplitDirection: interfaces.SplitHorizontal, Size: 30},
+	}
+	projectPath := t.TempDir()
+	session, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "api", WorkingDir: projectPath, Blueprint: blueprint})
+	if err != nil {
+		t.Fatalf("CreateSessionAdvanced failed: %v", err)
+	}
+	panes := fake.Windows("api")[0].Panes
+	if len(panes) != 3 || panes[2].Command != "go test ./..." || panes[1].WorkingDir != projectPath {
+		t.Fatalf("session panes = %+v, want claude, a shell and the tests", panes)
+	}
+	if panes[2].Env[interfaces.SessionIDEnv] != session.ID {
+		t.Errorf("blueprint pane environment = %v, want the session ID", panes[2].Env)
+	}
+
+	// Resumed sessions get their panes back
+	_ = fake.KillSession("api")
+	if _, err := svc.ResumeSession("api"); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if panes := fake.Windows("api")[0].Panes; len(panes) != 3 {
+		t.Errorf("resumed session has %d panes, want 3", len(panes))
+	}
+
+	// A failing pane rolls the whole session back
+	fake.FailOn("SplitPanes", fmt.Errorf("no space for new pane"))
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "web", Blueprint: blueprint}); err == nil {
+		t.Fatal("CreateSessionAdvanced succeeded with a failing pane")
+	}
+	if fake.HasSession("web") {
+		t.Error("multiplexer session is left after a failing pane")
+	}
+	if _, err := svc.GetSession("web"); err == nil {
+		t.Error("session metadata is left after a failing pane")
+	}
+}
+
+func TestBlueprintRollbackRemovesWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	svc, fake := newTestService(t)
+	svc.SetWorktreesDir(t.TempDir())
+
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+
+	// The worktree was only just created, so it goes with the session
+	fake.FailOn("SplitPanes", fmt.Errorf("no space for new pane"))
+	blueprint := []interfaces.BlueprintPane{{Command: "go test ./..."}}
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "web", WorkingDir: repo, Worktree: true, Blueprint: blueprint}); err == nil {
+		t.Fatal("CreateSessionAdvanced succeeded with a failing pane")
+	}
+	output, err := exec.Command("git", "-C", repo, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		t.Fatalf("git worktree list failed: %v", err)
+	}
+	if worktrees := strings.Count(string(output), "worktree "); worktrees != 1 {
+		t.Errorf("%d worktrees after the rollback, want the repository's only:\n%s", worktrees, output)
+	}
+	if err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "-q", "refs/heads/web").Run(); err != nil {
+		t.Error("branch of the rolled back worktree was deleted")
+	}
+}
+
+func TestAttachedSessions(t *testing.T) {
+	svc, fake := newTestService(t)
+	projectPath := t.TempDir()
+
+	parent, err := svc.CreateSession("api", "", projectPath)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	attach := func(name string, attachment interfaces.AttachmentType, command string) *interfaces.Session {
+		t.Helper()
+		session, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{
+			Name:           name,
+			WorkingDir:     projectPath,
+			Command:        command,
+			AttachTo:       "api",
+			AttachmentType: attachment,
+			SplitDirection: interfaces.SplitHorizontal,
+		})
+		if err != nil {
+			t.Fatalf("attaching %s failed: %v", name, err)
+		}
+		return session
+	}
+	tests := attach("tests", interfaces.AttachmentPane, "go test ./...")
+	logs := attach("logs", interfaces.AttachmentWindow, "tail -f app.log")
+
+	if tests.ParentID != parent.ID || tests.Target == "" || tests.Command != "go test ./..." {
+		t.Errorf("unexpected attached pane: %+v", tests)
+	}
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "tests", AttachTo: "api", AttachmentType: interfaces.AttachmentPane}); err == nil {
+		t.Error("attaching a pane with a taken name succeeded")
+	}
+
+	// Input goes to the pane itself, not to Claude's
+	if err := svc.SendInput("tests", "-run TestAPI", interfaces.SendInputOptions{Enter: true}); err != nil {
+		t.Fatalf("SendInput failed: %v", err)
+	}
+	if panes := fake.Windows("api")[0].Panes; len(panes[0].Input) != 0 || len(panes[1].Input) != 2 {
+		t.Errorf("input reached the wrong pane: %+v", panes)
+	}
+
+	// Input for all sessions goes to Claude only, not into the pane's tests
+	// or the window's tail
+	targets, err := svc.InputTargets("")
+	if err != nil {
+		t.Fatalf("InputTargets failed: %v", err)
+	}
+	if len(targets) != 1 || targets[0].ID != parent.ID {
+		t.Errorf("input targets = %v, want only the session the pane and window are attached to", targets)
+	}
+
+	// Killing a pane leaves the rest of the session
+	if err := svc.DeleteSession("tests"); err != nil {
+		t.Fatalf("DeleteSession of the pane failed: %v", err)
+	}
+	if windows := fake.Windows("api"); len(windows) != 2 || len(windows[0].Panes) != 1 {
+		t.Errorf("unexpected windows after killing the pane: %+v", windows)
+	}
+
+	// A closed window is inactive and can be added again
+	if err := fake.CloseTarget("api", logs.Target); err != nil {
+		t.Fatalf("failed to close fake window: %v", err)
+	}
+	if got, _ := svc.GetSession("logs"); got.Status != interfaces.StatusInactive {
+		t.Errorf("status of closed window = %s, want %s", got.Status, interfaces.StatusInactive)
+	}
+	if _, err := svc.ResumeSession("logs"); err != nil {
+		t.Fatalf("ResumeSession of the window failed: %v", err)
+	}
+	if windows := fake.Windows("api"); len(windows) != 2 || windows[1].Panes[0].Command != "tail -f app.log" {
+		t.Errorf("unexpected windows after resuming the window: %+v", windows)
+	}
+
+	// Killing the session takes its panes and windows along
+	if err := svc.DeleteSession("api"); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if sessions, _ := svc.ListSessions(); len(sessions) != 0 {
+		t.Errorf("%d sessions left after killing their parent", len(sessions))
+	}
+}
+
+func TestResumeTargets(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	create := func(name, attachTo string, attachment interfaces.AttachmentType) *interfaces.Session {
+		t.Helper()
+		session, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{
+			Name: name, Command: "claude", AttachTo: attachTo, AttachmentType: attachment,
+		})
+		if err != nil {
+			t.Fatalf("creating %s failed: %v", name, err)
+		}
+		return session
+	}
+	create("api", "", interfaces.AttachmentNone)
+	create("tests", "api", interfaces.AttachmentPane)
+	create("docs", "", interfaces.AttachmentNone)
+	logs := create("logs", "docs", interfaces.AttachmentWindow)
+
+	// The pane goes down with its session, the window alone
+	_ = fake.KillSession("api")
+	if err := fake.CloseTarget("docs", logs.Target); err != nil {
+		t.Fatalf("failed to close fake window: %v", err)
+	}
+
+	targets, err := svc.ResumeTargets()
+	if err != nil {
+		t.Fatalf("ResumeTargets failed: %v", err)
+	}
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+	slices.Sort(names)
+	if want := []string{"api", "logs"}; !slices.Equal(names, want) {
+		t.Fatalf("resume targets = %q, want %q", names, want)
+	}
+
+	// Resuming each brings everything back without resuming the pane twice
+	for _, target := range targets {
+		if _, err := svc.ResumeSession(target.ID); err != nil {
+			t.Errorf("ResumeSession of %s failed: %v", target.Name, err)
+		}
+	}
+	if targets, _ := svc.ResumeTargets(); len(targets) != 0 {
+		t.Errorf("%d sessions left to resume", len(targets))
+	}
+	if windows := fake.Windows("api"); len(windows) != 1 || len(windows[0].Panes) != 2 {
+		t.Errorf("api windows after resuming = %+v, want Claude and the tests", windows)
+	}
+}
+
+func TestConversationLinking(t *testing.T) {
+	svc, fake := newTestService(t)
+	projectPath := t.TempDir()
+
+	session, err := svc.CreateSession("api", "", projectPath)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	transcriptDir := claude.ProjectDir(svc.claudeDir, projectPath)
+	if err := os.MkdirAll(transcriptDir, 0755); err != nil {
+		t.Fatalf("failed to create transcript directory: %v", err)
+	}
+	writeTranscript := func(id string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(transcriptDir, id+".jsonl")
+		if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+			t.Fatalf("failed to write transcript: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set transcript time: %v", err)
+		}
+	}
+
+	// A conversation from before the session is not linked
+	const oldID = "0bbafd23-3ced-4393-bd8c-a45ab806a7f1"
+	writeTranscript(oldID, session.CreatedAt.Add(-time.Hour))
+	if got, _ := svc.GetSession("api"); got.ConversationID != "" {
+		t.Fatalf("linked a conversation from before the session: %s", got.ConversationID)
+	}
+
+	// Status is computed for the listing and never written back
+	session.Status = interfaces.StatusInactive
+	if err := svc.repository.Save(session); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+
+	const newID = "fa63cc01-aec7-4573-8adb-2ef2d7bae30a"
+	writeTranscript(newID, time.Now().Add(time.Minute))
+	sessions, err := svc.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if sessions[0].ConversationID != newID {
+		t.Fatalf("ConversationID = %q, want %q", sessions[0].ConversationID, newID)
+	}
+	stored, err := svc.repository.FindByID(session.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if stored.ConversationID != newID || stored.Status != interfaces.StatusInactive {
+		t.Errorf("stored session = %q %s, want only the link written", stored.ConversationID, stored.Status)
+	}
+
+	// The link is stored, and resuming picks up exactly that conversation
+	_ = fake.KillSession("api")
+	if _, err := svc.ResumeSession("api"); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if command := fake.Windows("api")[0].Panes[0].Command; command != "claude --resume "+newID {
+		t.Errorf("resume command = %q, want --resume with the linked conversation", command)
+	}
+}
+
+func TestConversationLinkingOwner(t *testing.T) {
+	svc, _ := newTestService(t)
+	projectPath := t.TempDir()
+	now := time.Now()
+
+	// Two sessions in the same project, created an hour apart
+	for name, createdAt := range map[string]time.Time{"first": now.Add(-2 * time.Hour), "second": now.Add(-time.Hour)} {
+		session, err := svc.CreateSession(name, "", projectPath)
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		session.CreatedAt = createdAt
+		if err := svc.repository.Save(session); err != nil {
+			t.Fatalf("failed to save session: %v", err)
+		}
+	}
+
+	transcriptDir := claude.ProjectDir(svc.claudeDir, projectPath)
+	if err := os.MkdirAll(transcriptDir, 0755); err != nil {
+		t.Fatalf("failed to create transcript directory: %v", err)
+	}
+	writeTranscript := func(id string, startedAt time.Time) {
+		t.Helper()
+		entry := fmt.Sprintf(`{"type":"user","timestamp":%q}`+"\n", startedAt.UTC().Format(time.RFC3339Nano))
+		if err := os.WriteFile(filepath.Join(transcriptDir, id+".jsonl"), []byte(entry), 0644); err != nil {
+			t.Fatalf("failed to write transcript: %v", err)
+		}
+	}
+	linked := func() map[string]string {
+		t.Helper()
+		sessions, err := svc.ListSessions()
+		if err != nil {
+			t.Fatalf("ListSessions failed: %v", err)
+		}
+		result := make(map[string]string)
+		for _, session := range sessions {
+			result[session.Name] = session.ConversationID
+		}
+		return result
+	}
+
+	// The second session's Claude started first; the conversation is still
+	// its own, however the sessions are looked up
+	const secondID = "fa63cc01-aec7-4573-8adb-2ef2d7bae30a"
+	writeTranscript(secondID, now.Add(-30*time.Minute))
+	if got, _ := svc.GetSession("first"); got.ConversationID != "" {
+		t.Fatalf("looking a session up linked conversation %s", got.ConversationID)
+	}
+	if got := linked(); got["first"] != "" || got["second"] != secondID {
+		t.Fatalf("linked conversations = %v, want %s for the second session only", got, secondID)
+	}
+
+	const firstID = "0bbafd23-3ced-4393-bd8c-a45ab806a7f1"
+	writeTranscript(firstID, now.Add(-90*time.Minute))
+	if got := linked(); got["first"] != firstID || got["second"] != secondID {
+		t.Errorf("linked conversations = %v, want %s for the first session", got, firstID)
+	}
+}
+
+func TestAgentState(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	_ = fake.SetScreen("api", "● Waiting on you\n╭────╮\n│ >  │\n╰────╯\n")
+
+	// Panes are only read when the state is asked for
+	sessions, err := svc.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if sessions[0].AgentState != interfaces.AgentUnknown {
+		t.Errorf("AgentState = %q after listing sessions, want none", sessions[0].AgentState)
+	}
+	svc.DetectAgentStates(sessions)
+	if sessions[0].AgentState != interfaces.AgentWaitingInput {
+		t.Errorf("AgentState = %q, want %q", sessions[0].AgentState, interfaces.AgentWaitingInput)
+	}
+
+	// Stopped sessions have no agent state
+	_ = fake.KillSession("api")
+	if got, _ := svc.GetSessionDetails("api"); got.AgentState != interfaces.AgentUnknown {
+		t.Errorf("AgentState of a stopped session = %q, want none", got.AgentState)
+	}
+}
+
+func TestRecordHookEvent(t *testing.T) {
+	svc, fake := newTestService(t)
+	apiDir, webDir := t.TempDir(), t.TempDir()
+
+	api, err := svc.CreateSession("api", "", apiDir)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if _, err := svc.CreateSession("web", "", webDir); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if env := fake.Windows("api")[0].Panes[0].Env; env[interfaces.SessionIDEnv] != api.ID {
+		t.Errorf("session command environment = %v, want %s=%s", env, interfaces.SessionIDEnv, api.ID)
+	}
+
+	// Hook state wins over the pane
+	_ = fake.SetScreen("api", "│ >  │\n")
+	event := interfaces.HookEvent{
+		Name:           "Notification",
+		ConversationID: "conv-1",
+		AgentState:     interfaces.AgentWaitingPermission,
+		Time:           time.Now(),
+	}
+	if _, err := svc.RecordHookEvent(event, interfaces.HookOrigin{SessionID: api.ID}); err != nil {
+		t.Fatalf("RecordHookEvent failed: %v", err)
+	}
+	got, _ := svc.GetSessionDetails("api")
+	if got.AgentState != interfaces.AgentWaitingPermission || got.ConversationID != "conv-1" {
+		t.Errorf("after hook: AgentState = %q, ConversationID = %q", got.AgentState, got.ConversationID)
+	}
+
+	// An event that arrives late does not replace a newer one
+	late := interfaces.HookEvent{Name: "PreToolUse", AgentState: interfaces.AgentWorking, Time: event.Time.Add(-time.Second)}
+	if _, err := svc.RecordHookEvent(late, interfaces.HookOrigin{SessionID: api.ID}); err != nil {
+		t.Fatalf("RecordHookEvent failed: %v", err)
+	}
+	if got, _ := svc.GetSessionDetails("api"); got.AgentState != interfaces.AgentWaitingPermission {
+		t.Errorf("after a late hook: AgentState = %q, want %q", got.AgentState, interfaces.AgentWaitingPermission)
+	}
+
+	// Once Claude ended, the pane tells again
+	_ = fake.SetScreen("api", "● Waiting on you\n╭────╮\n│ >  │\n╰────╯\n")
+	end := interfaces.HookEvent{Name: "SessionEnd", Time: time.Now()}
+	if _, err := svc.RecordHookEvent(end, interfaces.HookOrigin{SessionID: api.ID}); err != nil {
+		t.Fatalf("RecordHookEvent failed: %v", err)
+	}
+	if got, _ := svc.GetSessionDetails("api"); got.AgentState != interfaces.AgentWaitingInput {
+		t.Errorf("after SessionEnd: AgentState = %q, want %q from the pane", got.AgentState, interfaces.AgentWaitingInput)
+	}
+
+	// Without a session ID, the working directory decides
+	event = interfaces.HookEvent{Name: "Stop", Cwd: filepath.Join(webDir, "src"), Time: time.Now()}
+	session, err := svc.RecordHookEvent(event, interfaces.HookOrigin{})
+	if err != nil || session.Name != "web" {
+		t.Errorf("hook in %s recorded in %v, err %v", event.Cwd, session, err)
+	}
+
+	event.Cwd = t.TempDir()
+	if _, err := svc.RecordHookEvent(event, interfaces.HookOrigin{}); err == nil {
+		t.Error("expected an error for a hook outside every session")
+	}
+}
+
+func TestRecordHookEventConcurrently(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	session, err := svc.CreateSession("api", "", "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// Claude runs each hook in a process of its own, while the user changes
+	// the session
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			event := interfaces.HookEvent{Name: "PreToolUse", AgentState: interfaces.AgentWorking, Time: time.Now()}
+			if _, err := svc.RecordHookEvent(event, interfaces.HookOrigin{SessionID: session.ID}); err != nil {
+				t.Errorf("RecordHookEvent failed: %v", err)
+			}
+		}()
+	}
+	if _, err := svc.SetMuted("api", true); err != nil {
+		t.Fatalf("SetMuted failed: %v", err)
+	}
+	wg.Wait()
+
+	got, err := svc.GetSession("api")
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if !got.Muted || got.LastHook == nil {
+		t.Errorf("Muted = %v, LastHook = %v after concurrent updates, want both kept", got.Muted, got.LastHook)
+	}
+}
+
+func TestSendInput(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	opts := interfaces.SendInputOptions{Keys: []string{"Escape"}, Enter: true}
+	if err := svc.SendInput("api", "Fix the tests\nthen commit", opts); err != nil {
+		t.Fatalf("SendInput failed: %v", err)
+	}
+
+	want := []string{"<Escape>", "Fix the tests\nthen commit", "<Enter>"}
+	if got := fake.Windows("api")[0].Panes[0].Input; !slices.Equal(got, want) {
+		t.Errorf("pane input = %q, want %q", got, want)
+	}
+
+	if err := svc.SendInput("api", "hello", interfaces.SendInputOptions{Target: "3"}); err == nil {
+		t.Error("expected an error for a missing target pane")
+	}
+
+	_ = fake.KillSession("api")
+	if err := svc.SendInput("api", "hello", interfaces.SendInputOptions{}); err == nil {
+		t.Error("expected an error for a stopped session")
+	}
+}
+
+func TestSetMutedKeepsStoredMetadata(t *testing.T) {
+	svc, fake := newTestService(t)
+	projectPath := t.TempDir()
+
+	session, err := svc.CreateSession("api", "", projectPath)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// A conversation with usage, read into the session when it is looked up
+	transcriptDir := claude.ProjectDir(svc.claudeDir, projectPath)
+	if err := os.MkdirAll(transcriptDir, 0755); err != nil {
+		t.Fatalf("failed to create transcript directory: %v", err)
+	}
+	const conversationID = "fa63cc01-aec7-4573-8adb-2ef2d7bae30a"
+	entry := `{"type":"assistant","timestamp":"2025-06-01T10:00:01Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":10}}}` + "\n"
+	if err := os.WriteFile(filepath.Join(transcriptDir, conversationID+".jsonl"), []byte(entry), 0644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	session.ConversationID = conversationID
+	if err := svc.repository.Save(session); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+	if got, _ := svc.GetSession("api"); got.Usage == nil {
+		t.Fatal("expected usage to be read from the transcript")
+	}
+
+	// Muting a session that stopped meanwhile stores neither its usage nor
+	// the status it was looked up with
+	_ = fake.KillSession("api")
+	if _, err := svc.SetMuted("api", true); err != nil {
+		t.Fatalf("SetMuted failed: %v", err)
+	}
+	stored, err := svc.repository.FindByID(session.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if !stored.Muted || stored.Usage != nil || stored.Status != interfaces.StatusActive {
+		t.Errorf("stored session: muted=%v usage=%+v status=%s", stored.Muted, stored.Usage, stored.Status)
+	}
+}
+
+func TestCreateSessionTags(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	if _, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "api", Tags: []string{"backend", " go ", "backend"}}); err != nil {
+		t.Fatalf("CreateSessionAdvanced failed: %v", err)
+	}
+	if got, _ := svc.GetSession("api"); !slices.Equal(got.Tags, []string{"backend", "go"}) {
+		t.Errorf("Tags = %q, want [backend go]", got.Tags)
+	}
+
+	if _, err := svc.CreateSession("web", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if targets, _ := svc.InputTargets("backend"); len(targets) != 1 || targets[0].Name != "api" {
+		t.Errorf("input targets tagged backend = %v, want api only", targets)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	_ = fake.SetScreen("api", "● Done.\n\n> \n\n\n")
+
+	screen, err := svc.Capture("api", interfaces.CaptureOptions{})
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	if screen != "● Done.\n\n>\n" {
+		t.Errorf("Capture = %q, want the screen without trailing blank lines", screen)
+	}
+
+	if _, err := svc.Capture("api", interfaces.CaptureOptions{Target: "2.1"}); err == nil {
+		t.Error("expected an error for a missing target pane")
+	}
+
+	_ = fake.KillSession("api")
+	if _, err := svc.Capture("api", interfaces.CaptureOptions{}); err == nil {
+		t.Error("expected an error for a stopped session")
+	}
+}
+
+func TestOutputLogging(t *testing.T) {
+	svc, fake := newTestService(t)
+	dir := t.TempDir()
+	svc.SetOutputLogging(OutputLogging{Enabled: true, Dir: dir, Archive: true})
+
+	off := false
+	quiet, err := svc.CreateSessionAdvanced(interfaces.CreateSessionRequest{Name: "quiet", LogOutput: &off})
+	if err != nil {
+		t.Fatalf("CreateSessionAdvanced failed: %v", err)
+	}
+	if quiet.LogFile != "" || fake.Windows("quiet")[0].Panes[0].Pipe != "" {
+		t.Errorf("session created with LogOutput false is logged")
+	}
+
+	session, err := svc.CreateSession("api", "", "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	wantLog := filepath.Join(dir, session.ID+".log")
+	if session.LogFile != wantLog {
+		t.Errorf("LogFile = %q, want %q", session.LogFile, wantLog)
+	}
+	pipe := fake.Windows("api")[0].Panes[0].Pipe
+	if !strings.Contains(pipe, outputlog.WriterCommand) || !strings.Contains(pipe, wantLog) {
+		t.Errorf("pane is piped to %q, want a log writer for %s", pipe, wantLog)
+	}
+
+	// Resuming continues the same log
+	_ = fake.KillSession("api")
+	if _, err := svc.ResumeSession("api"); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if pipe := fake.Windows("api")[0].Panes[0].Pipe; !strings.Contains(pipe, wantLog) {
+		t.Errorf("resumed pane is piped to %q, want a log writer for %s", pipe, wantLog)
+	}
+
+	if err := os.WriteFile(wantLog, []byte("output\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteSession("api"); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive", session.ID+".log")); err != nil {
+		t.Errorf("log of deleted session was not archived: %v", err)
+	}
+
+	// So is the log of a session pruned after its multiplexer session is gone
+	crashed, err := svc.CreateSession("crashed", "", "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := os.WriteFile(crashed.LogFile, []byte("output\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_ = fake.KillSession("crashed")
+	if _, err := svc.Reconcile(interfaces.ReconcileOptions{Prune: true}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive", crashed.ID+".log")); err != nil {
+		t.Errorf("log of pruned session was not archived: %v", err)
+	}
+}
+
+func TestRunHeadless(t *testing.T) {
+	svc, fake := newTestService(t)
+
+	if _, err := svc.RunHeadless(interfaces.RunRequest{Prompt: "hi"}); err == nil {
+		t.Error("RunHeadless without a runs directory succeeded")
+	}
+	svc.SetRunsDir(t.TempDir())
+
+	// claude is looked up in PATH
+	bin := t.TempDir()
+	script := "#!/bin/sh\ncat >/dev/null\necho '{\"type\":\"result\",\"result\":\"done\",\"session_id\":\"conv-1\"}'\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session, err := svc.RunHeadless(interfaces.RunRequest{Name: "batch", Prompt: "fix it", WorkingDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("RunHeadless failed: %v", err)
+	}
+	if muxSessions, _ := fake.ListSessions(); session.Backend != interfaces.HeadlessBackend || len(muxSessions) != 0 {
+		t.Errorf("run backend = %q with %d multiplexer sessions, want a headless run only", session.Backend, len(muxSessions))
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for session.Status == interfaces.StatusRunning && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		if session, err = svc.GetSession("batch"); err != nil {
+			t.Fatalf("GetSession failed: %v", err)
+		}
+	}
+	if session.Status != interfaces.StatusSucceeded || session.Run.Result != "done" || session.ConversationID != "conv-1" {
+		t.Errorf("finished run = %s %+v, want succeeded with its result and conversation", session.Status, session.Run)
+	}
+
+	// A run has no terminal, so input goes to the other sessions only
+	if _, err := svc.CreateSession("api", "", ""); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := svc.SendInput("batch", "hello", interfaces.SendInputOptions{}); err == nil {
+		t.Error("expected an error sending input to a headless run")
+	}
+	if _, err := svc.Capture("batch", interfaces.CaptureOptions{}); err == nil {
+		t.Error("expected an error capturing a headless run")
+	}
+	targets, err := svc.InputTargets("")
+	if err != nil {
+		t.Fatalf("InputTargets failed: %v", err)
+	}
+	if len(targets) != 1 || targets[0].Name != "api" {
+		t.Fatalf("input targets = %v, want api only", targets)
+	}
+	for _, target := range targets {
+		if err := svc.SendInput(target.ID, "hello", interfaces.SendInputOptions{Enter: true}); err != nil {
+			t.Errorf("broadcast to %s failed: %v", target.Name, err)
+		}
+	}
+
+	if err := svc.DeleteSession("batch"); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+}
+
+func TestFanout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	svc, fake := newTestService(t)
+	svc.SetWorktreesDir(t.TempDir())
+
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+
+	sessions, err := svc.Fanout(interfaces.FanoutRequest{Name: "task", WorkingDir: repo, Command: "my-claude", Prompt: "Fix it's bug", Count: 2})
+	if err != nil {
+		t.Fatalf("Fanout failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Fanout created %d sessions, want 2", len(sessions))
+	}
+	for i, session := range sessions {
+		name := fmt.Sprintf("task-%d", i+1)
+		if session.Name != name || session.Group != "task" || session.Worktree == nil || session.Worktree.Branch != name {
+			t.Errorf("session %d = %s in group %q with worktree %+v, want %s on its own branch", i, session.Name, session.Group, session.Worktree, name)
+			continue
+		}
+		pane := fake.Windows(name)[0].Panes[0]
+		if pane.Command != `my-claude -- 'Fix it'\''s bug'` || pane.WorkingDir != session.Worktree.Path {
+			t.Errorf("pane runs %q in %s, want the command with the prompt in the worktree", pane.Command, pane.WorkingDir)
+		}
+	}
+
+	if _, err := svc.Fanout(interfaces.FanoutRequest{Name: "task", WorkingDir: repo, Prompt: "again", Count: 1}); err == nil {
+		t.Error("Fanout reused an existing group")
+	}
+	if members, _ := svc.ListGroupSessions("task"); len(members) != 2 {
+		t.Errorf("ListGroupSessions returned %d sessions, want 2", len(members))
+	}
+
+	// Killed sessions leave their branches, which a new fanout must not reuse
+	for _, session := range sessions {
+		if err := svc.DeleteSession(session.ID); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+	}
+	if _, err := svc.Fanout(interfaces.FanoutRequest{Name: "task", WorkingDir: repo, Prompt: "again", Count: 3}); err == nil || !strings.Contains(err.Error(), "task-1") {
+		t.Errorf("Fanout over existing branches = %v, want an error naming the branch", err)
+	}
+	if sessions, _ := svc.ListSessions(); len(sessions) != 0 {
+		t.Errorf("Fanout over existing branches created %d sessions", len(sessions))
+	}
+}
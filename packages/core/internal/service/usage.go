@@ -0,0 +1,100 @@
+package service
+
+import (
+	"os"
+	"time"
+
+	"claude-pilot/core/internal/claude"
+	"claude-pilot/shared/interfaces"
+)
+
+// usageCacheEntry holds the usage read from a transcript as of its last change
+type usageCacheEntry struct {
+	modTime time.Time
+	size    int64
+	records []interfaces.UsageRecord
+}
+
+// SetPricing sets the price table used to cost token usage and the context
+// window used for the context fill percentage
+func (s *SessionService) SetPricing(prices claude.PriceTable, contextWindow int64) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+
+	s.prices = prices
+	if contextWindow > 0 {
+		s.contextWindow = contextWindow
+	}
+	s.usageCache = make(map[string]usageCacheEntry)
+}
+
+// GetUsage returns the usage of each API request in the Claude conversation
+// of a session. Sessions whose conversation has not started have no usage.
+func (s *SessionService) GetUsage(identifier string) ([]interfaces.UsageRecord, error) {
+	session, err := s.GetSession(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.usageRecords(s.withConversation(session))
+}
+
+// loadUsage totals the usage of a session's conversation into session.Usage
+func (s *SessionService) loadUsage(session *interfaces.Session) {
+	if session.ConversationID == "" {
+		return
+	}
+
+	records, err := s.usageRecords(session)
+	if err != nil {
+		s.logger.Debug("Failed to read conversation usage",
+			"session_id", session.ID,
+			"conversation_id", session.ConversationID,
+			"error", err)
+		return
+	}
+
+	s.usageMu.Lock()
+	contextWindow := s.contextWindow
+	s.usageMu.Unlock()
+
+	session.Usage = claude.SummarizeUsage(records, contextWindow)
+}
+
+// usageRecords reads and prices the usage in a session's transcript. Results
+// are cached until the transcript changes, as transcripts grow large and
+// sessions are listed on every TUI refresh.
+func (s *SessionService) usageRecords(session *interfaces.Session) ([]interfaces.UsageRecord, error) {
+	if session.ConversationID == "" {
+		return nil, nil
+	}
+
+	path := claude.TranscriptPath(s.claudeDir, session.ProjectPath, session.ConversationID)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.usageMu.Lock()
+	cached, ok := s.usageCache[path]
+	prices := s.prices
+	s.usageMu.Unlock()
+
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.records, nil
+	}
+
+	records, err := claude.ReadUsage(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Cost = prices.Cost(records[i].Model, records[i].TokenUsage)
+	}
+
+	s.usageMu.Lock()
+	s.usageCache[path] = usageCacheEntry{modTime: info.ModTime(), size: info.Size(), records: records}
+	s.usageMu.Unlock()
+
+	return records, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"claude-pilot/core/internal/worktree"
+	"claude-pilot/shared/interfaces"
+)
+
+// SetWorktreesDir sets the directory that git worktrees of sessions are
+// created in; without it sessions cannot be created with a worktree
+func (s *SessionService) SetWorktreesDir(dir string) {
+	s.worktreesDir = dir
+}
+
+// createWorktree creates the worktree a new session runs in and returns it
+// with the session's working directory in it
+func (s *SessionService) createWorktree(req interfaces.CreateSessionRequest) (*interfaces.Worktree, string, error) {
+	if s.worktreesDir == "" {
+		return nil, "", fmt.Errorf("worktrees are not configured")
+	}
+
+	branch := req.Branch
+	if branch == "" {
+		branch = req.Name
+	}
+	workingDir := req.WorkingDir
+	if workingDir == "" {
+		var err error
+		if workingDir, err = os.Getwd(); err != nil {
+			return nil, "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+
+	wt, dir, err := worktree.Create(workingDir, s.worktreesDir, req.Name, branch)
+	if err != nil {
+		s.logger.Error("Failed to create worktree", "name", req.Name, "branch", branch, "error", err)
+		return nil, "", err
+	}
+
+	s.logger.Info("Worktree created", "name", req.Name, "repo", wt.Repo, "branch", wt.Branch, "path", wt.Path)
+	return wt, dir, nil
+}
+
+// RemoveWorktree removes the git worktree of a session, keeping its branch.
+// Without force it refuses when the worktree has uncommitted changes.
+func (s *SessionService) RemoveWorktree(wt *interfaces.Worktree, force bool) error {
+	if err := worktree.Remove(wt, force); err != nil {
+		s.logger.Warn("Failed to remove worktree", "path", wt.Path, "error", err)
+		return err
+	}
+
+	s.logger.Info("Worktree removed", "path", wt.Path, "branch", wt.Branch)
+	return nil
+}
+
+// WorktreeDiff summarizes the changes made in a session's worktree since it
+// was created
+func (s *SessionService) WorktreeDiff(wt *interfaces.Worktree) (*interfaces.DiffStats, error) {
+	return worktree.Diff(wt)
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sync"
+	"syscall"
 
 	"claude-pilot/shared/interfaces"
 	"claude-pilot/core/internal/utils"
@@ -71,6 +72,45 @@ func (r *FileSessionRepository) Save(session *interfaces.Session) error {
 	return nil
 }
 
+// Update changes a stored session while holding a lock on the sessions
+// directory, which other processes take for their updates as well
+func (r *FileSessionRepository) Update(id string, update func(*interfaces.Session)) (*interfaces.Session, error) {
+	unlock, err := r.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := r.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update(session)
+	if err := r.Save(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// lock takes the lock file of the sessions directory, returning the function
+// that releases it
+func (r *FileSessionRepository) lock() (func(), error) {
+	file, err := os.OpenFile(filepath.Join(r.sessionsDir, ".lock"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock sessions: %w", err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		_ = file.Close()
+	}, nil
+}
+
 // FindByID retrieves a session by its unique ID
 func (r *FileSessionRepository) FindByID(id string) (*interfaces.Session, error) {
 	sessionFile := filepath.Join(r.sessionsDir, id+".json")
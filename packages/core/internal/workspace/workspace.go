@@ -0,0 +1,116 @@
+// Package workspace reads the workspace file a repository commits to declare
+// the sessions to run in it, so that they can be brought up with one command.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"claude-pilot/core/internal/config"
+
+	"github.com/spf13/viper"
+)
+
+// FileName is the name of workspace files
+const FileName = ".claude-pilot.yaml"
+
+// Workspace is the set of sessions declared by a workspace file
+type Workspace struct {
+	// Path is the absolute path of the workspace file
+	Path string `mapstructure:"-"`
+
+	// Sessions in the order they are brought up
+	Sessions []Session `mapstructure:"sessions"`
+}
+
+// Session is a session declared in a workspace file. It has the fields of a
+// session template, with working_dir relative to the workspace file, and may
+// start from a template of the user's config whose values it overrides.
+type Session struct {
+	Name     string `mapstructure:"name"`
+	Template string `mapstructure:"template"`
+
+	config.SessionTemplate `mapstructure:",squash"`
+}
+
+// Dir returns the directory of the workspace file
+func (w *Workspace) Dir() string {
+	return filepath.Dir(w.Path)
+}
+
+// Find returns the path of the workspace file in dir or the closest of its
+// parent directories
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in %s or its parent directories", FileName, dir)
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates a workspace file
+func Load(path string) (*Workspace, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	ws := &Workspace{Path: path}
+	if err := v.Unmarshal(ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace file: %w", err)
+	}
+	if err := ws.validate(); err != nil {
+		return nil, fmt.Errorf("invalid workspace file %s: %w", path, err)
+	}
+	return ws, nil
+}
+
+// validate checks that sessions have unique names and that panes and windows
+// are attached to a standalone session declared before them
+func (w *Workspace) validate() error {
+	if len(w.Sessions) == 0 {
+		return fmt.Errorf("no sessions declared")
+	}
+
+	standalone := make(map[string]bool)
+	for i, session := range w.Sessions {
+		if session.Name == "" {
+			return fmt.Errorf("session %d has no name", i+1)
+		}
+		if _, ok := standalone[session.Name]; ok {
+			return fmt.Errorf("session '%s' is declared twice", session.Name)
+		}
+		if err := session.Validate(); err != nil {
+			return fmt.Errorf("session '%s': %w", session.Name, err)
+		}
+		if session.AttachTo != "" && !standalone[session.AttachTo] {
+			return fmt.Errorf("session '%s' is attached to '%s', which is not a standalone session declared before it", session.Name, session.AttachTo)
+		}
+		if session.AttachTo != "" && session.Worktree {
+			return fmt.Errorf("session '%s': worktrees can only be created for standalone sessions", session.Name)
+		}
+		if session.AttachTo != "" && len(session.Panes) > 0 {
+			return fmt.Errorf("session '%s': panes can only be added to standalone sessions", session.Name)
+		}
+		standalone[session.Name] = session.AttachTo == ""
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindAndLoad(t *testing.T) {
+	repo := t.TempDir()
+	path := filepath.Join(repo, FileName)
+	content := `
+sessions:
+  - name: api
+    working_dir: api
+    model: opus
+    env: [PORT=8080]
+  - name: api-tests
+    attach_to: api
+    split: h
+    command: go test ./...
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "api", "handlers")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := Find(sub)
+	if err != nil || found != path {
+		t.Fatalf("Find() = %q, %v, want %q", found, err, path)
+	}
+
+	ws, err := Load(found)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if ws.Dir() != repo || len(ws.Sessions) != 2 {
+		t.Fatalf("Load() = %+v, want 2 sessions in %s", ws, repo)
+	}
+	api, tests := ws.Sessions[0], ws.Sessions[1]
+	if api.Name != "api" || api.WorkingDir != "api" || api.Model != "opus" || len(api.Env) != 1 {
+		t.Errorf("unexpected session: %+v", api)
+	}
+	if tests.AttachTo != "api" || tests.Split != "h" || tests.Command != "go test ./..." {
+		t.Errorf("unexpected attached session: %+v", tests)
+	}
+}
+
+func TestLoadRejectsInvalidSessions(t *testing.T) {
+	for name, content := range map[string]string{
+		"no sessions":      "sessions: []",
+		"no name":          "sessions: [{model: opus}]",
+		"duplicate":        "sessions: [{name: api}, {name: api}]",
+		"attached to pane": "sessions: [{name: api}, {name: tests, attach_to: api}, {name: logs, attach_to: tests}]",
+		"attached later":   "sessions: [{name: tests, attach_to: api}, {name: api}]",
+		"invalid split":    "sessions: [{name: api}, {name: tests, attach_to: api, split: x}]",
+	} {
+		path := filepath.Join(t.TempDir(), FileName)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package worktree creates and removes the git worktrees that sessions run
+// in, so that agents working on the same repository each have their own
+// working tree and branch.
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// ErrDirty is returned when removing a worktree with uncommitted changes
+var ErrDirty = errors.New("worktree has uncommitted changes")
+
+// Create adds a worktree of the repository containing workingDir at
+// <dir>/<repository>/<name>, on branch. An existing branch is checked out,
+// any other is created from the repository's current HEAD. It returns the
+// worktree and the directory in it that corresponds to workingDir.
+func Create(workingDir, dir, name, branch string) (*interfaces.Worktree, string, error) {
+	commonDir, err := git(workingDir, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return nil, "", fmt.Errorf("'%s' is not in a git repository: %w", workingDir, err)
+	}
+	prefix, err := git(workingDir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The repository is the main working tree, or the bare repository itself
+	repo := commonDir
+	if filepath.Base(commonDir) == ".git" {
+		repo = filepath.Dir(commonDir)
+	}
+
+	path := filepath.Join(dir, filepath.Base(repo), name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, "", fmt.Errorf("worktree path '%s' already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, "", fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+
+	args := []string{"worktree", "add", path, branch}
+	if _, err := git(repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		args = []string{"worktree", "add", "-b", branch, path}
+	}
+	if _, err := git(repo, args...); err != nil {
+		return nil, "", fmt.Errorf("failed to create worktree: %w", err)
+	}
+	base, err := git(path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read worktree HEAD: %w", err)
+	}
+
+	wt := &interfaces.Worktree{Repo: repo, Branch: branch, Path: path, Base: base}
+	return wt, filepath.Join(path, prefix), nil
+}
+
+// BranchExists reports whether the repository containing workingDir has a
+// branch of the given name
+func BranchExists(workingDir, branch string) (bool, error) {
+	if _, err := git(workingDir, "rev-parse", "--git-dir"); err != nil {
+		return false, fmt.Errorf("'%s' is not in a git repository: %w", workingDir, err)
+	}
+	_, err := git(workingDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil, nil
+}
+
+// Dirty reports whether a worktree has uncommitted changes or untracked files
+func Dirty(wt *interfaces.Worktree) (bool, error) {
+	status, err := git(wt.Path, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to read worktree status: %w", err)
+	}
+	return status != "", nil
+}
+
+// Diff summarizes the changes in a worktree since its base commit, both
+// committed and not. Worktrees without a base only count uncommitted changes.
+func Diff(wt *interfaces.Worktree) (*interfaces.DiffStats, error) {
+	base := wt.Base
+	if base == "" {
+		base = "HEAD"
+	}
+
+	stats := &interfaces.DiffStats{}
+	commits, err := git(wt.Path, "rev-list", "--count", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count worktree commits: %w", err)
+	}
+	stats.Commits, _ = strconv.Atoi(commits)
+
+	numstat, err := git(wt.Path, "diff", "--numstat", base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff worktree: %w", err)
+	}
+	for _, line := range strings.Split(numstat, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		// Binary files count as changed without lines
+		stats.Files++
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		stats.Insertions += added
+		stats.Deletions += deleted
+	}
+
+	untracked, err := git(wt.Path, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	if untracked != "" {
+		stats.Untracked = len(strings.Split(untracked, "\n"))
+	}
+	return stats, nil
+}
+
+// Remove deletes a worktree, keeping its branch. Without force it refuses
+// with ErrDirty when the worktree has uncommitted changes.
+func Remove(wt *interfaces.Worktree, force bool) error {
+	if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+		// Already gone, only git's record of it is left
+		_, err := git(wt.Repo, "worktree", "prune")
+		return err
+	}
+
+	args := []string{"worktree", "remove", wt.Path}
+	if force {
+		args = []string{"worktree", "remove", "--force", wt.Path}
+	} else if dirty, err := Dirty(wt); err != nil {
+		return err
+	} else if dirty {
+		return fmt.Errorf("%w: %s", ErrDirty, wt.Path)
+	}
+
+	if _, err := git(wt.Repo, args...); err != nil {
+		return fmt.Errorf("failed to remove worktree: %w", err)
+	}
+	return nil
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return "", fmt.Errorf("%w: %s", err, text)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,105 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// newRepo creates a repository with one commit and a subdirectory
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(filepath.Join(repo, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "api", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+		{"branch", "existing"},
+	} {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	return repo
+}
+
+func TestCreateAndRemove(t *testing.T) {
+	repo := newRepo(t)
+	dir := t.TempDir()
+
+	// Created from a subdirectory, the session starts in the same one
+	wt, workingDir, err := Create(filepath.Join(repo, "api"), dir, "feature", "feature-x")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	wantPath := filepath.Join(dir, "app", "feature")
+	if wt.Branch != "feature-x" || wt.Path != wantPath || workingDir != filepath.Join(wantPath, "api") {
+		t.Errorf("Create() = %+v in %s, want branch feature-x at %s", wt, workingDir, wantPath)
+	}
+	if branch, _ := git(wt.Path, "branch", "--show-current"); branch != "feature-x" {
+		t.Errorf("worktree is on branch %q, want feature-x", branch)
+	}
+
+	// Existing branches are checked out rather than created
+	other, _, err := Create(repo, dir, "other", "existing")
+	if err != nil {
+		t.Fatalf("Create with an existing branch failed: %v", err)
+	}
+	if _, _, err := Create(repo, dir, "other", "another"); err == nil {
+		t.Error("Create succeeded with a path that already exists")
+	}
+	if err := Remove(other, false); err != nil {
+		t.Errorf("Remove of a clean worktree failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(wt.Path, "notes.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, "api", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err := Diff(wt); err != nil || *stats != (interfaces.DiffStats{Files: 1, Insertions: 2, Untracked: 1}) {
+		t.Errorf("Diff() = %+v, %v, want one changed file with 2 insertions and one untracked file", stats, err)
+	}
+	if err := Remove(wt, false); !errors.Is(err, ErrDirty) {
+		t.Errorf("Remove of a dirty worktree = %v, want ErrDirty", err)
+	}
+	if err := Remove(wt, true); err != nil {
+		t.Errorf("forced Remove failed: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists after Remove")
+	}
+
+	// The branch outlives its worktree
+	if _, err := git(repo, "rev-parse", "--verify", "refs/heads/feature-x"); err != nil {
+		t.Errorf("branch was removed with its worktree: %v", err)
+	}
+}
+
+func TestBranchExists(t *testing.T) {
+	repo := newRepo(t)
+
+	for branch, want := range map[string]bool{"existing": true, "missing": false} {
+		if exists, err := BranchExists(filepath.Join(repo, "api"), branch); err != nil || exists != want {
+			t.Errorf("BranchExists(%q) = %v, %v, want %v", branch, exists, err, want)
+		}
+	}
+	if _, err := BranchExists(t.TempDir(), "existing"); err == nil {
+		t.Error("BranchExists succeeded outside a repository")
+	}
+}
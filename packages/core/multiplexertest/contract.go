@@ -0,0 +1,322 @@
+package multiplexertest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// Contract is the behaviour every interfaces.TerminalMultiplexer backend must
+// provide. Backends run it from their own tests:
+//
+//	multiplexertest.Contract{New: newBackend, SupportsPanes: true, SupportsWindows: true}.Run(t)
+type Contract struct {
+	// New returns the backend under test. Sessions created by the suite are
+	// killed when each subtest finishes.
+	New func(t *testing.T) interfaces.TerminalMultiplexer
+
+	// Command is a long-running command used for sessions (default "sleep 600")
+	Command string
+
+	// SupportsPanes reports whether sessions can be attached as panes
+	SupportsPanes bool
+
+	// SupportsWindows reports whether sessions can be attached as windows
+	SupportsWindows bool
+}
+
+// sessionCounter keeps session names unique across subtests and backends
+var sessionCounter atomic.Int64
+
+// Run runs the contract suite as subtests of t
+func (c Contract) Run(t *testing.T) {
+	t.Helper()
+
+	if c.Command == "" {
+		c.Command = "sleep 600"
+	}
+
+	t.Run("Identity", c.testIdentity)
+	t.Run("CreateAndGet", c.testCreateAndGet)
+	t.Run("ListSessions", c.testListSessions)
+	t.Run("DuplicateName", c.testDuplicateName)
+	t.Run("KillSession", c.testKillSession)
+	t.Run("MissingSession", c.testMissingSession)
+	t.Run("PaneAttachment", c.testPaneAttachment)
+	t.Run("WindowAttachment", c.testWindowAttachment)
+	t.Run("MissingAttachTarget", c.testMissingAttachTarget)
+}
+
+// newSession creates a standalone session that is killed when the test ends
+func (c Contract) newSession(t *testing.T, mux interfaces.TerminalMultiplexer) interfaces.CreateSessionRequest {
+	t.Helper()
+
+	req := interfaces.CreateSessionRequest{
+		Name:        fmt.Sprintf("contract-%d-%d", time.Now().UnixNano()%100000, sessionCounter.Add(1)),
+		Description: "contract test session",
+		WorkingDir:  t.TempDir(),
+		Command:     c.Command,
+	}
+
+	session, err := mux.CreateSession(req)
+	if err != nil {
+		t.Fatalf("CreateSession(%q) failed: %v", req.Name, err)
+	}
+	if session == nil {
+		t.Fatalf("CreateSession(%q) returned a nil session", req.Name)
+	}
+
+	t.Cleanup(func() {
+		if mux.HasSession(req.Name) {
+			_ = mux.KillSession(req.Name)
+		}
+	})
+
+	return req
+}
+
+// paneCount returns the pane count of a session, failing the test on error
+func paneCount(t *testing.T, mux interfaces.TerminalMultiplexer, name string) int {
+	t.Helper()
+
+	count, err := mux.GetSessionPaneCount(name)
+	if err != nil {
+		t.Fatalf("GetSessionPaneCount(%q) failed: %v", name, err)
+	}
+	return count
+}
+
+func (c Contract) testIdentity(t *testing.T) {
+	mux := c.New(t)
+
+	if mux.GetName() == "" {
+		t.Error("GetName returned an empty backend name")
+	}
+	if !mux.IsAvailable() {
+		t.Error("IsAvailable returned false for the backend under test")
+	}
+}
+
+func (c Contract) testCreateAndGet(t *testing.T) {
+	mux := c.New(t)
+	req := c.newSession(t, mux)
+
+	if !mux.HasSession(req.Name) {
+		t.Errorf("HasSession(%q) = false after creation", req.Name)
+	}
+	if !mux.IsSessionRunning(req.Name) {
+		t.Errorf("IsSessionRunning(%q) = false after creation", req.Name)
+	}
+
+	session, err := mux.GetSession(req.Name)
+	if err != nil {
+		t.Fatalf("GetSession(%q) failed: %v", req.Name, err)
+	}
+	if session.GetName() != req.Name {
+		t.Errorf("GetSession returned name %q, want %q", session.GetName(), req.Name)
+	}
+	if session.IsAttached() {
+		t.Error("new session reported as attached")
+	}
+	if session.GetCreatedAt().IsZero() {
+		t.Error("new session has a zero creation time")
+	}
+
+	if count := paneCount(t, mux, req.Name); count != 1 {
+		t.Errorf("new session has %d panes, want 1", count)
+	}
+}
+
+func (c Contract) testListSessions(t *testing.T) {
+	mux := c.New(t)
+	first := c.newSession(t, mux)
+	second := c.newSession(t, mux)
+
+	sessions, err := mux.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, session := range sessions {
+		found[session.GetName()] = true
+	}
+	for _, name := range []string{first.Name, second.Name} {
+		if !found[name] {
+			t.Errorf("ListSessions did not include %q", name)
+		}
+	}
+}
+
+func (c Contract) testDuplicateName(t *testing.T) {
+	mux := c.New(t)
+	req := c.newSession(t, mux)
+
+	if _, err := mux.CreateSession(req); err == nil {
+		t.Errorf("creating a second session named %q succeeded, want error", req.Name)
+	}
+	if !mux.HasSession(req.Name) {
+		t.Errorf("original session %q disappeared after duplicate create", req.Name)
+	}
+}
+
+func (c Contract) testKillSession(t *testing.T) {
+	mux := c.New(t)
+	req := c.newSession(t, mux)
+
+	if err := mux.KillSession(req.Name); err != nil {
+		t.Fatalf("KillSession(%q) failed: %v", req.Name, err)
+	}
+
+	if mux.HasSession(req.Name) {
+		t.Errorf("HasSession(%q) = true after kill", req.Name)
+	}
+	if mux.IsSessionRunning(req.Name) {
+		t.Errorf("IsSessionRunning(%q) = true after kill", req.Name)
+	}
+	if _, err := mux.GetSession(req.Name); err == nil {
+		t.Errorf("GetSession(%q) succeeded after kill", req.Name)
+	}
+
+	sessions, err := mux.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	for _, session := range sessions {
+		if session.GetName() == req.Name {
+			t.Errorf("ListSessions still includes killed session %q", req.Name)
+		}
+	}
+}
+
+func (c Contract) testMissingSession(t *testing.T) {
+	mux := c.New(t)
+	name := fmt.Sprintf("contract-missing-%d", sessionCounter.Add(1))
+
+	if mux.HasSession(name) {
+		t.Errorf("HasSession(%q) = true for a session that was never created", name)
+	}
+	if mux.IsSessionRunning(name) {
+		t.Errorf("IsSessionRunning(%q) = true for a session that was never created", name)
+	}
+	if _, err := mux.GetSession(name); err == nil {
+		t.Errorf("GetSession(%q) succeeded for a session that was never created", name)
+	}
+	if err := mux.KillSession(name); err == nil {
+		t.Errorf("KillSession(%q) succeeded for a session that was never created", name)
+	}
+	if _, err := mux.GetSessionPaneCount(name); err == nil {
+		t.Errorf("GetSessionPaneCount(%q) succeeded for a session that was never created", name)
+	}
+}
+
+func (c Contract) testPaneAttachment(t *testing.T) {
+	mux := c.New(t)
+	target := c.newSession(t, mux)
+
+	req := interfaces.CreateSessionRequest{
+		Name:           "pane",
+		WorkingDir:     target.WorkingDir,
+		Command:        c.Command,
+		AttachTo:       target.Name,
+		AttachmentType: interfaces.AttachmentPane,
+		SplitDirection: interfaces.SplitVertical,
+	}
+
+	attached, err := mux.CreateSession(req)
+	if !c.SupportsPanes {
+		if err == nil {
+			t.Error("attaching as a pane succeeded on a backend that does not support panes")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("attaching as a pane failed: %v", err)
+	}
+
+	if count := paneCount(t, mux, target.Name); count != 2 {
+		t.Errorf("session has %d panes after adding a pane, want 2", count)
+	}
+	closeTarget(t, mux, target.Name, attached)
+}
+
+func (c Contract) testWindowAttachment(t *testing.T) {
+	mux := c.New(t)
+	target := c.newSession(t, mux)
+
+	req := interfaces.CreateSessionRequest{
+		Name:           "window",
+		WorkingDir:     target.WorkingDir,
+		Command:        c.Command,
+		AttachTo:       target.Name,
+		AttachmentType: interfaces.AttachmentWindow,
+	}
+
+	attached, err := mux.CreateSession(req)
+	if !c.SupportsWindows {
+		if err == nil {
+			t.Error("attaching as a window succeeded on a backend that does not support windows")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("attaching as a window failed: %v", err)
+	}
+
+	if !mux.IsSessionRunning(target.Name) {
+		t.Errorf("session %q is not running after adding a window", target.Name)
+	}
+	closeTarget(t, mux, target.Name, attached)
+}
+
+// closeTarget closes an attached pane or window on backends that are
+// TargetClosers, checking that the rest of the session is left
+func closeTarget(t *testing.T, mux interfaces.TerminalMultiplexer, name string, attached interfaces.MultiplexerSession) {
+	t.Helper()
+
+	closer, ok := mux.(interfaces.TargetCloser)
+	if !ok {
+		return
+	}
+
+	target := attached.GetID()
+	if !closer.HasTarget(name, target) {
+		t.Fatalf("HasTarget(%q, %q) = false for the attachment just created", name, target)
+	}
+	if err := closer.CloseTarget(name, target); err != nil {
+		t.Fatalf("CloseTarget(%q, %q) failed: %v", name, target, err)
+	}
+	if closer.HasTarget(name, target) {
+		t.Errorf("HasTarget(%q, %q) = true after CloseTarget", name, target)
+	}
+	if count := paneCount(t, mux, name); count != 1 {
+		t.Errorf("session has %d panes after closing the attachment, want 1", count)
+	}
+}
+
+func (c Contract) testMissingAttachTarget(t *testing.T) {
+	if !c.SupportsPanes && !c.SupportsWindows {
+		t.Skip("backend supports no attachments")
+	}
+
+	mux := c.New(t)
+	attachmentType := interfaces.AttachmentWindow
+	if c.SupportsPanes {
+		attachmentType = interfaces.AttachmentPane
+	}
+
+	req := interfaces.CreateSessionRequest{
+		Name:           "orphan",
+		Command:        c.Command,
+		AttachTo:       fmt.Sprintf("contract-missing-%d", sessionCounter.Add(1)),
+		AttachmentType: attachmentType,
+		SplitDirection: interfaces.SplitVertical,
+	}
+
+	if _, err := mux.CreateSession(req); err == nil {
+		t.Errorf("attaching to missing session %q succeeded, want error", req.AttachTo)
+	}
+}
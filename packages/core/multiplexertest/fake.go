@@ -0,0 +1,622 @@
+// Package multiplexertest provides an in-memory terminal multiplexer for tests
+// and a contract test suite that every multiplexer backend must pass.
+package multiplexertest
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"claude-pilot/shared/interfaces"
+)
+
+// FakePane is a pane inside a fake window
+type FakePane struct {
+	ID         string // e.g. %3, unique across sessions like tmux's pane IDs
+	Command    string
+	WorkingDir string
+	Env        map[string]string
+	Screen     string   // What CapturePane returns for the pane
+	Input      []string // Text sent to the pane, with keys as "<Key>"
+	Pipe       string   // Command the pane's output is piped to
+}
+
+// FakeWindow is a window inside a fake session
+type FakeWindow struct {
+	ID    string // e.g. @2
+	Name  string
+	Panes []FakePane
+}
+
+// FakeMultiplexer is an in-memory implementation of interfaces.TerminalMultiplexer.
+// It models sessions, windows, panes and attach state without running any
+// processes, and is safe for concurrent use.
+type FakeMultiplexer struct {
+	// OnAttach, when set, is called by AttachToSession while the session is
+	// marked as attached, standing in for the blocking interactive attach
+	OnAttach func(name string) error
+
+	mu        sync.Mutex
+	name      string
+	available bool
+	sessions  map[string]*fakeSession
+	failures  map[string]error
+	now       func() time.Time
+	lastID    int
+}
+
+// fakeSession is the state of a single fake session
+type fakeSession struct {
+	name        string
+	description string
+	workingDir  string
+	createdAt   time.Time
+	windows     []FakeWindow
+	clients     int
+	attachCount int
+}
+
+// NewFakeMultiplexer creates an empty, available fake multiplexer named "fake"
+func NewFakeMultiplexer() *FakeMultiplexer {
+	return &FakeMultiplexer{
+		name:      "fake",
+		available: true,
+		sessions:  make(map[string]*fakeSession),
+		failures:  make(map[string]error),
+		now:       time.Now,
+	}
+}
+
+// SetName changes the backend name reported by GetName
+func (f *FakeMultiplexer) SetName(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.name = name
+}
+
+// SetAvailable changes the result of IsAvailable
+func (f *FakeMultiplexer) SetAvailable(available bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.available = available
+}
+
+// SetClock replaces the clock used for session creation times
+func (f *FakeMultiplexer) SetClock(now func() time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// FailOn makes the named method (e.g. "CreateSession") return err until it is
+// cleared by passing a nil error
+func (f *FakeMultiplexer) FailOn(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = err
+}
+
+// SetAttached simulates another client attaching to or detaching from a session
+func (f *FakeMultiplexer) SetAttached(name string, attached bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return fmt.Errorf("fake session '%s' not found", name)
+	}
+
+	if attached {
+		session.clients++
+	} else if session.clients > 0 {
+		session.clients--
+	}
+	return nil
+}
+
+// SetScreen sets what CapturePane returns for the first pane of a session
+func (f *FakeMultiplexer) SetScreen(name, screen string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return fmt.Errorf("fake session '%s' not found", name)
+	}
+	session.windows[0].Panes[0].Screen = screen
+	return nil
+}
+
+// Windows returns a copy of the windows and panes of a session
+func (f *FakeMultiplexer) Windows(name string) []FakeWindow {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return nil
+	}
+
+	windows := make([]FakeWindow, len(session.windows))
+	for i, window := range session.windows {
+		windows[i] = FakeWindow{ID: window.ID, Name: window.Name, Panes: append([]FakePane(nil), window.Panes...)}
+	}
+	return windows
+}
+
+// AttachCount returns how many times AttachToSession succeeded for a session
+func (f *FakeMultiplexer) AttachCount(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if session, ok := f.sessions[name]; ok {
+		return session.attachCount
+	}
+	return 0
+}
+
+// GetName returns the name of the multiplexer backend
+func (f *FakeMultiplexer) GetName() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.name
+}
+
+// IsAvailable reports whether the fake is available
+func (f *FakeMultiplexer) IsAvailable() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.available
+}
+
+// CreateSession creates a session, or a pane or window in an existing one
+func (f *FakeMultiplexer) CreateSession(req interfaces.CreateSessionRequest) (interfaces.MultiplexerSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["CreateSession"]; err != nil {
+		return nil, err
+	}
+
+	command := req.Command
+	if command == "" {
+		command = "claude"
+	}
+	pane := FakePane{ID: f.newID("%"), Command: command, WorkingDir: req.WorkingDir, Env: req.Env}
+
+	if req.AttachTo != "" && req.AttachmentType != interfaces.AttachmentNone {
+		target, ok := f.sessions[req.AttachTo]
+		if !ok {
+			return nil, fmt.Errorf("target session '%s' does not exist", req.AttachTo)
+		}
+
+		// The view's ID is the pane or window, as with tmux
+		var id string
+		switch req.AttachmentType {
+		case interfaces.AttachmentPane:
+			// Panes split the most recently created window, which tmux treats as current
+			last := len(target.windows) - 1
+			target.windows[last].Panes = append(target.windows[last].Panes, pane)
+			id = pane.ID
+		case interfaces.AttachmentWindow:
+			id = f.newID("@")
+			target.windows = append(target.windows, FakeWindow{ID: id, Name: req.Name, Panes: []FakePane{pane}})
+		default:
+			return nil, fmt.Errorf("unsupported attachment type: %s", req.AttachmentType)
+		}
+
+		return &fakeSessionView{
+			id:          id,
+			name:        fmt.Sprintf("%s-attached-to-%s", req.Name, req.AttachTo),
+			createdAt:   f.now(),
+			workingDir:  req.WorkingDir,
+			description: fmt.Sprintf("%s (attached as %s to %s)", req.Description, req.AttachmentType, req.AttachTo),
+		}, nil
+	}
+
+	if _, exists := f.sessions[req.Name]; exists {
+		return nil, fmt.Errorf("fake session '%s' already exists", req.Name)
+	}
+
+	pane.Pipe = req.OutputPipe
+	session := &fakeSession{
+		name:        req.Name,
+		description: req.Description,
+		workingDir:  req.WorkingDir,
+		createdAt:   f.now(),
+		windows:     []FakeWindow{{ID: f.newID("@"), Name: req.Name, Panes: []FakePane{pane}}},
+	}
+	f.sessions[req.Name] = session
+
+	return session.view(), nil
+}
+
+// GetSession retrieves session information by name
+func (f *FakeMultiplexer) GetSession(name string) (interfaces.MultiplexerSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["GetSession"]; err != nil {
+		return nil, err
+	}
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return nil, fmt.Errorf("fake session '%s' not found", name)
+	}
+	return session.view(), nil
+}
+
+// ListSessions returns all sessions ordered by creation time
+func (f *FakeMultiplexer) ListSessions() ([]interfaces.MultiplexerSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["ListSessions"]; err != nil {
+		return nil, err
+	}
+
+	views := make([]*fakeSessionView, 0, len(f.sessions))
+	for _, session := range f.sessions {
+		views = append(views, session.view())
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		if !views[i].createdAt.Equal(views[j].createdAt) {
+			return views[i].createdAt.Before(views[j].createdAt)
+		}
+		return views[i].name < views[j].name
+	})
+
+	sessions := make([]interfaces.MultiplexerSession, len(views))
+	for i, view := range views {
+		sessions[i] = view
+	}
+	return sessions, nil
+}
+
+// AttachToSession marks the session as attached, runs OnAttach and detaches again
+func (f *FakeMultiplexer) AttachToSession(name string) error {
+	f.mu.Lock()
+	if err := f.failures["AttachToSession"]; err != nil {
+		f.mu.Unlock()
+		return err
+	}
+
+	session, ok := f.sessions[name]
+	if !ok {
+		f.mu.Unlock()
+		return fmt.Errorf("fake session '%s' not found", name)
+	}
+	session.clients++
+	session.attachCount++
+	onAttach := f.OnAttach
+	f.mu.Unlock()
+
+	var err error
+	if onAttach != nil {
+		err = onAttach(name)
+	}
+
+	f.mu.Lock()
+	// The session may have been killed while attached
+	if session, ok := f.sessions[name]; ok && session.clients > 0 {
+		session.clients--
+	}
+	f.mu.Unlock()
+
+	return err
+}
+
+// KillSession terminates a session
+func (f *FakeMultiplexer) KillSession(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["KillSession"]; err != nil {
+		return err
+	}
+
+	if _, ok := f.sessions[name]; !ok {
+		return fmt.Errorf("fake session '%s' not found", name)
+	}
+	delete(f.sessions, name)
+	return nil
+}
+
+// IsSessionRunning checks if a session is currently running
+func (f *FakeMultiplexer) IsSessionRunning(name string) bool {
+	return f.HasSession(name)
+}
+
+// HasSession checks if a session exists
+func (f *FakeMultiplexer) HasSession(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.sessions[name]
+	return ok
+}
+
+// GetSessionPaneCount returns the number of panes in the current window of a
+// session, which is the window added last as new windows are selected
+func (f *FakeMultiplexer) GetSessionPaneCount(name string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["GetSessionPaneCount"]; err != nil {
+		return 0, err
+	}
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return 0, fmt.Errorf("session '%s' not found", name)
+	}
+
+	return len(session.windows[len(session.windows)-1].Panes), nil
+}
+
+// CapturePane returns the screen set with SetScreen for a pane of a session.
+// Fake panes have no scrollback or colors, so only the target is used.
+func (f *FakeMultiplexer) CapturePane(name string, opts interfaces.CaptureOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["CapturePane"]; err != nil {
+		return "", err
+	}
+
+	pane, err := f.pane(name, opts.Target)
+	if err != nil {
+		return "", err
+	}
+	return pane.Screen, nil
+}
+
+// SendText records text as input to a pane of a session
+func (f *FakeMultiplexer) SendText(name, target, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["SendText"]; err != nil {
+		return err
+	}
+
+	pane, err := f.pane(name, target)
+	if err != nil {
+		return err
+	}
+	pane.Input = append(pane.Input, text)
+	return nil
+}
+
+// SendKeys records keys as input to a pane of a session
+func (f *FakeMultiplexer) SendKeys(name, target string, keys ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["SendKeys"]; err != nil {
+		return err
+	}
+
+	pane, err := f.pane(name, target)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		pane.Input = append(pane.Input, "<"+key+">")
+	}
+	return nil
+}
+
+// PipeOutput records the command the first pane of a session is piped to
+func (f *FakeMultiplexer) PipeOutput(name, command string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["PipeOutput"]; err != nil {
+		return err
+	}
+
+	pane, err := f.pane(name, "")
+	if err != nil {
+		return err
+	}
+	pane.Pipe = command
+	return nil
+}
+
+// SplitPanes adds the panes of a blueprint to the first window of a session
+func (f *FakeMultiplexer) SplitPanes(name string, panes []interfaces.BlueprintPane, env map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["SplitPanes"]; err != nil {
+		return err
+	}
+
+	session, ok := f.sessions[name]
+	if !ok {
+		return fmt.Errorf("session '%s' not found", name)
+	}
+	for _, pane := range panes {
+		workingDir := pane.WorkingDir
+		if workingDir == "" {
+			workingDir = session.workingDir
+		}
+		session.windows[0].Panes = append(session.windows[0].Panes, FakePane{ID: f.newID("%"), Command: pane.Command, WorkingDir: workingDir, Env: env})
+	}
+	return nil
+}
+
+// HasTarget reports whether a pane or window, given by its ID, is in a session
+func (f *FakeMultiplexer) HasTarget(name, target string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, _, err := f.findTarget(name, target)
+	return err == nil
+}
+
+// CloseTarget removes a pane or window, given by its ID, from a session.
+// Like tmux, closing its last pane ends the session.
+func (f *FakeMultiplexer) CloseTarget(name, target string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures["CloseTarget"]; err != nil {
+		return err
+	}
+
+	window, pane, err := f.findTarget(name, target)
+	if err != nil {
+		return err
+	}
+	session := f.sessions[name]
+	if pane >= 0 {
+		session.windows[window].Panes = slices.Delete(session.windows[window].Panes, pane, pane+1)
+	}
+	if pane < 0 || len(session.windows[window].Panes) == 0 {
+		session.windows = slices.Delete(session.windows, window, window+1)
+	}
+	if len(session.windows) == 0 {
+		delete(f.sessions, name)
+	}
+	return nil
+}
+
+// findTarget returns the indexes of the window and pane with an ID, with a
+// pane of -1 for windows. Callers must hold f.mu.
+func (f *FakeMultiplexer) findTarget(name, target string) (int, int, error) {
+	session, ok := f.sessions[name]
+	if !ok {
+		return 0, 0, fmt.Errorf("session '%s' not found", name)
+	}
+
+	for i, window := range session.windows {
+		if window.ID == target {
+			return i, -1, nil
+		}
+		for j, pane := range window.Panes {
+			if pane.ID == target {
+				return i, j, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("target '%s' not found in session '%s'", target, name)
+}
+
+// newID returns a new pane or window ID with the given prefix. Callers must
+// hold f.mu.
+func (f *FakeMultiplexer) newID(prefix string) string {
+	f.lastID++
+	return prefix + strconv.Itoa(f.lastID)
+}
+
+// pane finds a pane by a tmux-style target: "" for the first pane, a window
+// index for its first pane, "window.pane", or a pane or window ID. Callers
+// must hold f.mu.
+func (f *FakeMultiplexer) pane(name, target string) (*FakePane, error) {
+	session, ok := f.sessions[name]
+	if !ok {
+		return nil, fmt.Errorf("session '%s' not found", name)
+	}
+
+	if strings.HasPrefix(target, "%") || strings.HasPrefix(target, "@") {
+		window, pane, err := f.findTarget(name, target)
+		if err != nil {
+			return nil, err
+		}
+		return &session.windows[window].Panes[max(pane, 0)], nil
+	}
+
+	window, pane := 0, 0
+	if target != "" {
+		windowPart, panePart, hasPane := strings.Cut(target, ".")
+		var err error
+		if window, err = strconv.Atoi(windowPart); err != nil {
+			return nil, fmt.Errorf("invalid target '%s'", target)
+		}
+		if hasPane {
+			if pane, err = strconv.Atoi(panePart); err != nil {
+				return nil, fmt.Errorf("invalid target '%s'", target)
+			}
+		}
+	}
+
+	if window < 0 || window >= len(session.windows) || pane < 0 || pane >= len(session.windows[window].Panes) {
+		return nil, fmt.Errorf("target '%s' not found in session '%s'", target, name)
+	}
+	return &session.windows[window].Panes[pane], nil
+}
+
+// view returns an immutable snapshot of the session
+func (s *fakeSession) view() *fakeSessionView {
+	return &fakeSessionView{
+		id:          "fake-" + s.name,
+		name:        s.name,
+		createdAt:   s.createdAt,
+		workingDir:  s.workingDir,
+		description: s.description,
+		attached:    s.clients > 0,
+	}
+}
+
+// fakeSessionView implements the MultiplexerSession interface
+type fakeSessionView struct {
+	id          string
+	name        string
+	createdAt   time.Time
+	workingDir  string
+	description string
+	attached    bool
+}
+
+// GetID returns the session ID
+func (v *fakeSessionView) GetID() string {
+	return v.id
+}
+
+// GetName returns the session name
+func (v *fakeSessionView) GetName() string {
+	return v.name
+}
+
+// GetStatus returns the session status
+func (v *fakeSessionView) GetStatus() interfaces.SessionStatus {
+	return interfaces.StatusActive
+}
+
+// GetCreatedAt returns the creation time
+func (v *fakeSessionView) GetCreatedAt() time.Time {
+	return v.createdAt
+}
+
+// GetWorkingDir returns the working directory
+func (v *fakeSessionView) GetWorkingDir() string {
+	return v.workingDir
+}
+
+// GetDescription returns the session description
+func (v *fakeSessionView) GetDescription() string {
+	return v.description
+}
+
+// IsAttached returns whether a client is attached to the session
+func (v *fakeSessionView) IsAttached() bool {
+	return v.attached
+}
+
+// IsRunning returns whether the session is running
+func (v *fakeSessionView) IsRunning() bool {
+	return true
+}
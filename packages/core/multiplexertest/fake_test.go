@@ -0,0 +1,75 @@
+package multiplexertest
+
+import (
+	"errors"
+	"testing"
+
+	"claude-pilot/shared/interfaces"
+)
+
+func TestFakeMultiplexerContract(t *testing.T) {
+	Contract{
+		New: func(t *testing.T) interfaces.TerminalMultiplexer {
+			return NewFakeMultiplexer()
+		},
+		SupportsPanes:   true,
+		SupportsWindows: true,
+	}.Run(t)
+}
+
+func TestFakeMultiplexerAttachState(t *testing.T) {
+	fake := NewFakeMultiplexer()
+	if _, err := fake.CreateSession(interfaces.CreateSessionRequest{Name: "api"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	fake.OnAttach = func(name string) error {
+		session, err := fake.GetSession(name)
+		if err != nil {
+			return err
+		}
+		if !session.IsAttached() {
+			t.Error("session not reported as attached during AttachToSession")
+		}
+		return nil
+	}
+
+	if err := fake.AttachToSession("api"); err != nil {
+		t.Fatalf("AttachToSession failed: %v", err)
+	}
+
+	session, _ := fake.GetSession("api")
+	if session.IsAttached() {
+		t.Error("session still attached after AttachToSession returned")
+	}
+	if count := fake.AttachCount("api"); count != 1 {
+		t.Errorf("AttachCount = %d, want 1", count)
+	}
+}
+
+func TestFakeMultiplexerWindowsAndFailures(t *testing.T) {
+	fake := NewFakeMultiplexer()
+	_, _ = fake.CreateSession(interfaces.CreateSessionRequest{Name: "api", Command: "claude"})
+	_, _ = fake.CreateSession(interfaces.CreateSessionRequest{
+		Name:           "logs",
+		Command:        "tail -f log",
+		AttachTo:       "api",
+		AttachmentType: interfaces.AttachmentWindow,
+	})
+
+	windows := fake.Windows("api")
+	if len(windows) != 2 || windows[1].Name != "logs" || windows[1].Panes[0].Command != "tail -f log" {
+		t.Errorf("unexpected windows: %+v", windows)
+	}
+
+	boom := errors.New("boom")
+	fake.FailOn("KillSession", boom)
+	if err := fake.KillSession("api"); !errors.Is(err, boom) {
+		t.Errorf("KillSession error = %v, want %v", err, boom)
+	}
+
+	fake.FailOn("KillSession", nil)
+	if err := fake.KillSession("api"); err != nil {
+		t.Errorf("KillSession failed after clearing failure: %v", err)
+	}
+}
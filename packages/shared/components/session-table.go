@@ -1,6 +1,7 @@
 package components
 
 import (
+	"claude-pilot/shared/interfaces"
 	"claude-pilot/shared/styles"
 	"fmt"
 	"os"
@@ -18,11 +19,15 @@ const (
 	columnKeyID         = "id"
 	columnKeyName       = "name"
 	columnKeyStatus     = "status"
+	columnKeyAgent      = "agent"
 	columnKeyBackend    = "backend"
 	columnKeyCreated    = "created"
 	columnKeyLastActive = "last_active"
 	columnKeyProject    = "project"
 	columnKeyPanes      = "panes"
+	columnKeyTokens     = "tokens"
+	columnKeyCost       = "cost"
+	columnKeyContext    = "context"
 )
 
 // TableConfig holds configuration for table rendering
@@ -45,11 +50,32 @@ type SessionData struct {
 	ID          string
 	Name        string
 	Status      string
+	AgentState  string
 	Backend     string
 	Created     time.Time
 	LastActive  time.Time
 	ProjectPath string
 	Panes       int
+
+	// ParentID is the session a pane or window is attached to, which it is
+	// listed under
+	ParentID string
+
+	// Usage of the session's Claude conversation
+	Tokens         int64
+	Cost           float64
+	ContextPercent float64
+}
+
+// SetUsage fills the usage columns from a session's usage, which is nil
+// until its Claude conversation starts
+func (d *SessionData) SetUsage(usage *interfaces.SessionUsage) {
+	if usage == nil {
+		return
+	}
+	d.Tokens = usage.TotalTokens()
+	d.Cost = usage.Cost
+	d.ContextPercent = usage.ContextPercent()
 }
 
 // Table provides a unified table component wrapping evertras/bubble-table
@@ -163,7 +189,7 @@ func (t *SessionTable) refreshEvertrasModel() {
 	}
 
 	// Update rows with processed data
-	rows := GetEvertrasSessionRows(t.data, t.width)
+	rows := t.nestedRows()
 	if len(rows) > 0 {
 		t.evertrasModel = t.evertrasModel.WithRows(rows)
 	}
@@ -195,6 +221,44 @@ func (t *SessionTable) SetSessionData(sessions []SessionData) {
 	t.data = sessions
 }
 
+// nestedRows returns the rows of the sessions in sort order, each followed
+// by the panes and windows attached to it. The evertras model is left
+// unsorted, as it would separate them.
+func (t *SessionTable) nestedRows() []table.Row {
+	present := make(map[string]bool, len(t.data))
+	for _, session := range t.data {
+		present[session.ID] = true
+	}
+
+	var top []SessionData
+	children := make(map[string][]SessionData)
+	for _, session := range t.data {
+		if present[session.ParentID] {
+			children[session.ParentID] = append(children[session.ParentID], session)
+		} else {
+			top = append(top, session)
+		}
+	}
+
+	sorted := table.New(GetEvertrasTableColumns()).WithRows(GetEvertrasSessionRows(top, t.width))
+	if t.config.SortColumn != "" {
+		if t.config.SortDirection == "desc" {
+			sorted = sorted.SortByDesc(t.config.SortColumn)
+		} else {
+			sorted = sorted.SortByAsc(t.config.SortColumn)
+		}
+	}
+
+	var rows []table.Row
+	for _, row := range sorted.GetVisibleRows() {
+		rows = append(rows, row)
+		if id, ok := row.Data[columnKeyID].(string); ok {
+			rows = append(rows, GetEvertrasSessionRows(children[id], t.width)...)
+		}
+	}
+	return rows
+}
+
 // RenderCLI renders the table for CLI output (static)
 func (t *SessionTable) RenderCLI() string {
 	if len(t.data) == 0 {
@@ -232,24 +296,13 @@ func (t *SessionTable) SetSort(column, direction string) error {
 	t.config.SortColumn = column
 	t.config.SortDirection = direction
 
-	// Apply to evertras model if initialized
-	if t.initialized {
-		t.SortByColumn(column, direction == "asc")
-	}
-
 	return nil
 }
 
-// SortByColumn sorts the table by the specified column
+// SortByColumn sorts the table by the specified column. Rows are sorted
+// when they are rendered, keeping attached panes and windows under their
+// session.
 func (t *SessionTable) SortByColumn(columnKey string, ascending bool) {
-	if t.initialized {
-		if ascending {
-			t.evertrasModel = t.evertrasModel.SortByAsc(columnKey)
-		} else {
-			t.evertrasModel = t.evertrasModel.SortByDesc(columnKey)
-		}
-	}
-
 	// Update internal config
 	t.config.SortColumn = columnKey
 	if ascending {
@@ -261,7 +314,7 @@ func (t *SessionTable) SortByColumn(columnKey string, ascending bool) {
 
 // validateSortColumn validates if the given column is valid for sorting
 func (t *SessionTable) validateSortColumn(column string) bool {
-	validColumns := []string{"id", "name", "status", "backend", "created", "last_active", "project", "panes"}
+	validColumns := []string{"id", "name", "status", "agent", "backend", "created", "last_active", "project", "panes", "tokens", "cost", "context"}
 	return slices.Contains(validColumns, column)
 }
 
@@ -273,11 +326,15 @@ func GetEvertrasTableColumns() []table.Column {
 		table.NewFlexColumn(columnKeyID, "ID", 2).WithStyle(columnStyles.ID),
 		table.NewFlexColumn(columnKeyName, "Name", 2).WithStyle(columnStyles.Name),
 		table.NewFlexColumn(columnKeyStatus, "Status", 1).WithStyle(columnStyles.Status),
+		table.NewFlexColumn(columnKeyAgent, "Agent", 1).WithStyle(columnStyles.Status),
 		table.NewFlexColumn(columnKeyBackend, "Backend", 1).WithStyle(columnStyles.Backend),
 		table.NewFlexColumn(columnKeyCreated, "Created", 2).WithStyle(columnStyles.Timestamp),
 		table.NewFlexColumn(columnKeyLastActive, "Last Active", 1).WithStyle(columnStyles.Timestamp),
 		table.NewFlexColumn(columnKeyProject, "Project", 3).WithStyle(columnStyles.Project),
 		table.NewFlexColumn(columnKeyPanes, "Panes", 1).WithStyle(columnStyles.Panes),
+		table.NewFlexColumn(columnKeyTokens, "Tokens", 1).WithStyle(columnStyles.Usage),
+		table.NewFlexColumn(columnKeyCost, "Cost", 1).WithStyle(columnStyles.Usage),
+		table.NewFlexColumn(columnKeyContext, "Context", 1).WithStyle(columnStyles.Usage),
 	}
 }
 
@@ -292,6 +349,9 @@ func GetEvertrasSessionRows(sessions []SessionData, width int) []table.Row {
 
 		id := session.ID
 		name := session.Name
+		if session.ParentID != "" {
+			name = "└─ " + name
+		}
 
 		// Get the width of the project path column
 		projectPathWidth := min(width-10, 50)
@@ -311,11 +371,15 @@ func GetEvertrasSessionRows(sessions []SessionData, width int) []table.Row {
 			columnKeyID:         id,
 			columnKeyName:       name,
 			columnKeyStatus:     status,
+			columnKeyAgent:      styles.FormatAgentState(session.AgentState),
 			columnKeyBackend:    backend,
 			columnKeyCreated:    created,
 			columnKeyLastActive: timeAgo,
 			columnKeyProject:    projectPath,
 			columnKeyPanes:      panes,
+			columnKeyTokens:     styles.FormatTokens(session.Tokens),
+			columnKeyCost:       styles.FormatCost(session.Cost),
+			columnKeyContext:    styles.FormatContextPercent(session.ContextPercent),
 		})
 	}
 
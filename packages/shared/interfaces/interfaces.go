@@ -11,14 +11,49 @@ const (
 	StatusConnected SessionStatus = "connected"
 	StatusError     SessionStatus = "error"
 	StatusWarning   SessionStatus = "warning"
+
+	// States of headless runs
+	StatusRunning   SessionStatus = "running"
+	StatusSucceeded SessionStatus = "succeeded"
+	StatusFailed    SessionStatus = "failed"
+)
+
+// HeadlessBackend is the backend of sessions that run claude -p as a
+// background process instead of in a multiplexer
+const HeadlessBackend = "headless"
+
+// AgentState is what the Claude agent in a session is doing, as read from its pane
+type AgentState string
+
+const (
+	AgentUnknown           AgentState = ""           // Not running, or the screen is not recognized
+	AgentWorking           AgentState = "working"    // Generating or running tools
+	AgentWaitingInput      AgentState = "waiting"    // Idle at the prompt, waiting for the user
+	AgentWaitingPermission AgentState = "permission" // Asking the user to allow a tool call
+	AgentErrored           AgentState = "error"      // Stopped on an error, e.g. an API error
 )
 
+// NeedsAttention reports whether the agent is blocked on the user
+func (s AgentState) NeedsAttention() bool {
+	return s == AgentWaitingInput || s == AgentWaitingPermission || s == AgentErrored
+}
+
 // Message represents a message in a Claude session
 type Message struct {
-	ID        string    `json:"id"`
-	Role      string    `json:"role"` // "user" or "assistant"
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
+	ID        string     `json:"id"`
+	Role      string     `json:"role"` // "user" or "assistant"
+	Content   string     `json:"content"`
+	Timestamp time.Time  `json:"timestamp"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall is a tool invocation made by Claude in an assistant message
+type ToolCall struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Input   string `json:"input"` // Tool input as JSON
+	Output  string `json:"output,omitempty"`
+	IsError bool   `json:"is_error,omitempty"`
 }
 
 // Session represents a Claude coding session with persistence
@@ -32,6 +67,217 @@ type Session struct {
 	ProjectPath string        `json:"project_path"`
 	Description string        `json:"description"`
 	Panes       int           `json:"panes"`
+	Tags        []string      `json:"tags,omitempty"`
+
+	// ConversationID is the Claude Code conversation running in the session
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// Usage totals the tokens and cost of the session's conversation. It is
+	// read from the transcript whenever the session is, and never stored.
+	Usage *SessionUsage `json:"-"`
+
+	// AgentState is detected from the session's pane while it runs, only
+	// when asked for with DetectAgentStates, and never stored
+	AgentState AgentState `json:"-"`
+
+	// Muted sessions do not send notifications
+	Muted bool `json:"muted,omitempty"`
+
+	// LastHook is the latest event reported by Claude Code hooks in the session
+	LastHook *HookEvent `json:"last_hook,omitempty"`
+
+	// LogFile is where the session's output is logged, empty when it is not
+	LogFile string `json:"log_file,omitempty"`
+
+	// Run is the claude -p run of a headless session
+	Run *HeadlessRun `json:"run,omitempty"`
+
+	// Worktree is the git worktree created for the session, if any
+	Worktree *Worktree `json:"worktree,omitempty"`
+
+	// Group links the sessions started together by a fanout
+	Group string `json:"group,omitempty"`
+
+	// Command is the program the session runs, Claude when empty
+	Command string `json:"command,omitempty"`
+
+	// Claude reports whether Command starts Claude, the configured
+	// default_shell; only then does resuming continue its conversation
+	Claude bool `json:"claude,omitempty"`
+
+	// Launch holds the options Claude is started and resumed with
+	Launch *LaunchOptions `json:"launch,omitempty"`
+
+	// Workspace is the workspace file that declared the session, if any
+	Workspace string `json:"workspace,omitempty"`
+
+	// Blueprint holds the extra panes the session is created and resumed with
+	Blueprint []BlueprintPane `json:"blueprint,omitempty"`
+
+	// ParentID is the session a pane or window was attached to, empty for
+	// standalone sessions
+	ParentID string `json:"parent_id,omitempty"`
+
+	// Attachment is how the session was added to its parent, split in
+	// SplitDirection for panes
+	Attachment     AttachmentType `json:"attachment,omitempty"`
+	SplitDirection SplitDirection `json:"split,omitempty"`
+
+	// Target is the multiplexer's ID of the pane or window, e.g. %3 or @2 in
+	// tmux, empty for backends that are not TargetClosers
+	Target string `json:"target,omitempty"`
+}
+
+// LaunchOptions are command line options of Claude for a session
+type LaunchOptions struct {
+	Model              string   `json:"model,omitempty"`
+	PermissionMode     string   `json:"permission_mode,omitempty"` // e.g. acceptEdits, plan
+	AllowedTools       []string `json:"allowed_tools,omitempty"`
+	DisallowedTools    []string `json:"disallowed_tools,omitempty"`
+	MCPConfig          string   `json:"mcp_config,omitempty"` // Path of an MCP servers config file
+	AppendSystemPrompt string   `json:"append_system_prompt,omitempty"`
+	AddDirs            []string `json:"add_dirs,omitempty"` // More directories Claude may access
+	Args               []string `json:"args,omitempty"`     // Any other arguments
+}
+
+// CommandArgs returns the options as Claude's command line arguments
+func (o *LaunchOptions) CommandArgs() []string {
+	if o == nil {
+		return nil
+	}
+
+	var args []string
+	if o.Model != "" {
+		args = append(args, "--model", o.Model)
+	}
+	if o.PermissionMode != "" {
+		args = append(args, "--permission-mode", o.PermissionMode)
+	}
+	if len(o.AllowedTools) > 0 {
+		args = append(append(args, "--allowedTools"), o.AllowedTools...)
+	}
+	if len(o.DisallowedTools) > 0 {
+		args = append(append(args, "--disallowedTools"), o.DisallowedTools...)
+	}
+	if o.MCPConfig != "" {
+		args = append(args, "--mcp-config", o.MCPConfig)
+	}
+	if o.AppendSystemPrompt != "" {
+		args = append(args, "--append-system-prompt", o.AppendSystemPrompt)
+	}
+	if len(o.AddDirs) > 0 {
+		args = append(append(args, "--add-dir"), o.AddDirs...)
+	}
+	return append(args, o.Args...)
+}
+
+// Worktree is a git worktree, with its own branch, that a session runs in
+type Worktree struct {
+	Repo   string `json:"repo"` // Main working tree of the repository
+	Branch string `json:"branch"`
+	Path   string `json:"path"`
+	Base   string `json:"base,omitempty"` // Commit the worktree was created at
+}
+
+// DiffStats summarizes the changes made in a worktree since its base commit
+type DiffStats struct {
+	Commits    int `json:"commits"` // Commits on top of the base
+	Files      int `json:"files"`   // Changed tracked files, committed or not
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+	Untracked  int `json:"untracked"` // New files not added to git yet
+}
+
+// HeadlessRun is the state of a claude -p run in a headless session
+type HeadlessRun struct {
+	Prompt     string    `json:"prompt"`
+	PID        int       `json:"pid,omitempty"` // Runner process, 0 until it has started
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	Error      string    `json:"error,omitempty"` // Why the run failed, besides Claude's result
+
+	// Reported by Claude in its stream-json events
+	ConversationID string        `json:"conversation_id,omitempty"`
+	Result         string        `json:"result,omitempty"`
+	IsError        bool          `json:"is_error,omitempty"`
+	NumTurns       int           `json:"num_turns,omitempty"`
+	Cost           float64       `json:"cost,omitempty"`
+	Duration       time.Duration `json:"duration,omitempty"`
+}
+
+// Finished reports whether the run has ended
+func (r *HeadlessRun) Finished() bool {
+	return !r.FinishedAt.IsZero()
+}
+
+// Succeeded reports whether the run ended with a successful result
+func (r *HeadlessRun) Succeeded() bool {
+	return r.Finished() && r.ExitCode == 0 && !r.IsError && r.Error == ""
+}
+
+// HookEvent is an event reported by a Claude Code hook running in a session
+type HookEvent struct {
+	Name           string     `json:"name"` // Hook event name, e.g. "Stop" or "PreToolUse"
+	ConversationID string     `json:"conversation_id,omitempty"`
+	Cwd            string     `json:"cwd,omitempty"`
+	ToolName       string     `json:"tool_name,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	AgentState     AgentState `json:"agent_state,omitempty"` // What the event says the agent is doing
+	Time           time.Time  `json:"time"`
+}
+
+// HookOrigin tells where a Claude Code hook ran, to find the session it belongs to
+type HookOrigin struct {
+	SessionID string // From SessionIDEnv, set in the environment of every session
+	Pane      string // Multiplexer pane the hook ran in, e.g. tmux's TMUX_PANE
+}
+
+// TokenUsage counts the tokens used by Claude API requests and their cost
+type TokenUsage struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	Cost                float64 `json:"cost"` // USD, from the configured price table
+}
+
+// Add adds other's tokens and cost to u
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.Cost += other.Cost
+}
+
+// TotalTokens returns the sum of all token counts
+func (u TokenUsage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// UsageRecord is the usage of a single Claude API request
+type UsageRecord struct {
+	TokenUsage
+	Timestamp time.Time `json:"timestamp"`
+	Model     string    `json:"model"`
+	Sidechain bool      `json:"sidechain,omitempty"` // Made by a subagent
+}
+
+// SessionUsage is the usage of a session's conversation
+type SessionUsage struct {
+	TokenUsage
+	Model         string `json:"model,omitempty"` // Model of the latest request
+	ContextTokens int64  `json:"context_tokens"`  // Tokens in the context window as of the latest request
+	ContextWindow int64  `json:"context_window"`
+}
+
+// ContextPercent returns how full the context window is, from 0 to 100
+func (u SessionUsage) ContextPercent() float64 {
+	if u.ContextWindow <= 0 {
+		return 0
+	}
+	return float64(u.ContextTokens) / float64(u.ContextWindow) * 100
 }
 
 // AttachmentType represents how to attach to an existing session
@@ -51,15 +297,68 @@ const (
         SplitHorizontal SplitDirection = "h" // Split horizontally (left/right)
 )
 
+// BlueprintPane is an extra pane of a new session's window. Each pane of a
+// blueprint splits the one before it, the first splits the session's pane.
+type BlueprintPane struct {
+	Command        string         `json:"command,omitempty"`     // Run in the pane (default: the user's shell)
+	SplitDirection SplitDirection `json:"split,omitempty"`       // How the previous pane is split (default: v)
+	Size           int            `json:"size,omitempty"`        // Percentage of the split pane taken (default: half)
+	WorkingDir     string         `json:"working_dir,omitempty"` // Default: the session's working directory
+}
+
+// SessionIDEnv is the environment variable holding the ID of the session a
+// process runs in
+const SessionIDEnv = "CLAUDE_PILOT_SESSION_ID"
+
 // CreateSessionRequest contains parameters for creating a new session
 type CreateSessionRequest struct {
 	Name           string
 	Description    string
 	WorkingDir     string
-	Command        string         // Command to run in the session (default: "claude")
-	AttachTo       string         // Target session name to attach to
-	AttachmentType AttachmentType // How to attach (pane, window, or standalone)
-	SplitDirection SplitDirection // Direction for pane splits (v/h)
+	Command        string            // Program to run in the session (default: "claude"), started with Launch and Prompt
+	AttachTo       string            // Target session name to attach to
+	AttachmentType AttachmentType    // How to attach (pane, window, or standalone)
+	SplitDirection SplitDirection    // Direction for pane splits (v/h)
+	Env            map[string]string // Environment variables for the command
+	Tags           []string          // Tags of the session, stored in its metadata
+	LogOutput      *bool             // Log the session's output (default: as configured)
+	OutputPipe     string            // Shell command the session's output is piped into, by OutputPipers
+	Worktree       bool              // Run in a new git worktree of WorkingDir's repository
+	Branch         string            // Branch of the worktree (default: the session name)
+	Prompt         string            // Initial prompt passed to Command
+	Group          string            // Group the session belongs to
+	Launch         *LaunchOptions    // Options Claude is started with
+	Workspace      string            // Workspace file declaring the session
+	Blueprint      []BlueprintPane   // Extra panes of a standalone session, by PaneSplitters
+}
+
+// FanoutRequest contains parameters for starting the same task in several
+// sessions, each in its own worktree
+type FanoutRequest struct {
+	Name        string // Group ID, and prefix of the sessions' names and branches
+	Description string
+	WorkingDir  string
+	Command     string // Program to run (default: "claude")
+	Prompt      string
+	Count       int
+	Tags        []string
+}
+
+// RunRequest contains parameters for starting a headless session
+type RunRequest struct {
+	Name        string
+	Description string
+	WorkingDir  string
+	Prompt      string
+	Args        []string // Extra arguments for claude, e.g. "--model" "opus"
+	Tags        []string
+}
+
+// SendInputOptions controls how input is delivered to a session
+type SendInputOptions struct {
+	Target string   // Pane or window to type into, e.g. "1" or "1.2" (default: the first pane)
+	Keys   []string // Keys pressed before the text, e.g. "Escape" or "C-c"
+	Enter  bool     // Press Enter after the text
 }
 
 // MultiplexerSession represents a session managed by a terminal multiplexer
@@ -107,6 +406,88 @@ type TerminalMultiplexer interface {
 	GetSessionPaneCount(name string) (int, error)
 }
 
+// CaptureOptions selects what is captured from a session's pane
+type CaptureOptions struct {
+	Target string // Pane or window, e.g. "1" or "1.2" (default: the first pane, where Claude runs)
+	Lines  int    // Scrollback lines to include above the visible screen; negative for all
+	ANSI   bool   // Keep colors and text attributes as ANSI escape sequences
+}
+
+// PaneCapturer is implemented by multiplexers that can read a session's screen
+type PaneCapturer interface {
+	// CapturePane returns the text of a pane of the session, by default the
+	// visible screen of its first pane
+	CapturePane(name string, opts CaptureOptions) (string, error)
+}
+
+// InputSender is implemented by multiplexers that can type into a session
+type InputSender interface {
+	// SendText pastes text into a pane of the session. Target selects the
+	// pane or window, the session's first pane when empty.
+	SendText(name, target, text string) error
+
+	// SendKeys presses keys in a pane of the session, given by names such as
+	// Enter, Escape or C-c
+	SendKeys(name, target string, keys ...string) error
+}
+
+// PaneLocator is implemented by multiplexers that can tell which session a
+// pane belongs to
+type PaneLocator interface {
+	// SessionForPane returns the name of the session containing a pane
+	SessionForPane(pane string) (string, error)
+}
+
+// OutputPiper is implemented by multiplexers that can stream what a session
+// prints to another process. They start CreateSessionRequest.OutputPipe
+// together with a new session, so that none of its output is missed.
+type OutputPiper interface {
+	// PipeOutput starts a shell command that receives the output of the
+	// session's first pane on stdin, replacing any command started before
+	PipeOutput(name, command string) error
+}
+
+// ClientMessenger is implemented by multiplexers that can show a message to
+// the users attached to their sessions
+type ClientMessenger interface {
+	// DisplayMessage shows a message to every attached client
+	DisplayMessage(message string) error
+}
+
+// PaneSplitter is implemented by multiplexers that can split a new session
+// into the panes of a blueprint
+type PaneSplitter interface {
+	// SplitPanes adds the panes to the session's window in order, with env
+	// set in them. Panes already added are left when one fails.
+	SplitPanes(name string, panes []BlueprintPane, env map[string]string) error
+}
+
+// TargetCloser is implemented by multiplexers that can address the panes and
+// windows attached to a session on their own. The ID of the multiplexer
+// session CreateSession returns for an attachment is its target.
+type TargetCloser interface {
+	// HasTarget reports whether the pane or window is still in the session
+	HasTarget(name, target string) bool
+
+	// CloseTarget kills the pane or window, leaving the rest of the session
+	CloseTarget(name, target string) error
+}
+
+// SessionEvent reports a change in multiplexer state
+type SessionEvent struct {
+	Type string // Backend-specific event name (e.g. "sessions-changed")
+	Args string // Raw event arguments, if any
+}
+
+// SessionEventSource is implemented by multiplexers that can push state
+// changes instead of being polled
+type SessionEventSource interface {
+	// SubscribeSessionEvents returns a channel of state changes and a function
+	// that ends the subscription and closes the channel. A nil channel means
+	// events are unavailable and callers should poll.
+	SubscribeSessionEvents() (<-chan SessionEvent, func())
+}
+
 // SessionRepository handles persistence of session metadata
 type SessionRepository interface {
 	// Save stores a session to persistent storage
@@ -131,6 +512,15 @@ type SessionRepository interface {
 	SaveIndex() error
 }
 
+// SessionUpdater is implemented by repositories that can change a stored
+// session atomically, so that processes changing the same session, such as
+// concurrent Claude Code hooks, do not lose each other's changes
+type SessionUpdater interface {
+	// Update reads the session with the given ID, applies update and stores
+	// the result, with no other Update in between
+	Update(id string, update func(*Session)) (*Session, error)
+}
+
 // SessionService defines the business logic interface for session management
 type SessionService interface {
 	// CreateSession creates a new session with both metadata and multiplexer session
@@ -142,6 +532,14 @@ type SessionService interface {
 	// GetSession retrieves a session by ID or name
 	GetSession(identifier string) (*Session, error)
 
+	// GetSessionDetails retrieves a session like GetSession, also linking
+	// the Claude conversation running in it and detecting its agent state
+	GetSessionDetails(identifier string) (*Session, error)
+
+	// DetectAgentStates sets the agent state of running sessions, which
+	// reads their panes
+	DetectAgentStates(sessions []*Session)
+
 	// ListSessions returns all sessions with their current status
 	ListSessions() ([]*Session, error)
 
@@ -157,6 +555,52 @@ type SessionService interface {
 	// AttachToSession connects to an existing session
 	AttachToSession(identifier string) error
 
+	// ResumeSession recreates the multiplexer session of an inactive session
+	ResumeSession(identifier string) (*Session, error)
+
+	// ResumeTargets returns the inactive sessions to resume, leaving out
+	// panes and windows resumed along with their session
+	ResumeTargets() ([]*Session, error)
+
+	// GetMessages returns the messages of the Claude conversation in a session
+	GetMessages(identifier string) ([]Message, error)
+
+	// GetUsage returns the usage of each Claude API request made in a session
+	GetUsage(identifier string) ([]UsageRecord, error)
+
+	// SetMuted turns notifications for a session off or back on
+	SetMuted(identifier string, muted bool) (*Session, error)
+
+	// SendInput types text and keys into a running session
+	SendInput(identifier, text string, opts SendInputOptions) error
+
+	// InputTargets returns the running sessions input can be sent to
+	// together, optionally only those with a tag
+	InputTargets(tag string) ([]*Session, error)
+
+	// Capture returns the screen, and optionally scrollback, of a running session
+	Capture(identifier string, opts CaptureOptions) (string, error)
+
+	// RunHeadless starts claude -p in the background as a headless session
+	RunHeadless(req RunRequest) (*Session, error)
+
+	// RemoveWorktree removes the git worktree of a session; without force it
+	// refuses when the worktree has uncommitted changes
+	RemoveWorktree(wt *Worktree, force bool) error
+
+	// WorktreeDiff summarizes the changes made in a session's worktree
+	WorktreeDiff(wt *Worktree) (*DiffStats, error)
+
+	// Fanout creates sessions that work on the same prompt in parallel, each
+	// in its own worktree, linked by a group
+	Fanout(req FanoutRequest) ([]*Session, error)
+
+	// ListGroupSessions returns the sessions of a group
+	ListGroupSessions(group string) ([]*Session, error)
+
+	// RecordHookEvent records a Claude Code hook event in the session it came from
+	RecordHookEvent(event HookEvent, origin HookOrigin) (*Session, error)
+
 	// IsSessionRunning checks if the session's multiplexer is active
 	IsSessionRunning(identifier string) bool
 
@@ -165,4 +609,21 @@ type SessionService interface {
 
 	// GetSessionPaneCount returns the number of panes in a session
 	GetSessionPaneCount(identifier string) (int, error)
+
+	// Reconcile brings session metadata in line with the multiplexer's sessions
+	Reconcile(opts ReconcileOptions) (*ReconcileResult, error)
+}
+
+// ReconcileOptions controls how metadata and multiplexer sessions are reconciled
+type ReconcileOptions struct {
+	DryRun bool // Report what would change without changing anything
+	Prune  bool // Delete metadata whose multiplexer session is gone
+}
+
+// ReconcileResult reports what reconciliation found
+type ReconcileResult struct {
+	Adopted []*Session // Multiplexer sessions that had no metadata
+	Stale   []*Session // Metadata whose multiplexer session is gone
+	Pruned  []*Session // Stale metadata that was deleted
+	DryRun  bool       // Whether the changes were only reported
 }
@@ -604,6 +604,7 @@ type EvertrasColumnStyles struct {
 	Timestamp lipgloss.Style
 	Project   lipgloss.Style
 	Panes     lipgloss.Style
+	Usage     lipgloss.Style
 }
 
 // GetEvertrasColumnStyles returns column-specific styles for different data types
@@ -645,5 +646,9 @@ func GetEvertrasColumnStyles() EvertrasColumnStyles {
 			Bold(true).
 			Padding(0, 1).
 			Align(lipgloss.Center),
+		Usage: lipgloss.NewStyle().
+			Foreground(TextSecondary).
+			Padding(0, 1).
+			Align(lipgloss.Right),
 	}
 }
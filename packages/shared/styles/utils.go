@@ -103,13 +103,13 @@ func GetContextualColor(context Context, state string) lipgloss.Color {
 	switch context {
 	case ContextStatus:
 		switch state {
-		case "success", "active", "connected":
+		case "success", "active", "connected", "succeeded":
 			return SuccessColor
 		case "warning", "inactive":
 			return WarningColor
 		case "error", "failed":
 			return ErrorColor
-		case "info", "pending":
+		case "info", "pending", "running":
 			return InfoColor
 		default:
 			return TextSecondary
@@ -149,7 +149,7 @@ func GetContextualColor(context Context, state string) lipgloss.Color {
 
 	case ContextBackend:
 		switch state {
-		case "tmux":
+		case "tmux", "zellij", "screen", "native", "headless":
 			return TextPrimary
 		default:
 			return TextSecondary
@@ -189,6 +189,10 @@ func FormatStatus(status string) string {
 		return "✗ " + status
 	case "starting", "pending":
 		return "⏳ " + status
+	case "running":
+		return "▶ " + status
+	case "succeeded":
+		return "✓ " + status
 	case "stopped":
 		return "⏹ " + status
 	default:
@@ -196,6 +200,23 @@ func FormatStatus(status string) string {
 	}
 }
 
+// FormatAgentState formats a Claude agent state, highlighting agents that
+// are blocked on the user
+func FormatAgentState(state string) string {
+	switch state {
+	case "working":
+		return TableCellInfoStyle.Render("⚙ working")
+	case "waiting":
+		return TableCellWarningStyle.Render("💬 waiting")
+	case "permission":
+		return TableCellWarningStyle.Render("✋ permission")
+	case "error":
+		return TableCellErrorStyle.Render("✗ error")
+	default:
+		return TableCellStyle.Render("—")
+	}
+}
+
 // FormatTime formats timestamps with consistent styling
 func FormatTime(t time.Time) string {
 	return TableCellTimestampStyle.Render(t.Format("2006-01-02 15:04"))
@@ -219,6 +240,48 @@ func FormatTimeAgo(t time.Time) string {
 	}
 }
 
+// FormatTokens formats a token count compactly, e.g. 12.3k or 1.2M
+func FormatTokens(tokens int64) string {
+	if tokens <= 0 {
+		return TableCellStyle.Render("—")
+	}
+
+	switch {
+	case tokens < 1_000:
+		return TableCellStyle.Render(fmt.Sprintf("%d", tokens))
+	case tokens < 1_000_000:
+		return TableCellStyle.Render(fmt.Sprintf("%.1fk", float64(tokens)/1_000))
+	default:
+		return TableCellStyle.Render(fmt.Sprintf("%.1fM", float64(tokens)/1_000_000))
+	}
+}
+
+// FormatCost formats a cost in USD
+func FormatCost(cost float64) string {
+	if cost <= 0 {
+		return TableCellStyle.Render("—")
+	}
+	return TableCellStyle.Render(fmt.Sprintf("$%.2f", cost))
+}
+
+// FormatContextPercent formats a context window fill with semantic colors
+// that warn as the window fills up
+func FormatContextPercent(percent float64) string {
+	if percent <= 0 {
+		return TableCellStyle.Render("—")
+	}
+
+	text := fmt.Sprintf("%.0f%%", percent)
+	switch {
+	case percent < 50:
+		return TableCellSuccessStyle.Render(text)
+	case percent < 80:
+		return TableCellWarningStyle.Render(text)
+	default:
+		return TableCellErrorStyle.Render(text)
+	}
+}
+
 // FormatProjectPath formats project paths with consistent styling and smart truncation
 func FormatProjectPath(path string, maxLen int) string {
 	if path == "" {
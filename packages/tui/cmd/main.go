@@ -10,6 +10,34 @@ import (
 )
 
 func main() {
+	// Native backend sessions are held by a re-executed copy of this binary
+	if len(os.Args) > 1 && os.Args[1] == api.NativeHolderCommand {
+		if err := api.RunNativeHolder(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running session holder: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	api.EnableNativeBackend()
+
+	// Logged sessions pipe their output into a copy of this binary
+	if len(os.Args) > 1 && os.Args[1] == api.LogWriterCommand {
+		if err := api.RunLogWriter(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing session log: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Headless sessions run in a background copy of this binary
+	if len(os.Args) > 1 && os.Args[1] == api.HeadlessRunnerCommand {
+		if err := api.RunHeadlessRunner(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running headless session: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize the core API client
 	client, err := api.NewDefaultClient(false) // verbose = false for TUI
 	if err != nil {
@@ -3,11 +3,12 @@ package tui
 import (
 	"claude-pilot/core/api"
 	"claude-pilot/shared/components"
+	"claude-pilot/shared/interfaces"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -26,6 +27,7 @@ func loadSessionsCmd(client *api.Client) tea.Cmd {
 		}
 
 		sessions, err := client.ListSessions()
+		client.WithAgentState(sessions)
 		return sessionsLoadedMsg{
 			sessions: sessions,
 			err:      err,
@@ -33,8 +35,35 @@ func loadSessionsCmd(client *api.Client) tea.Cmd {
 	}
 }
 
-// createSessionCmd creates a new session with the specified parameters
-func createSessionCmd(client *api.Client, name, description, projectPath string) tea.Cmd {
+// sessionEventDebounce coalesces bursts of multiplexer events, such as the
+// several notifications tmux sends for one new session
+const sessionEventDebounce = 100 * time.Millisecond
+
+// waitForSessionEventCmd waits for the next multiplexer state change
+func waitForSessionEventCmd(events <-chan interfaces.SessionEvent) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-events; !ok {
+			return nil
+		}
+
+		timer := time.NewTimer(sessionEventDebounce)
+		defer timer.Stop()
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					return sessionsChangedMsg{}
+				}
+			case <-timer.C:
+				return sessionsChangedMsg{}
+			}
+		}
+	}
+}
+
+// createSessionCmd creates a new session from req, whose empty fields are
+// taken from the named template unless it is empty
+func createSessionCmd(client *api.Client, req api.CreateSessionRequest, template string) tea.Cmd {
 	return func() tea.Msg {
 		if client == nil {
 			return sessionCreatedMsg{
@@ -43,19 +72,22 @@ func createSessionCmd(client *api.Client, name, description, projectPath string)
 			}
 		}
 
-		if strings.TrimSpace(name) == "" {
+		if template != "" {
+			if err := client.ApplyTemplate(template, &req); err != nil {
+				return sessionCreatedMsg{
+					session: nil,
+					err:     err,
+				}
+			}
+		}
+
+		if strings.TrimSpace(req.Name) == "" {
 			return sessionCreatedMsg{
 				session: nil,
 				err:     fmt.Errorf("session name cannot be empty"),
 			}
 		}
 
-		req := api.CreateSessionRequest{
-			Name:        strings.TrimSpace(name),
-			Description: strings.TrimSpace(description),
-			ProjectPath: strings.TrimSpace(projectPath),
-		}
-
 		session, err := client.CreateSession(req)
 		return sessionCreatedMsg{
 			session: session,
@@ -89,49 +121,67 @@ func killSessionCmd(client *api.Client, sessionID string) tea.Cmd {
 	}
 }
 
-// attachSessionCmd attaches to a session and hands control to the multiplexer
-func attachSessionCmd(client *api.Client, sessionID string) tea.Cmd {
+// resumeSessionCmd recreates the multiplexer session of an inactive session
+func resumeSessionCmd(client *api.Client, sessionID string) tea.Cmd {
 	return func() tea.Msg {
 		if client == nil {
-			return errorMsg{error: fmt.Errorf("API client is nil")}
+			return sessionResumedMsg{err: fmt.Errorf("API client is nil")}
 		}
 
-		if strings.TrimSpace(sessionID) == "" {
-			return errorMsg{error: fmt.Errorf("session ID cannot be empty")}
+		session, err := client.ResumeSession(sessionID)
+		return sessionResumedMsg{
+			session: session,
+			err:     err,
 		}
+	}
+}
 
-		// Get the session to find its name
-		session, err := client.GetSession(sessionID)
-		if err != nil {
-			return errorMsg{error: fmt.Errorf("failed to get session: %w", err)}
+// attachSessionCmd attaches to a session and hands control to the multiplexer
+func attachSessionCmd(client *api.Client, sessionID string) tea.Cmd {
+	if client == nil {
+		return func() tea.Msg {
+			return errorMsg{error: fmt.Errorf("API client is nil")}
 		}
+	}
 
-		if session == nil {
-			return errorMsg{error: fmt.Errorf("session not found")}
+	if strings.TrimSpace(sessionID) == "" {
+		return func() tea.Msg {
+			return errorMsg{error: fmt.Errorf("session ID cannot be empty")}
 		}
+	}
 
-		if strings.TrimSpace(session.Name) == "" {
-			return errorMsg{error: fmt.Errorf("session name is empty")}
+	// Suspend the TUI and attach through the API, so the configured backend,
+	// binary and tmux socket are used
+	return tea.Exec(&attachCommand{client: client, sessionID: sessionID}, func(err error) tea.Msg {
+		if err != nil {
+			return errorMsg{error: fmt.Errorf("failed to attach to session: %w", err)}
 		}
+		// After the session ends, quit the TUI
+		return tea.Quit()
+	})
+}
 
-		// Create a command to attach to the session
-		// This will hand control over to the multiplexer session
-		cmd := exec.Command("tmux", "attach-session", "-t", session.Name)
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+// attachCommand runs a blocking attach as a tea.ExecCommand. The multiplexer
+// talks to the terminal directly, so the provided streams are not used.
+type attachCommand struct {
+	client    *api.Client
+	sessionID string
+}
 
-		// Use tea.ExecProcess to hand control to the external process
-		return tea.ExecProcess(cmd, func(err error) tea.Msg {
-			if err != nil {
-				return errorMsg{error: fmt.Errorf("failed to attach to session: %w", err)}
-			}
-			// After the session ends, quit the TUI
-			return tea.Quit()
-		})
-	}
+// Run attaches to the session until the user detaches
+func (c *attachCommand) Run() error {
+	return c.client.AttachToSession(c.sessionID)
 }
 
+// SetStdin is a no-op; the multiplexer reads the terminal directly
+func (c *attachCommand) SetStdin(io.Reader) {}
+
+// SetStdout is a no-op; the multiplexer writes the terminal directly
+func (c *attachCommand) SetStdout(io.Writer) {}
+
+// SetStderr is a no-op; the multiplexer writes the terminal directly
+func (c *attachCommand) SetStderr(io.Writer) {}
+
 // Table Data Commands
 
 // SortTableDataCmd sorts table data by the specified column and direction asynchronously
@@ -536,7 +586,7 @@ func exportToCSV(file *os.File, data []components.SessionData) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"ID", "Name", "Status", "Backend", "Created", "Last Active", "Project", "Panes"}
+	header := []string{"ID", "Name", "Status", "Agent", "Backend", "Created", "Last Active", "Project", "Panes", "Tokens", "Cost", "Context"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -547,11 +597,15 @@ func exportToCSV(file *os.File, data []components.SessionData) error {
 			session.ID,
 			session.Name,
 			session.Status,
+			session.AgentState,
 			session.Backend,
 			session.Created.Format(time.RFC3339),
 			session.LastActive.Format(time.RFC3339),
 			session.ProjectPath,
 			fmt.Sprintf("%d", session.Panes),
+			fmt.Sprintf("%d", session.Tokens),
+			fmt.Sprintf("%.4f", session.Cost),
+			fmt.Sprintf("%.1f", session.ContextPercent),
 		}
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("failed to write CSV record: %w", err)
@@ -16,6 +16,7 @@ type KeyMap struct {
 	Attach  key.Binding
 	Create  key.Binding
 	Kill    key.Binding
+	Resume  key.Binding
 	Refresh key.Binding
 	Quit    key.Binding
 
@@ -24,9 +25,11 @@ type KeyMap struct {
 	No  key.Binding
 
 	// Form navigation
-	Submit    key.Binding
-	NextInput key.Binding
-	PrevInput key.Binding
+	Submit     key.Binding
+	NextInput  key.Binding
+	PrevInput  key.Binding
+	NextOption key.Binding
+	PrevOption key.Binding
 
 	// Help and navigation
 	Help key.Binding
@@ -90,6 +93,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("k"),
 			key.WithHelp("k", "kill session"),
 		),
+		Resume: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "resume session"),
+		),
 		Refresh: key.NewBinding(
 			key.WithKeys("r"),
 			key.WithHelp("r", "refresh"),
@@ -122,6 +129,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("shift+tab"),
 			key.WithHelp("shift+tab", "previous field"),
 		),
+		NextOption: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", "next option"),
+		),
+		PrevOption: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "previous option"),
+		),
 
 		// Help and navigation
 		Help: key.NewBinding(
@@ -213,7 +228,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		// Navigation
 		{k.Up, k.Down, k.PageUp, k.PageDown},
 		// Actions
-		{k.Attach, k.Create, k.Kill, k.Refresh},
+		{k.Attach, k.Create, k.Kill, k.Resume, k.Refresh},
 		// Confirmation
 		{k.Yes, k.No},
 		// Table Selection
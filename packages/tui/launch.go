@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"claude-pilot/core/api"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// launchField is a create form field for one of Claude's launch options
+type launchField struct {
+	label       string
+	placeholder string
+}
+
+// Fields of the launch options, in form order
+const (
+	modelField = iota
+	permissionModeField
+	allowedToolsField
+	disallowedToolsField
+	mcpConfigField
+	systemPromptField
+	addDirsField
+	argsField
+)
+
+var launchFields = [...]launchField{
+	modelField:           {"Model", "e.g. opus or sonnet"},
+	permissionModeField:  {"Permission Mode", "default, acceptEdits, plan or bypassPermissions"},
+	allowedToolsField:    {"Allowed Tools", "comma separated, e.g. Bash(git log:*),Edit"},
+	disallowedToolsField: {"Disallowed Tools", "comma separated"},
+	mcpConfigField:       {"MCP Config", "path of an MCP servers config file"},
+	systemPromptField:    {"System Prompt", "text appended to Claude's system prompt"},
+	addDirsField:         {"Extra Dirs", "comma separated directories Claude may access"},
+	argsField:            {"Extra Args", "other claude arguments, space separated"},
+}
+
+// newLaunchInputs creates the text inputs of the launch options
+func newLaunchInputs() []textinput.Model {
+	inputs := make([]textinput.Model, len(launchFields))
+	for i, field := range launchFields {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = field.placeholder
+		inputs[i].CharLimit = 500
+		inputs[i].Width = 50
+	}
+	return inputs
+}
+
+// launchOptions returns the launch options filled in the create form, nil
+// when there are none
+func (m *Model) launchOptions() *api.LaunchOptions {
+	value := func(field int) string {
+		return strings.TrimSpace(m.launchInputs[field].Value())
+	}
+
+	opts := &api.LaunchOptions{
+		Model:              value(modelField),
+		PermissionMode:     value(permissionModeField),
+		AllowedTools:       splitList(value(allowedToolsField)),
+		DisallowedTools:    splitList(value(disallowedToolsField)),
+		MCPConfig:          value(mcpConfigField),
+		AppendSystemPrompt: value(systemPromptField),
+		AddDirs:            splitList(value(addDirsField)),
+		Args:               strings.Fields(value(argsField)),
+	}
+	if len(opts.CommandArgs()) == 0 {
+		return nil
+	}
+
+	// Claude runs in the project, so paths are made absolute
+	if opts.MCPConfig != "" {
+		opts.MCPConfig, _ = filepath.Abs(opts.MCPConfig)
+	}
+	for i, dir := range opts.AddDirs {
+		opts.AddDirs[i], _ = filepath.Abs(dir)
+	}
+	return opts
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -14,6 +14,11 @@ type sessionsLoadedMsg struct {
 	err      error
 }
 
+// sessionsChangedMsg signals that the multiplexer reported a state change.
+// This message is sent by waitForSessionEventCmd on backends that push
+// events, and triggers a reload of the session list without polling.
+type sessionsChangedMsg struct{}
+
 // sessionCreatedMsg contains the result of creating a new session.
 // This message is sent when the createSessionCmd completes, containing
 // either the newly created session or an error if creation failed.
@@ -30,6 +35,14 @@ type sessionKilledMsg struct {
 	err       error
 }
 
+// sessionResumedMsg contains the result of resuming an inactive session.
+// This message is sent when the resumeSessionCmd completes, containing
+// either the resumed session or an error if it could not be recreated.
+type sessionResumedMsg struct {
+	session *interfaces.Session
+	err     error
+}
+
 // errorMsg contains error information for display in the error view.
 // This message is used to transition the TUI to an error state with
 // user-friendly error display and retry options.
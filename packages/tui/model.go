@@ -36,6 +36,10 @@ type Model struct {
 	// Core dependencies
 	client *api.Client
 
+	// Multiplexer state changes, nil when the backend must be polled
+	sessionEvents     <-chan interfaces.SessionEvent
+	stopSessionEvents func()
+
 	// State management
 	currentView   ViewState
 	errorMessage  string
@@ -54,7 +58,11 @@ type Model struct {
 	nameInput        textinput.Model
 	descriptionInput textinput.Model
 	pathInput        textinput.Model
-	activeInput      int // 0=name, 1=description, 2=path
+	worktreeInput    textinput.Model
+	templateNames    []string          // Session templates of the config
+	templateChoice   int               // 0=no template, else templateNames[templateChoice-1]
+	launchInputs     []textinput.Model // One per launchFields entry
+	activeInput      int               // 0=name, 1=description, 2=path, 3=worktree branch, 4=template, then launch options
 
 	// Kill confirmation state
 	sessionToKill *interfaces.Session
@@ -102,7 +110,10 @@ const (
 	nameInputIndex        = 0
 	descriptionInputIndex = 1
 	pathInputIndex        = 2
-	maxInputIndex         = 2
+	worktreeInputIndex    = 3
+	templateInputIndex    = 4
+	firstLaunchInputIndex = 5
+	maxInputIndex         = firstLaunchInputIndex + len(launchFields) - 1
 )
 
 // NewModel creates a new TUI model with the provided API client.
@@ -129,6 +140,11 @@ func NewModel(client *api.Client) Model {
 	pathInput.CharLimit = 200
 	pathInput.Width = 60
 
+	worktreeInput := textinput.New()
+	worktreeInput.Placeholder = "Branch for a new git worktree (optional)"
+	worktreeInput.CharLimit = 100
+	worktreeInput.Width = 50
+
 	filterInput := textinput.New()
 	filterInput.Placeholder = "Filter sessions (name, status, description...)"
 	filterInput.CharLimit = 100
@@ -139,18 +155,25 @@ func NewModel(client *api.Client) Model {
 	exportFilename.CharLimit = 200
 	exportFilename.Width = 40
 
+	sessionEvents, stopSessionEvents := client.SubscribeSessionEvents()
+
 	return Model{
-		client:           client,
-		currentView:      Loading,
-		keymap:           DefaultKeyMap(),
-		nameInput:        nameInput,
-		descriptionInput: descriptionInput,
-		pathInput:        pathInput,
-		filterInput:      filterInput,
-		activeInput:      nameInputIndex,
-		sessions:         []*interfaces.Session{},
-		isLoading:        false,
-		showHelp:         false,
+		client:            client,
+		sessionEvents:     sessionEvents,
+		stopSessionEvents: stopSessionEvents,
+		currentView:       Loading,
+		keymap:            DefaultKeyMap(),
+		nameInput:         nameInput,
+		descriptionInput:  descriptionInput,
+		pathInput:         pathInput,
+		worktreeInput:     worktreeInput,
+		templateNames:     client.TemplateNames(),
+		launchInputs:      newLaunchInputs(),
+		filterInput:       filterInput,
+		activeInput:       nameInputIndex,
+		sessions:          []*interfaces.Session{},
+		isLoading:         false,
+		showHelp:          false,
 
 		// Initialize advanced table interaction state
 		tablePageSize:     10,
@@ -180,6 +203,9 @@ func NewModel(client *api.Client) Model {
 
 // Init initializes the model and returns a command to load initial session data
 func (m Model) Init() tea.Cmd {
+	if m.sessionEvents != nil {
+		return tea.Batch(loadSessionsCmd(m.client), waitForSessionEventCmd(m.sessionEvents))
+	}
 	return loadSessionsCmd(m.client)
 }
 
@@ -255,7 +281,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.currentView = Error
 			m.errorMessage = msg.err.Error()
 		} else {
-			m.sessions = msg.sessions
+			m.sessions = api.NestSessions(msg.sessions)
 			m.updateTableData()
 			m.lastRefresh = time.Now()
 			if m.currentView == Loading {
@@ -263,6 +289,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case sessionsChangedMsg:
+		// Reload quietly; the multiplexer pushes the next change when it happens
+		cmds = append(cmds, loadSessionsCmd(m.client), waitForSessionEventCmd(m.sessionEvents))
+
 	case sessionCreatedMsg:
 		m.isLoading = false
 		if msg.err != nil {
@@ -288,6 +318,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, loadSessionsCmd(m.client))
 		}
 
+	case sessionResumedMsg:
+		if msg.err != nil {
+			m.currentView = Error
+			m.errorMessage = msg.err.Error()
+		} else {
+			m.statusMessage = fmt.Sprintf("Session %s resumed", msg.session.Name)
+			cmds = append(cmds, loadSessionsCmd(m.client))
+		}
+
 	case errorMsg:
 		m.isLoading = false
 		m.currentView = Error
@@ -323,6 +362,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.descriptionInput, cmd = m.descriptionInput.Update(msg)
 		case pathInputIndex:
 			m.pathInput, cmd = m.pathInput.Update(msg)
+		case worktreeInputIndex:
+			m.worktreeInput, cmd = m.worktreeInput.Update(msg)
+		case templateInputIndex:
+			// The template picker has no text input
+		default:
+			i := m.activeInput - firstLaunchInputIndex
+			m.launchInputs[i], cmd = m.launchInputs[i].Update(msg)
 		}
 		if cmd != nil {
 			cmds = append(cmds, cmd)
@@ -506,6 +552,18 @@ func (m *Model) handleTableViewKeys(msg tea.KeyMsg) tea.Cmd {
 			}
 		}
 
+	case key.Matches(msg, m.keymap.Resume):
+		if len(m.sessions) > 0 {
+			highlightedRow := m.table.GetHighlightedRowIndex()
+			if highlightedRow >= 0 && highlightedRow < len(m.sessions) {
+				session := m.sessions[highlightedRow]
+				if session != nil && session.ID != "" {
+					m.statusMessage = fmt.Sprintf("Resuming %s...", session.Name)
+					return resumeSessionCmd(m.client, session.ID)
+				}
+			}
+		}
+
 	case key.Matches(msg, m.keymap.Refresh):
 		if m.client != nil {
 			m.isLoading = true
@@ -573,15 +631,28 @@ func (m *Model) handleCreatePromptKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keymap.Submit):
 		// Optimize by trimming once and reusing
 		name := strings.TrimSpace(m.nameInput.Value())
-		if name != "" && m.client != nil {
+		template := m.selectedTemplate()
+		if (name != "" || template != "") && m.client != nil {
 			m.isLoading = true
 			m.currentView = Loading
 
 			// Pre-trim all values to avoid repeated operations
 			description := strings.TrimSpace(m.descriptionInput.Value())
 			projectPath := strings.TrimSpace(m.pathInput.Value())
-
-			return createSessionCmd(m.client, name, description, projectPath)
+			branch := strings.TrimSpace(m.worktreeInput.Value())
+
+			req := api.CreateSessionRequest{
+				Name:        name,
+				Description: description,
+				ProjectPath: projectPath,
+				Branch:      branch,
+				Launch:      m.launchOptions(),
+			}
+			if branch != "" {
+				worktree := true
+				req.Worktree = &worktree
+			}
+			return createSessionCmd(m.client, req, template)
 		} else if name == "" {
 			// Show error message for empty name
 			m.statusMessage = "Session name is required"
@@ -592,11 +663,26 @@ func (m *Model) handleCreatePromptKeys(msg tea.KeyMsg) tea.Cmd {
 
 	case key.Matches(msg, m.keymap.PrevInput):
 		m.switchToPrevInput()
+
+	case m.activeInput == templateInputIndex && key.Matches(msg, m.keymap.NextOption):
+		m.templateChoice = (m.templateChoice + 1) % (len(m.templateNames) + 1)
+
+	case m.activeInput == templateInputIndex && key.Matches(msg, m.keymap.PrevOption):
+		m.templateChoice = (m.templateChoice + len(m.templateNames)) % (len(m.templateNames) + 1)
 	}
 
 	return nil
 }
 
+// selectedTemplate returns the name of the template picked in the create
+// form, empty for none
+func (m *Model) selectedTemplate() string {
+	if m.templateChoice == 0 {
+		return ""
+	}
+	return m.templateNames[m.templateChoice-1]
+}
+
 // handleKillConfirmationKeys handles keyboard input in kill confirmation view
 func (m *Model) handleKillConfirmationKeys(msg tea.KeyMsg) tea.Cmd {
 	switch {
@@ -653,15 +739,18 @@ func (m *Model) handleExportViewKeys(msg tea.KeyMsg) tea.Cmd {
 			if session == nil {
 				continue
 			}
-			sessionData = append(sessionData, components.SessionData{
+			data := components.SessionData{
 				ID:          session.ID,
 				Name:        session.Name,
 				Status:      string(session.Status),
+				AgentState:  string(session.AgentState),
 				Backend:     session.Backend,
 				Created:     session.CreatedAt,
 				LastActive:  session.LastActive,
 				ProjectPath: session.ProjectPath,
-			})
+			}
+			data.SetUsage(session.Usage)
+			sessionData = append(sessionData, data)
 		}
 
 		// Return to table view and execute export
@@ -696,16 +785,20 @@ func (m *Model) updateTableData() {
 			continue // Skip nil sessions
 		}
 
-		sessionData = append(sessionData, components.SessionData{
+		data := components.SessionData{
 			ID:          session.ID,
 			Name:        session.Name,
 			Status:      string(session.Status),
+			AgentState:  string(session.AgentState),
 			Backend:     session.Backend,
 			Created:     session.CreatedAt,
 			LastActive:  session.LastActive,
 			ProjectPath: session.ProjectPath,
 			Panes:       session.Panes,
-		})
+			ParentID:    session.ParentID,
+		}
+		data.SetUsage(session.Usage)
+		sessionData = append(sessionData, data)
 	}
 
 	// Apply pagination if enabled
@@ -727,12 +820,21 @@ func (m *Model) resetCreateForm() {
 	m.nameInput.SetValue("")
 	m.descriptionInput.SetValue("")
 	m.pathInput.SetValue("")
+	m.worktreeInput.SetValue("")
+	m.templateChoice = 0
+	for i := range m.launchInputs {
+		m.launchInputs[i].SetValue("")
+	}
 
 	// Reset focus state
 	m.activeInput = nameInputIndex
 	m.nameInput.Focus()
 	m.descriptionInput.Blur()
 	m.pathInput.Blur()
+	m.worktreeInput.Blur()
+	for i := range m.launchInputs {
+		m.launchInputs[i].Blur()
+	}
 
 	// Clear any status messages to prevent memory buildup
 	m.statusMessage = ""
@@ -766,6 +868,10 @@ func (m *Model) blurAllInputs() {
 	m.nameInput.Blur()
 	m.descriptionInput.Blur()
 	m.pathInput.Blur()
+	m.worktreeInput.Blur()
+	for i := range m.launchInputs {
+		m.launchInputs[i].Blur()
+	}
 }
 
 // focusActiveInput sets focus on the currently active input field
@@ -780,6 +886,12 @@ func (m *Model) focusActiveInput() {
 		m.descriptionInput.Focus()
 	case pathInputIndex:
 		m.pathInput.Focus()
+	case worktreeInputIndex:
+		m.worktreeInput.Focus()
+	case templateInputIndex:
+		// The template picker has no text input
+	default:
+		m.launchInputs[m.activeInput-firstLaunchInputIndex].Focus()
 	}
 }
 
@@ -852,6 +964,7 @@ func (m *Model) applySorting() {
 
 		return result
 	})
+	m.sessions = api.NestSessions(m.sessions)
 
 	// Update the table with sorted data
 	m.updateTableData()
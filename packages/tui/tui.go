@@ -22,6 +22,7 @@ func RunTui(client *api.Client) error {
 
 	// Create the main TUI model
 	model := NewModel(client)
+	defer model.stopSessionEvents()
 
 	// Create the Bubbletea program with proper cleanup handling
 	program := tea.NewProgram(
@@ -42,6 +42,7 @@ func renderTableView(m Model) string {
 		b.WriteString(emptyMessage)
 	} else {
 		b.WriteString(m.table.View())
+		b.WriteString(renderHighlightedSessionInfo(m))
 	}
 
 	b.WriteString("\n\n")
@@ -53,6 +54,29 @@ func renderTableView(m Model) string {
 	return b.String()
 }
 
+// renderHighlightedSessionInfo renders details of the highlighted session
+// that do not fit in the table, such as its Claude conversation ID.
+func renderHighlightedSessionInfo(m Model) string {
+	row := m.table.GetHighlightedRowIndex()
+	if row < 0 || row >= len(m.sessions) || m.sessions[row] == nil {
+		return ""
+	}
+
+	session := m.sessions[row]
+	if session.ConversationID == "" {
+		return ""
+	}
+
+	info := "\n" + styles.MutedTextStyle.Render("Conversation: "+session.ConversationID)
+	if usage := session.Usage; usage != nil {
+		info += "\n" + styles.MutedTextStyle.Render(fmt.Sprintf(
+			"Usage: %d input, %d output, %d cache write, %d cache read tokens · $%.2f · %s",
+			usage.InputTokens, usage.OutputTokens, usage.CacheCreationTokens, usage.CacheReadTokens,
+			usage.Cost, usage.Model))
+	}
+	return info
+}
+
 // renderCreateView renders the session creation form with input fields.
 // This view provides a form interface for creating new sessions with
 // name, description, and project path inputs.
@@ -67,7 +91,7 @@ func renderCreateView(m Model) string {
 	// Form fields
 	b.WriteString(styles.BoldStyle.Render("Session Name:"))
 	b.WriteString("\n")
-	if m.activeInput == 0 {
+	if m.activeInput == nameInputIndex {
 		b.WriteString(styles.InputFocusedStyle.Render(m.nameInput.View()))
 	} else {
 		b.WriteString(styles.InputStyle.Render(m.nameInput.View()))
@@ -76,7 +100,7 @@ func renderCreateView(m Model) string {
 
 	b.WriteString(styles.BoldStyle.Render("Description (optional):"))
 	b.WriteString("\n")
-	if m.activeInput == 1 {
+	if m.activeInput == descriptionInputIndex {
 		b.WriteString(styles.InputFocusedStyle.Render(m.descriptionInput.View()))
 	} else {
 		b.WriteString(styles.InputStyle.Render(m.descriptionInput.View()))
@@ -85,16 +109,57 @@ func renderCreateView(m Model) string {
 
 	b.WriteString(styles.BoldStyle.Render("Project Path (optional):"))
 	b.WriteString("\n")
-	if m.activeInput == 2 {
+	if m.activeInput == pathInputIndex {
 		b.WriteString(styles.InputFocusedStyle.Render(m.pathInput.View()))
 	} else {
 		b.WriteString(styles.InputStyle.Render(m.pathInput.View()))
 	}
 	b.WriteString("\n\n")
 
+	b.WriteString(styles.BoldStyle.Render("Worktree Branch (optional):"))
+	b.WriteString("\n")
+	if m.activeInput == worktreeInputIndex {
+		b.WriteString(styles.InputFocusedStyle.Render(m.worktreeInput.View()))
+	} else {
+		b.WriteString(styles.InputStyle.Render(m.worktreeInput.View()))
+	}
+	b.WriteString("\n\n")
+
+	// The picker cycles through the templates with the arrow keys
+	b.WriteString(styles.BoldStyle.Render("Template (optional):"))
+	b.WriteString("\n")
+	template := m.selectedTemplate()
+	switch {
+	case len(m.templateNames) == 0:
+		template = styles.MutedTextStyle.Render("none configured")
+	case template == "":
+		template = styles.MutedTextStyle.Render("none")
+	}
+	if m.activeInput == templateInputIndex {
+		b.WriteString(styles.HighlightStyle.Render("◀ ") + template + styles.HighlightStyle.Render(" ▶"))
+	} else {
+		b.WriteString("  " + template)
+	}
+	b.WriteString("\n\n")
+
+	// Launch options take a line each to keep the form short
+	b.WriteString(styles.BoldStyle.Render("Claude Options (optional):"))
+	b.WriteString("\n")
+	for i, field := range launchFields {
+		label := fmt.Sprintf("%-17s", field.label+":")
+		if m.activeInput == firstLaunchInputIndex+i {
+			label = styles.HighlightStyle.Render(label)
+		} else {
+			label = styles.MutedTextStyle.Render(label)
+		}
+		b.WriteString(label + " " + m.launchInputs[i].View() + "\n")
+	}
+	b.WriteString("\n")
+
 	// Instructions
 	instructions := []string{
 		"Tab/Shift+Tab: Navigate fields",
+		"←/→: Pick template",
 		"Enter: Create session",
 		"Esc: Cancel",
 	}
@@ -246,6 +311,7 @@ func renderFooter(m Model) string {
 				"Enter: Attach",
 				"c: Create",
 				"k: Kill",
+				"R: Resume",
 				"/: Filter",
 				"space: Select",
 				"r: Refresh",